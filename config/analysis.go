@@ -0,0 +1,26 @@
+package config
+
+import (
+	"github.com/anurag-327/neuron/internal/models"
+	"github.com/anurag-327/neuron/pkg/analysis"
+)
+
+// AnalysisPolicy is the static analysis policy applied to submissions
+// of each plan. PolicyOff leaves isolation to the sandbox alone.
+var AnalysisPolicy = map[models.PlanType]analysis.Policy{
+	models.PlanTypeFree:       analysis.PolicyStrict,
+	models.PlanTypePro:        analysis.PolicyStandard,
+	models.PlanTypeEnterprise: analysis.PolicyOff,
+}
+
+// CheckerAnalysisPolicy is the policy applied to custom checkers. It does
+// not follow the plan of the account: checkers run with file access to
+// read the output and answer of each test.
+var CheckerAnalysisPolicy = analysis.PolicyStandard
+
+func GetAnalysisPolicy(plan models.PlanType) analysis.Policy {
+	if v, ok := AnalysisPolicy[plan]; ok {
+		return v
+	}
+	return analysis.PolicyStrict
+}
@@ -0,0 +1,14 @@
+package config
+
+// Contest defaults and limits.
+var (
+	ContestMaxProblems = 26
+
+	// ContestPenaltyMinutes is the ICPC penalty per rejected attempt
+	// when a contest does not set one.
+	ContestPenaltyMinutes int64 = 20
+
+	// ContestProblemPoints is the IOI score of a problem when a contest
+	// does not set one.
+	ContestProblemPoints int64 = 100
+)
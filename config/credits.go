@@ -1,10 +1,24 @@
 package config
 
-import "github.com/anurag-327/neuron/internal/models"
+import (
+	"github.com/anurag-327/neuron/internal/models"
+	"github.com/anurag-327/neuron/internal/registry"
+)
 
+// CreditPricing is the price of each debit. Submissions in a language
+// with a creditCost are billed that instead (see GetJobCredits).
+// Interactive sessions are billed CreditReasonSession per started minute.
+//
+// Results served from the result cache are billed
+// CreditReasonCachedResult, which must stay below the cheapest
+// submission. Credits are whole numbers and a submission can cost 1, so
+// cached results are free: a cache hit runs nothing, and charging for it
+// would make "cache": true cost as much as running the program again.
 var CreditPricing = map[models.CreditTransactionReason]int64{
-	models.CreditReasonSubmission: 1,
-	models.CreditReasonRerun:      1,
+	models.CreditReasonSubmission:   1,
+	models.CreditReasonRerun:        1,
+	models.CreditReasonSession:      1,
+	models.CreditReasonCachedResult: 0,
 }
 
 func GetCreditsForReason(reason models.CreditTransactionReason) int64 {
@@ -13,3 +27,13 @@ func GetCreditsForReason(reason models.CreditTransactionReason) int64 {
 	}
 	return 0
 }
+
+// GetJobCredits is the price of a job of reason in langCfg: a submission
+// costs the language's creditCost, when it sets one, and every other
+// reason its flat price.
+func GetJobCredits(reason models.CreditTransactionReason, langCfg registry.LanguageConfig) int64 {
+	if reason == models.CreditReasonSubmission && langCfg.CreditCost > 0 {
+		return langCfg.CreditCost
+	}
+	return GetCreditsForReason(reason)
+}
@@ -0,0 +1,45 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/anurag-327/neuron/internal/models"
+	"github.com/anurag-327/neuron/internal/registry"
+)
+
+func TestGetJobCredits(t *testing.T) {
+	priced := registry.LanguageConfig{Name: "java", CreditCost: 7}
+	unpriced := registry.LanguageConfig{Name: "c"}
+
+	tests := []struct {
+		name    string
+		reason  models.CreditTransactionReason
+		langCfg registry.LanguageConfig
+		want    int64
+	}{
+		{"submission with a language price", models.CreditReasonSubmission, priced, 7},
+		{"submission without a language price", models.CreditReasonSubmission, unpriced, CreditPricing[models.CreditReasonSubmission]},
+		{"rerun keeps its flat price", models.CreditReasonRerun, priced, CreditPricing[models.CreditReasonRerun]},
+		{"session keeps its flat price", models.CreditReasonSession, priced, CreditPricing[models.CreditReasonSession]},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := GetJobCredits(tt.reason, tt.langCfg); got != tt.want {
+				t.Errorf("GetJobCredits = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCachedResultPricing(t *testing.T) {
+	cached := GetCreditsForReason(models.CreditReasonCachedResult)
+	submission := GetCreditsForReason(models.CreditReasonSubmission)
+	if cached < 0 || cached >= submission {
+		t.Errorf("cached result costs %d, want from 0 to below the submission price %d", cached, submission)
+	}
+	// A language can price submissions at 1, the least a run can cost
+	cheapest := GetJobCredits(models.CreditReasonSubmission, registry.LanguageConfig{CreditCost: 1})
+	if cached >= cheapest {
+		t.Errorf("cached result costs %d, not less than the cheapest submission %d", cached, cheapest)
+	}
+}
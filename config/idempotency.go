@@ -0,0 +1,15 @@
+package config
+
+import "time"
+
+// IdempotencyKeyTTL is how long an Idempotency-Key is remembered after
+// the job it submitted was queued.
+var IdempotencyKeyTTL = 24 * time.Hour
+
+// IdempotencyPendingTTL is how long an Idempotency-Key stays reserved
+// while its first request is in progress. A request that dies midway
+// frees its key after this.
+var IdempotencyPendingTTL = time.Minute
+
+// IdempotencyKeyMaxLength is the longest Idempotency-Key accepted.
+const IdempotencyKeyMaxLength = 255
@@ -0,0 +1,10 @@
+package config
+
+// MaxOutputSize is how much of each of stdout and stderr of a run is
+// kept; the rest is cut off.
+const MaxOutputSize = 16 * 1024 * 1024
+
+// InlineOutputSize is the largest output kept in the job document.
+// Larger outputs are stored in the object store, and the document keeps
+// only their beginning.
+const InlineOutputSize = 64 * 1024
@@ -0,0 +1,14 @@
+package config
+
+// Problem limits. Tests are stored in the problem document, so their
+// total size must stay well below MongoDB's 16MB document limit.
+var (
+	ProblemMaxTitleLength   = 200
+	ProblemMaxStatementSize = 64 * 1024
+	ProblemMaxTests         = 100
+	ProblemMaxTestDataSize  = 8 * 1024 * 1024
+
+	// ProblemMaxTimeLimitMs caps the per-test time limit of a problem;
+	// the runtime's own limit applies when it is lower.
+	ProblemMaxTimeLimitMs int64 = 10_000
+)
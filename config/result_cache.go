@@ -0,0 +1,7 @@
+package config
+
+import "time"
+
+// ResultCacheTTL is how long the result of a run is served to identical
+// submissions that opt into the result cache.
+var ResultCacheTTL = 24 * time.Hour
@@ -0,0 +1,41 @@
+package config
+
+import (
+	"time"
+
+	"github.com/anurag-327/neuron/internal/models"
+)
+
+// Retention is how long jobs and API logs are kept. After StripAfter
+// their large fields (code, input, output, request bodies) are removed,
+// and after DeleteAfter the documents themselves; both are archived
+// first. Zero keeps them forever. Problem and contest jobs are only
+// stripped, and the latest run of each snippet is kept whole.
+type Retention struct {
+	StripAfter  time.Duration
+	DeleteAfter time.Duration
+}
+
+const day = 24 * time.Hour
+
+// RetentionPolicy is the retention of the jobs and API logs of users of
+// each plan.
+var RetentionPolicy = map[models.PlanType]Retention{
+	models.PlanTypeFree:       {StripAfter: 7 * day, DeleteAfter: 30 * day},
+	models.PlanTypePro:        {StripAfter: 30 * day, DeleteAfter: 180 * day},
+	models.PlanTypeEnterprise: {StripAfter: 90 * day, DeleteAfter: 365 * day},
+}
+
+func GetRetention(plan models.PlanType) Retention {
+	if v, ok := RetentionPolicy[plan]; ok {
+		return v
+	}
+	return RetentionPolicy[models.PlanTypeFree]
+}
+
+// RetentionInterval is how often the retention purger runs.
+var RetentionInterval = time.Hour
+
+// RetentionBatchSize is how many documents the purger archives at once,
+// i.e. the most documents in one archive file.
+const RetentionBatchSize = 1000
@@ -0,0 +1,93 @@
+package config
+
+import (
+	"sort"
+	"time"
+
+	"github.com/anurag-327/neuron/internal/registry"
+)
+
+// SandboxPoolConfig defines configuration for a single runtime pool.
+//
+// A pool serves exactly one language version, optionally with a
+// library set; (Language, Version, Library) matches an entry in the
+// language registry.
+type SandboxPoolConfig struct {
+	Language string
+	Version  string
+	Library  string
+
+	// Backend is the sandbox backend serving the pool; Runtime,
+	// Seccomp and AppArmor tune it (see registry.SandboxSettings).
+	Backend  string
+	Runtime  string
+	Seccomp  string
+	AppArmor string
+
+	Image           string
+	InitSize        int
+	MaxSize         int
+	MemoryLimit     int64
+	TmpfsSize       int64
+	IdleMemoryLimit int64
+	HealthCmd       []string
+	HealthInterval  time.Duration
+	WarmupCmd       []string
+}
+
+// SandboxPools returns the pool configurations served by a backend.
+//
+// Pools are derived from the loaded language definitions, which are
+// the single source of truth for all supported runtimes and their
+// pool behavior. Library set variants get pools of their own.
+// Versions without a pool block are skipped.
+func SandboxPools(backend string) []SandboxPoolConfig {
+	var pools []SandboxPoolConfig
+
+	for name, langCfg := range registry.LanguageRegistry {
+		for _, v := range langCfg.VersionNames() {
+			pools = appendPool(pools, backend, name, v, "", langCfg.Versions[v])
+		}
+		for _, lib := range langCfg.LibraryNames() {
+			libCfg := langCfg.Libraries[lib]
+			for v, versionCfg := range libCfg.Versions {
+				pools = appendPool(pools, backend, name, v, lib, versionCfg)
+			}
+		}
+	}
+
+	sort.Slice(pools, func(i, j int) bool {
+		if pools[i].Language != pools[j].Language {
+			return pools[i].Language < pools[j].Language
+		}
+		if pools[i].Version != pools[j].Version {
+			return pools[i].Version < pools[j].Version
+		}
+		return pools[i].Library < pools[j].Library
+	})
+	return pools
+}
+
+func appendPool(pools []SandboxPoolConfig, backend, language, version, library string, versionCfg registry.VersionConfig) []SandboxPoolConfig {
+	if versionCfg.Pool == nil || versionCfg.Sandbox.Backend != backend {
+		return pools
+	}
+	return append(pools, SandboxPoolConfig{
+		Language:        language,
+		Version:         version,
+		Library:         library,
+		Backend:         versionCfg.Sandbox.Backend,
+		Runtime:         versionCfg.Sandbox.Runtime,
+		Seccomp:         versionCfg.Sandbox.Seccomp,
+		AppArmor:        versionCfg.Sandbox.AppArmor,
+		Image:           versionCfg.DockerImage,
+		InitSize:        versionCfg.Pool.InitSize,
+		MaxSize:         versionCfg.Pool.MaxSize,
+		MemoryLimit:     versionCfg.Pool.GetMemoryLimit(),
+		TmpfsSize:       versionCfg.Pool.GetTmpfsSize(),
+		IdleMemoryLimit: versionCfg.Pool.GetIdleMemoryLimit(),
+		HealthCmd:       versionCfg.Pool.HealthCmd,
+		HealthInterval:  versionCfg.Pool.HealthInterval,
+		WarmupCmd:       versionCfg.Pool.WarmupCmd,
+	})
+}
@@ -0,0 +1,22 @@
+package config
+
+import "time"
+
+// Interactive session limits. A session's wall-clock limit is lowered
+// to the minutes the account balance pays for, which are reserved when
+// it starts (see CreditReasonSession).
+var (
+	// SessionWallLimit caps the total duration of a session.
+	SessionWallLimit = 10 * time.Minute
+
+	// SessionIdleLimit ends sessions without input or output.
+	SessionIdleLimit = 2 * time.Minute
+
+	// SessionJoinWindow is how long a created session waits for its
+	// WebSocket before it expires.
+	SessionJoinWindow = time.Minute
+
+	// SessionAcquireTimeout bounds the wait for a free sandbox, so
+	// sessions fail fast instead of queueing behind batch jobs.
+	SessionAcquireTimeout = 10 * time.Second
+)
@@ -0,0 +1,15 @@
+package config
+
+// Similarity check limits.
+var (
+	// SimilarityMaxJobs bounds the jobs compared in one request.
+	SimilarityMaxJobs = 500
+
+	// SimilarityMinShare is the share of code two jobs must have in
+	// common to be reported when a request does not set one.
+	SimilarityMinShare = 0.3
+
+	// SimilarityCommonShare is the share of compared jobs above which
+	// shared code is treated as boilerplate and ignored.
+	SimilarityCommonShare = 0.5
+)
@@ -0,0 +1,8 @@
+package config
+
+// Snippet limits.
+var (
+	SnippetMaxNameLength = 100
+	SnippetMaxCodeSize   = 64 * 1024
+	SnippetMaxInputSize  = 1024 * 1024
+)
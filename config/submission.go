@@ -0,0 +1,25 @@
+package config
+
+import "strings"
+
+// SubmissionEnv are the environment variables a submission may set.
+// Anything else must use SubmissionEnvPrefix, so submissions can never
+// override PATH, HOME, LD_PRELOAD or runtime options such as
+// JAVA_TOOL_OPTIONS and NODE_OPTIONS.
+var SubmissionEnv = map[string]bool{
+	"TZ":             true,
+	"LANG":           true,
+	"LC_ALL":         true,
+	"DEBUG":          true,
+	"NODE_ENV":       true,
+	"PYTHONHASHSEED": true,
+	"RUST_BACKTRACE": true,
+}
+
+// SubmissionEnvPrefix marks application variables submissions may set
+// freely, e.g. APP_MODE.
+const SubmissionEnvPrefix = "APP_"
+
+func IsSubmissionEnvAllowed(name string) bool {
+	return SubmissionEnv[name] || (strings.HasPrefix(name, SubmissionEnvPrefix) && len(name) > len(SubmissionEnvPrefix))
+}
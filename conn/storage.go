@@ -0,0 +1,90 @@
+package conn
+
+import (
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/anurag-327/neuron/pkg/storage"
+	"github.com/kamva/mgm/v3"
+)
+
+// DefaultStorageDir is where the local storage backend keeps objects when
+// STORAGE_DIR is not set. It is relative to the process working directory.
+const DefaultStorageDir = "data/storage"
+
+var (
+	storageOnce sync.Once
+	objectStore storage.Store
+	storageErr  error
+)
+
+// GetStorage returns the singleton object store selected by
+// STORAGE_BACKEND: "gridfs" (default) keeps objects in MongoDB, "local"
+// below STORAGE_DIR.
+//
+// With GridFS, MongoDB must be connected before the first call.
+func GetStorage() (storage.Store, error) {
+	storageOnce.Do(func() {
+		switch backend := os.Getenv("STORAGE_BACKEND"); backend {
+		case "", "gridfs":
+			_, _, db, err := mgm.DefaultConfigs()
+			if err != nil {
+				storageErr = err
+				return
+			}
+			objectStore, storageErr = storage.NewGridFS(db, "storage")
+		case "local":
+			dir := os.Getenv("STORAGE_DIR")
+			if dir == "" {
+				dir = DefaultStorageDir
+			}
+			objectStore, storageErr = storage.NewLocal(dir)
+		default:
+			storageErr = fmt.Errorf("unsupported storage backend: %s", backend)
+		}
+	})
+
+	return objectStore, storageErr
+}
+
+// DefaultArchiveDir is where the local archive backend writes archives
+// when ARCHIVE_DIR is not set.
+const DefaultArchiveDir = "data/archive"
+
+var (
+	archiveOnce  sync.Once
+	archiveStore storage.Store
+	archiveErr   error
+)
+
+// GetArchiveStorage returns the singleton store that data retention
+// archives documents to before removing them, selected by
+// ARCHIVE_BACKEND: "local" (default) below ARCHIVE_DIR, "s3" in the
+// ARCHIVE_S3_BUCKET of an S3-compatible service, or "none" to remove
+// documents without archiving them, in which case the store is nil.
+func GetArchiveStorage() (storage.Store, error) {
+	archiveOnce.Do(func() {
+		switch backend := os.Getenv("ARCHIVE_BACKEND"); backend {
+		case "", "local":
+			dir := os.Getenv("ARCHIVE_DIR")
+			if dir == "" {
+				dir = DefaultArchiveDir
+			}
+			archiveStore, archiveErr = storage.NewLocal(dir)
+		case "s3":
+			archiveStore, archiveErr = storage.NewS3(storage.S3Config{
+				Endpoint:  os.Getenv("ARCHIVE_S3_ENDPOINT"),
+				Bucket:    os.Getenv("ARCHIVE_S3_BUCKET"),
+				Region:    os.Getenv("ARCHIVE_S3_REGION"),
+				AccessKey: os.Getenv("ARCHIVE_S3_ACCESS_KEY"),
+				SecretKey: os.Getenv("ARCHIVE_S3_SECRET_KEY"),
+			})
+		case "none":
+		default:
+			archiveErr = fmt.Errorf("unsupported archive backend: %s", backend)
+		}
+	})
+
+	return archiveStore, archiveErr
+}
@@ -2,34 +2,28 @@
 
 echo "🚀 Starting Neuron VPS Initialization..."
 
-# 1. Create the temporary runner directory (Fixes 'bind source path does not exist')
-echo "📂 Creating runner temp directory..."
-mkdir -p /var/www/neuron/backend/tmp/runner
-# Ensure current user owns it
-sudo chown -R $USER:$USER /var/www/neuron/backend/tmp/runner
-echo "✅ Directory created."
-
-# 2. Pull all required Docker Images
+# 1. Build Neuron runtime images (images/<name>/Dockerfile)
+echo "🔨 Building runtime images..."
+docker build -t neuron-kotlin:2.0 images/kotlin
+docker build -t neuron-typescript:5.6 images/typescript
+docker build -t neuron-python-datascience:3.12 images/python-datascience
+docker build -t neuron-javascript-utils:22 images/javascript-utils
+docker build -t neuron-cpp-boost:latest images/cpp-boost
+
+# 2. Load the sandbox AppArmor profile (skipped on hosts without AppArmor)
+if [ "$(cat /sys/module/apparmor/parameters/enabled 2>/dev/null)" = "Y" ]; then
+  echo "🛡️  Loading AppArmor profile..."
+  sudo apparmor_parser -r -W security/apparmor/neuron-sandbox
+fi
+
+# 3. Pull all stock images referenced by language definitions
 echo "🐳 Pulling Docker images..."
-
-# Python
-echo "   - Pulling Python..."
-docker pull python:3.12-alpine
-
-# C++ (GCC)
-echo "   - Pulling GCC..."
-docker pull gcc:latest
-
-# Java (Example - based on common registry patterns)
-echo "   - Pulling OpenJDK..."
-docker pull openjdk:17-alpine
-
-# Node.js (Example)
-echo "   - Pulling Node..."
-docker pull node:18-alpine
-
-# Go (Example)
-echo "   - Pulling Go..."
-docker pull golang:1.22-alpine
+for image in $(grep -h '^ *image:' languages/*.yaml | awk '{print $2}' | sort -u); do
+  case "$image" in
+    neuron-*) continue ;; # built above
+  esac
+  echo "   - Pulling $image..."
+  docker pull "$image"
+done
 
 echo "🎉 Initialization Complete! You can now run the worker."
\ No newline at end of file
@@ -0,0 +1,31 @@
+package dto
+
+import "time"
+
+type ContestBody struct {
+	Title       string `json:"title" binding:"required"`
+	Description string `json:"description"`
+
+	// Mode is "icpc" or "ioi".
+	Mode    string    `json:"mode" binding:"required"`
+	StartAt time.Time `json:"startAt" binding:"required"`
+	EndAt   time.Time `json:"endAt" binding:"required"`
+
+	// FreezeAt optionally freezes the scoreboard before EndAt.
+	FreezeAt *time.Time `json:"freezeAt"`
+
+	// PenaltyMinutes per rejected attempt in ICPC mode; defaults to
+	// config.ContestPenaltyMinutes.
+	PenaltyMinutes *int64 `json:"penaltyMinutes"`
+
+	Problems []ContestProblemBody `json:"problems" binding:"required"`
+}
+
+type ContestProblemBody struct {
+	// Label defaults to the problem's letter: A, B, C...
+	Label     string `json:"label"`
+	ProblemID string `json:"problemId" binding:"required"`
+
+	// Points in IOI mode; defaults to config.ContestProblemPoints.
+	Points int64 `json:"points"`
+}
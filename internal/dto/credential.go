@@ -0,0 +1,5 @@
+package dto
+
+type SetCredentialLibrariesBody struct {
+	Libraries []string `json:"libraries"`
+}
@@ -1,7 +1,89 @@
-package dto
-
-type SubmitCodeBody struct {
-	Code     string `json:"code" binding:"required"`
-	Language string `json:"language" binding:"required"`
-	Input    string `json:"input"`
-}
+package dto
+
+type SubmitCodeBody struct {
+	Code     string `json:"code" binding:"required"`
+	Language string `json:"language" binding:"required"`
+	Version  string `json:"version"`
+	Library  string `json:"library"`
+	Input    string `json:"input"`
+
+	// Artifacts are globs of files to keep from the working directory
+	// after the run, e.g. ["*.png", "out/*.csv"].
+	Artifacts []string `json:"artifacts"`
+
+	// Args are the program arguments, Env extra environment variables
+	// (see config.SubmissionEnv) and Files read-only data files placed
+	// next to the code.
+	Args  []string          `json:"args"`
+	Env   map[string]string `json:"env"`
+	Files []DataFile        `json:"files"`
+
+	// Interactor is only decoded to reject it: interactors belong to
+	// problems (see ProblemBody.Interactor), so submitters cannot judge
+	// their own runs.
+	Interactor string `json:"interactor"`
+
+	// Checker judges stdout against ExpectedOutput after a successful
+	// run (see models.Checker).
+	ExpectedOutput string       `json:"expectedOutput"`
+	Checker        *CheckerBody `json:"checker"`
+
+	// ProblemID runs the code against the tests of a stored problem
+	// instead of Input; it excludes Input, ExpectedOutput, Checker and
+	// Artifacts.
+	ProblemID string `json:"problemId"`
+
+	// ContestID submits ProblemID to a running contest the user is
+	// registered for.
+	ContestID string `json:"contestId"`
+
+	// Cache serves the result of an earlier identical run, if any, and
+	// stores the result of this one for later runs. Only for
+	// deterministic programs; it excludes ProblemID, ContestID and
+	// Artifacts.
+	Cache bool `json:"cache"`
+}
+
+type CheckerBody struct {
+	// Type is "exact", "tokens", "float", "unordered-lines" or "custom".
+	Type    string  `json:"type" binding:"required"`
+	Epsilon float64 `json:"epsilon"`
+
+	// Code of a custom checker, in Language (defaults to the language
+	// of the submission) and Version.
+	Language string `json:"language"`
+	Version  string `json:"version"`
+	Code     string `json:"code"`
+}
+
+type DataFile struct {
+	Name    string `json:"name" binding:"required"`
+	Content string `json:"content"`
+
+	// Encoding is "utf8" (the default) or "base64" for binary content.
+	Encoding string `json:"encoding"`
+}
+
+// RerunBody overrides parts of the job being rerun.
+type RerunBody struct {
+	// Input replaces the input of the job; it cannot be set on problem
+	// jobs, which run against the problem's tests.
+	Input *string `json:"input"`
+
+	// TimeLimitMs lowers the time limit of the runtime.
+	TimeLimitMs int64 `json:"timeLimitMs"`
+}
+
+// ListJobsQuery filters the jobs listed by GET /jobs. From and To are
+// RFC 3339 times bounding when a job was submitted; Cursor is the
+// nextCursor of the previous page.
+type ListJobsQuery struct {
+	Status    string `form:"status"`
+	Language  string `form:"language"`
+	ErrorType string `form:"errorType"`
+	From      string `form:"from"`
+	To        string `form:"to"`
+	Q         string `form:"q"`
+	Cursor    string `form:"cursor"`
+	Limit     int64  `form:"limit"`
+}
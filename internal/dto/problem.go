@@ -0,0 +1,27 @@
+package dto
+
+type ProblemBody struct {
+	Title       string   `json:"title" binding:"required"`
+	Statement   string   `json:"statement"`
+	TimeLimitMs int64    `json:"timeLimitMs"`
+	Languages   []string `json:"languages"`
+
+	// Samples are public; Tests are hidden from submitters.
+	Samples []TestCaseBody `json:"samples"`
+	Tests   []TestCaseBody `json:"tests"`
+
+	// Checker defaults to the exact checker. Interactor makes the
+	// problem interactive and excludes Checker.
+	Checker    *CheckerBody    `json:"checker"`
+	Interactor *InteractorBody `json:"interactor"`
+}
+
+type InteractorBody struct {
+	Language string `json:"language" binding:"required"`
+	Code     string `json:"code" binding:"required"`
+}
+
+type TestCaseBody struct {
+	Input          string `json:"input"`
+	ExpectedOutput string `json:"expectedOutput"`
+}
@@ -0,0 +1,12 @@
+package dto
+
+type CreateSessionBody struct {
+	Code     string `json:"code" binding:"required"`
+	Language string `json:"language" binding:"required"`
+	Version  string `json:"version"`
+	Library  string `json:"library"`
+
+	Args  []string          `json:"args"`
+	Env   map[string]string `json:"env"`
+	Files []DataFile        `json:"files"`
+}
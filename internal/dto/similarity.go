@@ -0,0 +1,13 @@
+package dto
+
+// SimilarityBody selects the jobs to compare: either JobIDs, or the
+// latest submission of each user to ProblemID.
+type SimilarityBody struct {
+	JobIDs    []string `json:"jobIds"`
+	ProblemID string   `json:"problemId"`
+
+	// MinShare is the share of code a pair must have in common to be
+	// reported, between 0 and 1.
+	MinShare *float64 `json:"minShare"`
+	Limit    int      `json:"limit"`
+}
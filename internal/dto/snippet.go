@@ -0,0 +1,23 @@
+package dto
+
+import "time"
+
+type SnippetBody struct {
+	Name     string `json:"name" binding:"required"`
+	Language string `json:"language" binding:"required"`
+	Version  string `json:"version"`
+	Code     string `json:"code" binding:"required"`
+	Input    string `json:"input"`
+}
+
+type ForkSnippetBody struct {
+	// Name defaults to the name of the forked snippet; Revision to its
+	// latest revision.
+	Name     string `json:"name"`
+	Revision int    `json:"revision"`
+}
+
+type ShareSnippetBody struct {
+	// ExpiresAt is when the link stops working; it never does when unset.
+	ExpiresAt *time.Time `json:"expiresAt"`
+}
@@ -1,34 +1,96 @@
-package factory
-
-import (
-	"log"
-	"sync"
-
-	"github.com/anurag-327/neuron/conn"
-	"github.com/anurag-327/neuron/pkg/sandbox"
-	"github.com/anurag-327/neuron/pkg/sandbox/docker"
-)
-
-var (
-	runnerInstance     sandbox.Runner
-	onceRunnerInstance sync.Once
-)
-
-func GetRunner() sandbox.Runner {
-	onceRunnerInstance.Do(func() {
-		client, err := conn.GetDockerClient()
-		if err != nil {
-			log.Fatalf("Failed to init docker client: %v", err)
-		}
-		runnerInstance = docker.NewRunner(client)
-	})
-	return runnerInstance
-}
-
-func GetRunnerHealth() error {
-	r := GetRunner()
-	if r == nil {
-		return nil // or error if it should be initialized
-	}
-	return r.Health()
-}
+package factory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/anurag-327/neuron/conn"
+	"github.com/anurag-327/neuron/internal/registry"
+	"github.com/anurag-327/neuron/pkg/sandbox"
+	"github.com/anurag-327/neuron/pkg/sandbox/docker"
+	"github.com/anurag-327/neuron/pkg/sandbox/process"
+)
+
+var (
+	runnerInstances    []sandbox.Runner
+	runnerErr          error
+	onceRunnerInstance sync.Once
+)
+
+// GetRunners returns one sandbox backend for every backend used by the
+// loaded language definitions, registering each with the sandbox package.
+func GetRunners() ([]sandbox.Runner, error) {
+	onceRunnerInstance.Do(func() {
+		for _, backend := range registry.BackendsInUse() {
+			r, err := newRunner(backend)
+			if err != nil {
+				runnerErr = fmt.Errorf("failed to init %s sandbox: %w", backend, err)
+				return
+			}
+			sandbox.RegisterRunner(r)
+			runnerInstances = append(runnerInstances, r)
+		}
+	})
+
+	if runnerErr != nil {
+		return nil, runnerErr
+	}
+	return runnerInstances, nil
+}
+
+func newRunner(backend string) (sandbox.Runner, error) {
+	switch backend {
+	case registry.BackendDocker:
+		client, err := conn.GetDockerClient()
+		if err != nil {
+			return nil, err
+		}
+		return docker.NewRunner(client), nil
+	case registry.BackendProcess:
+		return process.NewRunner(os.Getenv("PROCESS_SANDBOX_TOOL"), os.Getenv("PROCESS_SANDBOX_DIR"))
+	default:
+		return nil, fmt.Errorf("unsupported sandbox backend: %s", backend)
+	}
+}
+
+// InitRunners creates and warms up every sandbox backend in use.
+//
+// This function should be invoked once during worker startup.
+func InitRunners(ctx context.Context) error {
+	runners, err := GetRunners()
+	if err != nil {
+		return err
+	}
+	for _, r := range runners {
+		if err := r.Init(ctx); err != nil {
+			return fmt.Errorf("%s sandbox: %w", r.Name(), err)
+		}
+	}
+	return nil
+}
+
+// ShutdownRunners releases the resources of every sandbox backend.
+func ShutdownRunners() {
+	runners, _ := GetRunners()
+	for _, r := range runners {
+		r.Shutdown()
+	}
+}
+
+func GetRunnerHealth() error {
+	runners, err := GetRunners()
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, r := range runners {
+		if err := r.Health(); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", r.Name(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
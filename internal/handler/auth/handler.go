@@ -96,6 +96,7 @@ func GoogleLoginInController(c *gin.Context) {
 				Email:        email,
 				Password:     hashedPassword,
 				Role:         models.RoleTypeUser,
+				Plan:         models.PlanTypeFree,
 				Verified:     verified,
 				AuthProvider: string(models.AuthProviderGoogle),
 				Username:     strings.Split(email, "@")[0],
@@ -187,6 +188,7 @@ func GithubLoginInController(c *gin.Context) {
 				Email:        email,
 				Password:     hashedPassword,
 				Role:         models.RoleTypeUser,
+				Plan:         models.PlanTypeFree,
 				Verified:     verified,
 				AuthProvider: string(models.AuthProviderGoogle),
 				Username:     strings.Split(email, "@")[0],
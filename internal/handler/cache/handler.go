@@ -0,0 +1,33 @@
+package cacheHandler
+
+import (
+	"net/http"
+
+	"github.com/anurag-327/neuron/internal/registry"
+	"github.com/anurag-327/neuron/internal/repository"
+	"github.com/anurag-327/neuron/internal/util/response"
+	"github.com/gin-gonic/gin"
+)
+
+// InvalidateResultCacheHandler drops the cached results of a language,
+// or of one of its versions with ?version=, e.g. after its runtime image
+// was rebuilt under the same tag
+func InvalidateResultCacheHandler(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	language := c.Query("language")
+	if _, ok := registry.LanguageRegistry[language]; !ok {
+		response.Error(c, http.StatusBadRequest, "language not supported")
+		return
+	}
+
+	deleted, err := repository.DeleteCachedResults(ctx, language, c.Query("version"))
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, "result cache invalidated successfully", gin.H{
+		"deleted": deleted,
+	})
+}
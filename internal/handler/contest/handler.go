@@ -0,0 +1,281 @@
+package contestHandler
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/anurag-327/neuron/internal/dto"
+	"github.com/anurag-327/neuron/internal/models"
+	"github.com/anurag-327/neuron/internal/repository"
+	"github.com/anurag-327/neuron/internal/services"
+	"github.com/anurag-327/neuron/internal/util"
+	"github.com/anurag-327/neuron/internal/util/response"
+	"github.com/gin-gonic/gin"
+)
+
+// CreateContestHandler creates a contest authored by the user
+func CreateContestHandler(c *gin.Context) {
+	ctx := c.Request.Context()
+	user, err := util.GetUserFromContext(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var body dto.ContestBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	contest, err := services.CreateContest(ctx, user, body)
+	if err != nil {
+		contestError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "contest created successfully", gin.H{
+		"contest": contestView(contest, user),
+	})
+}
+
+// ListContestsHandler lists contests, the latest start first
+func ListContestsHandler(c *gin.Context) {
+	ctx := c.Request.Context()
+	user, err := util.GetUserFromContext(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	page, limit := util.GetPageAndLimitFromQuery(c)
+
+	contests, err := repository.ListContests(ctx, page, limit)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	views := make([]gin.H, 0, len(contests))
+	for i := range contests {
+		view := contestView(&contests[i], user)
+		delete(view, "description")
+		delete(view, "problems")
+		views = append(views, view)
+	}
+
+	response.Success(c, http.StatusOK, "contests fetched successfully", gin.H{
+		"contests": views,
+		"page":     page,
+		"limit":    limit,
+	})
+}
+
+// GetContestHandler returns a contest; its problems are listed once it
+// has started, or to its author
+func GetContestHandler(c *gin.Context) {
+	ctx := c.Request.Context()
+	user, err := util.GetUserFromContext(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	objID, err := util.IsValidObjectID(c.Param("contestId"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid Contest ID")
+		return
+	}
+
+	contest, err := repository.GetContestByID(ctx, objID)
+	if err != nil {
+		contestError(c, err)
+		return
+	}
+
+	registered, err := repository.IsContestParticipant(ctx, contest.ID, user.ID)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	view := contestView(contest, user)
+	view["registered"] = registered
+	response.Success(c, http.StatusOK, "contest fetched successfully", gin.H{
+		"contest": view,
+	})
+}
+
+// UpdateContestHandler replaces a contest of the user
+func UpdateContestHandler(c *gin.Context) {
+	ctx := c.Request.Context()
+	user, err := util.GetUserFromContext(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	objID, err := util.IsValidObjectID(c.Param("contestId"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid Contest ID")
+		return
+	}
+
+	var body dto.ContestBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	contest, err := services.UpdateContest(ctx, user, objID, body)
+	if err != nil {
+		contestError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "contest updated successfully", gin.H{
+		"contest": contestView(contest, user),
+	})
+}
+
+// DeleteContestHandler deletes a contest of the user
+func DeleteContestHandler(c *gin.Context) {
+	ctx := c.Request.Context()
+	user, err := util.GetUserFromContext(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	objID, err := util.IsValidObjectID(c.Param("contestId"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid Contest ID")
+		return
+	}
+
+	if err := services.DeleteContest(ctx, user, objID); err != nil {
+		contestError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "contest deleted successfully", nil)
+}
+
+// RegisterContestHandler registers the user for a contest
+func RegisterContestHandler(c *gin.Context) {
+	ctx := c.Request.Context()
+	user, err := util.GetUserFromContext(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	objID, err := util.IsValidObjectID(c.Param("contestId"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid Contest ID")
+		return
+	}
+
+	participant, err := services.RegisterForContest(ctx, user, objID)
+	if err != nil {
+		contestError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "registered successfully", gin.H{
+		"participant": participant,
+	})
+}
+
+// UnfreezeContestHandler reveals the full scoreboard of a contest of
+// the user
+func UnfreezeContestHandler(c *gin.Context) {
+	ctx := c.Request.Context()
+	user, err := util.GetUserFromContext(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	objID, err := util.IsValidObjectID(c.Param("contestId"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid Contest ID")
+		return
+	}
+
+	contest, err := services.UnfreezeContest(ctx, user, objID)
+	if err != nil {
+		contestError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "contest unfrozen successfully", gin.H{
+		"contest": contestView(contest, user),
+	})
+}
+
+// GetScoreboardHandler returns the live standings of a contest
+func GetScoreboardHandler(c *gin.Context) {
+	ctx := c.Request.Context()
+	user, err := util.GetUserFromContext(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	objID, err := util.IsValidObjectID(c.Param("contestId"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid Contest ID")
+		return
+	}
+
+	board, err := services.GetScoreboard(ctx, user, objID)
+	if err != nil {
+		contestError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "scoreboard fetched successfully", gin.H{
+		"scoreboard": board,
+	})
+}
+
+func contestError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrInvalidContest):
+		response.Error(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, repository.ErrContestNotFound):
+		response.Error(c, http.StatusNotFound, "contest not found")
+	case errors.Is(err, services.ErrNotContestAuthor):
+		response.Error(c, http.StatusForbidden, err.Error())
+	case errors.Is(err, services.ErrContestEnded), errors.Is(err, repository.ErrAlreadyRegistered):
+		response.Error(c, http.StatusConflict, err.Error())
+	default:
+		response.Error(c, http.StatusInternalServerError, err.Error())
+	}
+}
+
+// contestView is the API representation of a contest. Problems are
+// hidden from everyone but the author until the contest starts.
+func contestView(contest *models.Contest, user *models.User) gin.H {
+	view := gin.H{
+		"contestId":      contest.ID,
+		"authorId":       contest.AuthorID,
+		"title":          contest.Title,
+		"description":    contest.Description,
+		"mode":           contest.Mode,
+		"startAt":        contest.StartAt,
+		"endAt":          contest.EndAt,
+		"penaltyMinutes": contest.PenaltyMinutes,
+		"problems":       contest.Problems,
+	}
+	if !contest.FreezeAt.IsZero() {
+		view["freezeAt"] = contest.FreezeAt
+		view["unfrozen"] = contest.Unfrozen
+	}
+	if contest.AuthorID != user.ID && time.Now().Before(contest.StartAt) {
+		view["problems"] = []models.ContestProblem{}
+	}
+	return view
+}
@@ -1,8 +1,10 @@
 package credentialHandler
 
 import (
+	"errors"
 	"net/http"
 
+	"github.com/anurag-327/neuron/internal/dto"
 	"github.com/anurag-327/neuron/internal/repository"
 	"github.com/anurag-327/neuron/internal/services"
 	"github.com/anurag-327/neuron/internal/util"
@@ -103,3 +105,38 @@ func RevealCredentialHandler(c *gin.Context) {
 		"warning":    "This is the only time you'll see the plain key. Store it securely.",
 	})
 }
+
+// SetCredentialLibrariesHandler sets the default library sets used by
+// submissions made with the API key
+func SetCredentialLibrariesHandler(c *gin.Context) {
+	ctx := c.Request.Context()
+	user, err := util.GetUserFromContext(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var body dto.SetCredentialLibrariesBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	cred, err := services.SetCredentialLibraries(ctx, user.ID, body.Libraries)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidLibrarySet) {
+			response.Error(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		if errors.Is(err, repository.ErrCredentialNotFound) {
+			response.Error(c, http.StatusNotFound, "no credential found")
+			return
+		}
+		response.Error(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, "credential libraries updated successfully", gin.H{
+		"credential": cred,
+	})
+}
@@ -0,0 +1,99 @@
+package languageHandler
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/anurag-327/neuron/config"
+	"github.com/anurag-327/neuron/internal/models"
+	"github.com/anurag-327/neuron/internal/registry"
+	"github.com/anurag-327/neuron/internal/util/response"
+	"github.com/gin-gonic/gin"
+)
+
+type versionInfo struct {
+	Version       string `json:"version"`
+	Image         string `json:"image"`
+	Default       bool   `json:"default"`
+	TimeLimitMs   int64  `json:"timeLimitMs"`
+	MemoryLimitMb int64  `json:"memoryLimitMb"`
+}
+
+type libraryInfo struct {
+	Name        string        `json:"name"`
+	DisplayName string        `json:"displayName"`
+	Packages    []string      `json:"packages"`
+	Versions    []versionInfo `json:"versions"`
+}
+
+type languageInfo struct {
+	Language       string        `json:"language"`
+	DisplayName    string        `json:"displayName"`
+	DefaultVersion string        `json:"defaultVersion"`
+	CreditCost     int64         `json:"creditCost"`
+	Versions       []versionInfo `json:"versions"`
+	Libraries      []libraryInfo `json:"libraries,omitempty"`
+}
+
+// ListLanguagesHandler lists the supported languages with their
+// runtime versions, execution limits and submission price.
+func ListLanguagesHandler(c *gin.Context) {
+	languages := make([]languageInfo, 0, len(registry.LanguageRegistry))
+	for name, langCfg := range registry.LanguageRegistry {
+		info := languageInfo{
+			Language:       name,
+			DisplayName:    langCfg.DisplayName,
+			DefaultVersion: langCfg.DefaultVersion,
+			CreditCost:     config.GetJobCredits(models.CreditReasonSubmission, langCfg),
+		}
+
+		info.Versions = executableVersions(langCfg, langCfg.Versions)
+		if len(info.Versions) == 0 {
+			continue
+		}
+
+		for _, lib := range langCfg.LibraryNames() {
+			libCfg := langCfg.Libraries[lib]
+			versions := executableVersions(langCfg, libCfg.Versions)
+			if len(versions) == 0 {
+				continue
+			}
+			info.Libraries = append(info.Libraries, libraryInfo{
+				Name:        lib,
+				DisplayName: libCfg.DisplayName,
+				Packages:    libCfg.Packages,
+				Versions:    versions,
+			})
+		}
+
+		languages = append(languages, info)
+	}
+
+	sort.Slice(languages, func(i, j int) bool {
+		return languages[i].Language < languages[j].Language
+	})
+
+	response.Success(c, http.StatusOK, "languages fetched successfully", gin.H{
+		"languages": languages,
+	})
+}
+
+// executableVersions lists the versions that have a pool, since only
+// those can actually execute.
+func executableVersions(langCfg registry.LanguageConfig, versions map[string]registry.VersionConfig) []versionInfo {
+	var infos []versionInfo
+	for _, v := range langCfg.VersionNames() {
+		versionCfg, ok := versions[v]
+		if !ok || versionCfg.Pool == nil {
+			continue
+		}
+		infos = append(infos, versionInfo{
+			Version:       v,
+			Image:         versionCfg.DockerImage,
+			Default:       v == langCfg.DefaultVersion,
+			TimeLimitMs:   versionCfg.GetTimeLimit().Milliseconds(),
+			MemoryLimitMb: versionCfg.Pool.GetMemoryLimit() / (1024 * 1024),
+		})
+	}
+	return infos
+}
@@ -0,0 +1,204 @@
+package problemHandler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/anurag-327/neuron/internal/dto"
+	"github.com/anurag-327/neuron/internal/models"
+	"github.com/anurag-327/neuron/internal/repository"
+	"github.com/anurag-327/neuron/internal/services"
+	"github.com/anurag-327/neuron/internal/util"
+	"github.com/anurag-327/neuron/internal/util/response"
+	"github.com/gin-gonic/gin"
+)
+
+// CreateProblemHandler stores a new problem authored by the user
+func CreateProblemHandler(c *gin.Context) {
+	ctx := c.Request.Context()
+	user, err := util.GetUserFromContext(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var body dto.ProblemBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	problem, err := services.CreateProblem(ctx, user, body)
+	if err != nil {
+		problemError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "problem created successfully", gin.H{
+		"problem": problemView(problem, true),
+	})
+}
+
+// ListProblemsHandler lists problems without statements and tests,
+// leaving out those of contests that have not started; ?mine=true
+// lists only the user's own problems
+func ListProblemsHandler(c *gin.Context) {
+	ctx := c.Request.Context()
+	user, err := util.GetUserFromContext(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	page, limit := util.GetPageAndLimitFromQuery(c)
+
+	problems, err := services.ListProblems(ctx, user, c.Query("mine") == "true", page, limit)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	views := make([]gin.H, 0, len(problems))
+	for i := range problems {
+		view := problemView(&problems[i], false)
+		delete(view, "statement")
+		delete(view, "samples")
+		views = append(views, view)
+	}
+
+	response.Success(c, http.StatusOK, "problems fetched successfully", gin.H{
+		"problems": views,
+		"page":     page,
+		"limit":    limit,
+	})
+}
+
+// GetProblemHandler returns a problem; hidden tests and the checker
+// code are only included for its author, and problems of contests that
+// have not started are not found for anyone else
+func GetProblemHandler(c *gin.Context) {
+	ctx := c.Request.Context()
+	user, err := util.GetUserFromContext(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	objID, err := util.IsValidObjectID(c.Param("problemId"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid Problem ID")
+		return
+	}
+
+	problem, err := services.GetVisibleProblem(ctx, user, objID)
+	if err != nil {
+		problemError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "problem fetched successfully", gin.H{
+		"problem": problemView(problem, problem.AuthorID == user.ID),
+	})
+}
+
+// UpdateProblemHandler replaces a problem of the user
+func UpdateProblemHandler(c *gin.Context) {
+	ctx := c.Request.Context()
+	user, err := util.GetUserFromContext(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	objID, err := util.IsValidObjectID(c.Param("problemId"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid Problem ID")
+		return
+	}
+
+	var body dto.ProblemBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	problem, err := services.UpdateProblem(ctx, user, objID, body)
+	if err != nil {
+		problemError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "problem updated successfully", gin.H{
+		"problem": problemView(problem, true),
+	})
+}
+
+// DeleteProblemHandler deletes a problem of the user
+func DeleteProblemHandler(c *gin.Context) {
+	ctx := c.Request.Context()
+	user, err := util.GetUserFromContext(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	objID, err := util.IsValidObjectID(c.Param("problemId"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid Problem ID")
+		return
+	}
+
+	if err := services.DeleteProblem(ctx, user, objID); err != nil {
+		problemError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "problem deleted successfully", nil)
+}
+
+func problemError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrInvalidProblem):
+		response.Error(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, repository.ErrProblemNotFound):
+		response.Error(c, http.StatusNotFound, "problem not found")
+	case errors.Is(err, services.ErrNotProblemAuthor):
+		response.Error(c, http.StatusForbidden, err.Error())
+	default:
+		response.Error(c, http.StatusInternalServerError, err.Error())
+	}
+}
+
+// problemView is the API representation of a problem. Everyone sees
+// the statement, limits and samples; authors also get the hidden tests,
+// the full checker and the interactor.
+func problemView(p *models.Problem, author bool) gin.H {
+	view := gin.H{
+		"problemId":   p.ID,
+		"authorId":    p.AuthorID,
+		"title":       p.Title,
+		"statement":   p.Statement,
+		"timeLimitMs": p.TimeLimitMs,
+		"languages":   p.Languages,
+		"samples":     p.Samples,
+		"hiddenTests": len(p.Tests),
+		"createdAt":   p.CreatedAt,
+		"updatedAt":   p.UpdatedAt,
+	}
+
+	checker := models.Checker{Type: models.CheckerExact}
+	if p.Checker != nil {
+		checker = *p.Checker
+	}
+	if author {
+		view["tests"] = p.Tests
+		view["checker"] = checker
+		if p.Interactor != nil {
+			view["interactor"] = p.Interactor
+		}
+	} else {
+		view["checker"] = gin.H{"type": checker.Type, "epsilon": checker.Epsilon}
+	}
+	view["interactive"] = p.Interactor != nil
+	return view
+}
@@ -1,219 +1,514 @@
-package runnerHandler
-
-import (
-	"encoding/json"
-	"errors"
-	"net/http"
-
-	"github.com/anurag-327/neuron/config"
-	"github.com/anurag-327/neuron/internal/dto"
-	"github.com/anurag-327/neuron/internal/factory"
-	"github.com/anurag-327/neuron/internal/models"
-	"github.com/anurag-327/neuron/internal/registry"
-	"github.com/anurag-327/neuron/internal/repository"
-	"github.com/anurag-327/neuron/internal/services"
-	"github.com/anurag-327/neuron/internal/util"
-	"github.com/anurag-327/neuron/internal/util/response"
-	"github.com/gin-gonic/gin"
-)
-
-func SubmitCodeHandler(c *gin.Context) {
-	ctx := c.Request.Context()
-
-	user, err := util.GetUserFromContext(c)
-	if err != nil {
-		response.Error(c, http.StatusUnauthorized, "unauthorized")
-		return
-	}
-
-	apiLog := &models.ApiLog{
-		UserID:        user.ID,
-		JobID:         nil,
-		Endpoint:      c.Request.URL.String(),
-		Method:        c.Request.Method,
-		ResponseCode:  http.StatusOK,
-		RequestStatus: "success",
-		Status:        "running",
-		ErrorMessage:  "",
-	}
-
-	var body dto.SubmitCodeBody
-	if err := c.ShouldBindJSON(&body); err != nil {
-		apiLog.ResponseCode = http.StatusBadRequest
-		apiLog.RequestStatus = "failed"
-		apiLog.ErrorMessage = err.Error()
-		apiLog.Status = "failed"
-		_, _ = repository.SaveApiLog(ctx, apiLog)
-		response.Error(c, http.StatusBadRequest, err.Error())
-		return
-	}
-
-	// Convert body to JSON string for logging
-	bodyJSON, err := json.Marshal(body)
-	if err != nil {
-		bodyJSON = []byte("{}")
-	}
-
-	apiLog.RequestBody = string(bodyJSON)
-
-	// 1 Language supported?
-	langCfg, ok := registry.LanguageRegistry[body.Language]
-	if !ok {
-		apiLog.ResponseCode = http.StatusBadRequest
-		apiLog.RequestStatus = "failed"
-		apiLog.ErrorMessage = "language not supported"
-		apiLog.Status = "failed"
-		_, _ = repository.SaveApiLog(ctx, apiLog)
-		response.Error(c, http.StatusBadRequest, "language not supported")
-		return
-	}
-
-	// 2 Code validation
-	if langCfg.Validator != nil {
-		if err := langCfg.Validator(body.Code); err != nil {
-			apiLog.ResponseCode = http.StatusBadRequest
-			apiLog.RequestStatus = "failed"
-			apiLog.ErrorMessage = err.Error()
-			apiLog.Status = "failed"
-			_, _ = repository.SaveApiLog(ctx, apiLog)
-			response.Error(c, http.StatusBadRequest, err.Error())
-			return
-		}
-	}
-
-	// 3 Credit check
-	if err := services.AssertCanSubmit(ctx, user.ID); err != nil {
-		if errors.Is(err, repository.ErrInsufficientCredits) {
-			apiLog.ResponseCode = http.StatusPaymentRequired
-			apiLog.RequestStatus = "failed"
-			apiLog.Status = "failed"
-			apiLog.ErrorMessage = "insufficient credits"
-			_, _ = repository.SaveApiLog(ctx, apiLog)
-			response.Error(c, http.StatusPaymentRequired, "insufficient credits")
-			return
-		}
-		apiLog.ResponseCode = http.StatusInternalServerError
-		apiLog.RequestStatus = "failed"
-		apiLog.ErrorMessage = err.Error()
-		_, _ = repository.SaveApiLog(ctx, apiLog)
-		response.Error(c, http.StatusInternalServerError, err.Error())
-		return
-	}
-
-	// 4 Create job
-	job, err := services.CreateSubmission(ctx, user, body)
-	if err != nil {
-		apiLog.ResponseCode = http.StatusInternalServerError
-		apiLog.RequestStatus = "failed"
-		apiLog.Status = "failed"
-		apiLog.ErrorMessage = err.Error()
-		_, _ = repository.SaveApiLog(ctx, apiLog)
-		response.Error(c, http.StatusInternalServerError, err.Error())
-		return
-	}
-
-	// 5 Publish job
-	jobBytes, _ := json.Marshal(job)
-	p, err := factory.GetPublisher()
-	if err != nil {
-		apiLog.ResponseCode = http.StatusInternalServerError
-		apiLog.RequestStatus = "failed"
-		apiLog.Status = "failed"
-		apiLog.ErrorMessage = "publisher unavailable"
-		_, _ = repository.SaveApiLog(ctx, apiLog)
-		_ = repository.DeleteJob(ctx, job)
-		response.Error(c, http.StatusInternalServerError, "publisher unavailable")
-		return
-	}
-
-	if err := p.Publish(config.ExecutionTasksTopic, job.Language, jobBytes); err != nil {
-		apiLog.ResponseCode = http.StatusInternalServerError
-		apiLog.RequestStatus = "failed"
-		apiLog.Status = "failed"
-		apiLog.ErrorMessage = err.Error()
-		_, _ = repository.SaveApiLog(ctx, apiLog)
-		_ = repository.DeleteJob(ctx, job)
-		response.Error(c, http.StatusInternalServerError, err.Error())
-		return
-	}
-
-	// 6 Update api log
-	apiLog.ResponseCode = http.StatusOK
-	apiLog.RequestStatus = "success"
-	apiLog.Status = "success"
-	apiLog.ErrorMessage = ""
-	apiLog.JobID = &job.ID
-	_, _ = repository.SaveApiLog(ctx, apiLog)
-
-	response.Success(
-		c,
-		http.StatusOK,
-		"job queued successfully",
-		gin.H{"jobId": job.ID, "status": job.Status},
-	)
-}
-
-func GetJobStatusHandler(c *gin.Context) {
-
-	ctx := c.Request.Context()
-
-	user, err := util.GetUserFromContext(c)
-	if err != nil {
-		response.Error(c, http.StatusUnauthorized, "unauthorized")
-		return
-	}
-
-	jobID := c.Param("jobId")
-	if jobID == "" {
-		response.Error(c, http.StatusBadRequest, "jobId is required")
-		return
-	}
-
-	objID, err := util.IsValidObjectID(jobID)
-	if err != nil {
-		response.Error(c, http.StatusBadRequest, "Invalid Job ID")
-		return
-	}
-
-	job, err := repository.GetJobByIDAndUserID(ctx, objID, user.ID)
-	if err != nil {
-		response.Error(c, http.StatusInternalServerError, err.Error())
-		return
-	}
-
-	// Return minimal info for queued or running jobs
-	if job.Status == models.StatusQueued || job.Status == models.StatusRunning {
-		response.Success(c, http.StatusOK, "status fetched successfully", gin.H{
-			"status": job.Status,
-			"jobId":  job.ID,
-		})
-		return
-	}
-
-	executionTime := job.FinishedAt.Sub(job.StartedAt)
-	queueTime := job.StartedAt.Sub(job.QueuedAt)
-	totalTime := job.FinishedAt.Sub(job.QueuedAt)
-
-	util.SetCreditsLeftHeader(c, user.Credits)
-	response.Success(c, http.StatusOK, "job result fetched successfully", gin.H{
-		"jobId":               job.ID,
-		"status":              job.Status,
-		"stdout":              job.Stdout,
-		"stderr":              job.Stderr,
-		"sandboxErrorType":    job.SandboxErrorType,
-		"sandboxErrorMessage": job.SandboxErrorMessage,
-		"language":            job.Language,
-		"exitCode":            job.ExitCode,
-
-		// timestamps
-		"queuedAt":   job.QueuedAt,
-		"startedAt":  job.StartedAt,
-		"finishedAt": job.FinishedAt,
-
-		// time statistics
-		"executionTimeMs": executionTime.Milliseconds(),
-		"queueTimeMs":     queueTime.Milliseconds(),
-		"totalTimeMs":     totalTime.Milliseconds(),
-	})
-
-}
+package runnerHandler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/anurag-327/neuron/config"
+	"github.com/anurag-327/neuron/internal/dto"
+	"github.com/anurag-327/neuron/internal/factory"
+	"github.com/anurag-327/neuron/internal/models"
+	"github.com/anurag-327/neuron/internal/registry"
+	"github.com/anurag-327/neuron/internal/repository"
+	"github.com/anurag-327/neuron/internal/services"
+	"github.com/anurag-327/neuron/internal/util"
+	"github.com/anurag-327/neuron/internal/util/response"
+	"github.com/anurag-327/neuron/pkg/analysis"
+	"github.com/anurag-327/neuron/pkg/sandbox"
+	"github.com/anurag-327/neuron/pkg/storage"
+	"github.com/gin-gonic/gin"
+)
+
+func SubmitCodeHandler(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	user, err := util.GetUserFromContext(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var body dto.SubmitCodeBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		apiLog := newSubmitLog(c, user)
+		apiLog.ResponseCode = http.StatusBadRequest
+		apiLog.RequestStatus = "failed"
+		apiLog.ErrorMessage = err.Error()
+		apiLog.Status = "failed"
+		_, _ = repository.SaveApiLog(ctx, apiLog)
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	key := c.GetHeader("Idempotency-Key")
+	if key == "" {
+		SubmitCode(c, user, body)
+		return
+	}
+
+	// Retried requests get the job of the first one
+	reservation, replay, err := services.ReserveIdempotencyKey(ctx, user, key, body)
+	if err != nil {
+		idempotencyError(c, err)
+		return
+	}
+	if replay != nil {
+		c.Header("Idempotent-Replayed", "true")
+		response.Success(c, http.StatusOK, "job already submitted with this idempotency key", gin.H{
+			"jobId":  replay.ID.Hex(),
+			"status": replay.Status,
+		})
+		return
+	}
+
+	job := SubmitCode(c, user, body)
+	if job == nil {
+		if err := services.ReleaseIdempotencyKey(ctx, reservation); err != nil {
+			log.Printf("failed to release idempotency key of user %s: %v", user.ID.Hex(), err)
+		}
+		return
+	}
+	if err := services.CompleteIdempotencyKey(ctx, reservation, job); err != nil {
+		log.Printf("failed to store idempotency key of job %s: %v", job.ID.Hex(), err)
+	}
+}
+
+func idempotencyError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrInvalidIdempotencyKey):
+		response.Error(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, services.ErrIdempotencyKeyReused):
+		response.Error(c, http.StatusUnprocessableEntity, err.Error())
+	case errors.Is(err, services.ErrIdempotencyKeyInProgress):
+		response.Error(c, http.StatusConflict, err.Error())
+	case errors.Is(err, repository.ErrJobNotFound):
+		response.Error(c, http.StatusNotFound, "job of this idempotency key no longer exists")
+	default:
+		response.Error(c, http.StatusInternalServerError, err.Error())
+	}
+}
+
+// newSubmitLog starts the API log of a submission request.
+func newSubmitLog(c *gin.Context, user *models.User) *models.ApiLog {
+	return &models.ApiLog{
+		UserID:        user.ID,
+		JobID:         nil,
+		Endpoint:      c.Request.URL.String(),
+		Method:        c.Request.Method,
+		ResponseCode:  http.StatusOK,
+		RequestStatus: "success",
+		Status:        "running",
+		ErrorMessage:  "",
+	}
+}
+
+// SubmitCode validates body and queues it as a job of user, writing the
+// response; credits are charged when the job runs. It returns the queued
+// job, or nil when the submission was rejected.
+func SubmitCode(c *gin.Context, user *models.User, body dto.SubmitCodeBody) *models.Job {
+	ctx := c.Request.Context()
+	apiLog := newSubmitLog(c, user)
+
+	// Convert body to JSON string for logging
+	bodyJSON, err := json.Marshal(body)
+	if err != nil {
+		bodyJSON = []byte("{}")
+	}
+
+	apiLog.RequestBody = string(bodyJSON)
+
+	// 1 Language supported?
+	langCfg, ok := registry.LanguageRegistry[body.Language]
+	if !ok {
+		apiLog.ResponseCode = http.StatusBadRequest
+		apiLog.RequestStatus = "failed"
+		apiLog.ErrorMessage = "language not supported"
+		apiLog.Status = "failed"
+		_, _ = repository.SaveApiLog(ctx, apiLog)
+		response.Error(c, http.StatusBadRequest, "language not supported")
+		return nil
+	}
+
+	// Submissions without a library set use the credential default
+	library := body.Library
+	if library == "" {
+		if cred, ok := util.GetCredentialFromContext(c); ok {
+			library = langCfg.DefaultLibrary(cred.Libraries)
+		}
+	}
+
+	versionCfg, err := langCfg.ResolveVersion(body.Version)
+	if err != nil {
+		apiLog.ResponseCode = http.StatusBadRequest
+		apiLog.RequestStatus = "failed"
+		apiLog.ErrorMessage = err.Error()
+		apiLog.Status = "failed"
+		_, _ = repository.SaveApiLog(ctx, apiLog)
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return nil
+	}
+
+	runtime, err := langCfg.ResolveRuntime(versionCfg.Name, library)
+	if err != nil {
+		apiLog.ResponseCode = http.StatusBadRequest
+		apiLog.RequestStatus = "failed"
+		apiLog.ErrorMessage = err.Error()
+		apiLog.Status = "failed"
+		_, _ = repository.SaveApiLog(ctx, apiLog)
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return nil
+	}
+
+	if err := sandbox.ValidateArtifactGlobs(body.Artifacts); err != nil {
+		apiLog.ResponseCode = http.StatusBadRequest
+		apiLog.RequestStatus = "failed"
+		apiLog.ErrorMessage = err.Error()
+		apiLog.Status = "failed"
+		_, _ = repository.SaveApiLog(ctx, apiLog)
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return nil
+	}
+
+	files, err := services.DecodeDataFiles(body.Files)
+	if err == nil {
+		err = sandbox.ValidateDataFiles(files, langCfg)
+	}
+	if err == nil {
+		err = sandbox.ValidateArgs(body.Args)
+	}
+	if err == nil {
+		err = sandbox.ValidateEnv(body.Env)
+	}
+	if err == nil && body.Interactor != "" {
+		err = errors.New("interactors are set on problems, not on submissions")
+	}
+	var problem *models.Problem
+	if err == nil && body.ProblemID != "" {
+		problem, err = services.GetSubmissionProblem(ctx, user, body)
+	}
+	if err == nil && problem != nil && problem.Interactor != nil {
+		err = sandbox.ValidateInteractor(runtime)
+	}
+	if err != nil {
+		apiLog.ResponseCode = http.StatusBadRequest
+		apiLog.RequestStatus = "failed"
+		apiLog.ErrorMessage = err.Error()
+		apiLog.Status = "failed"
+		_, _ = repository.SaveApiLog(ctx, apiLog)
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return nil
+	}
+
+	// 2 Code validation under the plan's analysis policy
+	policy := config.GetAnalysisPolicy(user.GetPlan())
+	fileAccess := len(body.Files) > 0 || len(body.Artifacts) > 0
+	err = langCfg.Validate(body.Code, library, policy, fileAccess)
+	var jobChecker *models.Checker
+	if err == nil && body.Checker != nil {
+		jobChecker, err = services.ResolveChecker(*body.Checker, body.Language)
+	}
+	if err != nil {
+		apiLog.ResponseCode = http.StatusBadRequest
+		apiLog.RequestStatus = "failed"
+		apiLog.ErrorMessage = err.Error()
+		apiLog.Status = "failed"
+		_, _ = repository.SaveApiLog(ctx, apiLog)
+		var findingsErr *analysis.FindingsError
+		if errors.As(err, &findingsErr) {
+			response.ErrorWithData(c, http.StatusBadRequest, err.Error(), gin.H{
+				"policy":   policy,
+				"findings": findingsErr.Findings,
+			})
+			return nil
+		}
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return nil
+	}
+
+	// Contest submissions: running contest, registration and problem set
+	var contest *models.Contest
+	if body.ContestID != "" {
+		contest, err = services.GetSubmissionContest(ctx, user, body)
+		if err != nil {
+			code := http.StatusInternalServerError
+			switch {
+			case errors.Is(err, services.ErrInvalidContest):
+				code = http.StatusBadRequest
+			case errors.Is(err, repository.ErrContestNotFound):
+				code = http.StatusNotFound
+			case errors.Is(err, services.ErrContestNotRunning),
+				errors.Is(err, services.ErrNotContestant),
+				errors.Is(err, services.ErrProblemNotInContest):
+				code = http.StatusForbidden
+			}
+			apiLog.ResponseCode = int64(code)
+			apiLog.RequestStatus = "failed"
+			apiLog.ErrorMessage = err.Error()
+			apiLog.Status = "failed"
+			_, _ = repository.SaveApiLog(ctx, apiLog)
+			response.Error(c, code, err.Error())
+			return nil
+		}
+	}
+
+	// 3 Credit check
+	if err := services.AssertCanSubmit(ctx, user.ID, langCfg); err != nil {
+		if errors.Is(err, repository.ErrInsufficientCredits) {
+			apiLog.ResponseCode = http.StatusPaymentRequired
+			apiLog.RequestStatus = "failed"
+			apiLog.Status = "failed"
+			apiLog.ErrorMessage = "insufficient credits"
+			_, _ = repository.SaveApiLog(ctx, apiLog)
+			response.Error(c, http.StatusPaymentRequired, "insufficient credits")
+			return nil
+		}
+		apiLog.ResponseCode = http.StatusInternalServerError
+		apiLog.RequestStatus = "failed"
+		apiLog.ErrorMessage = err.Error()
+		_, _ = repository.SaveApiLog(ctx, apiLog)
+		response.Error(c, http.StatusInternalServerError, err.Error())
+		return nil
+	}
+
+	// 4 Create job
+	var cacheKey string
+	if body.Cache {
+		cacheKey, err = services.ResultCacheKey(body, runtime, library, files, jobChecker)
+		if err != nil {
+			apiLog.ResponseCode = http.StatusBadRequest
+			apiLog.RequestStatus = "failed"
+			apiLog.Status = "failed"
+			apiLog.ErrorMessage = err.Error()
+			_, _ = repository.SaveApiLog(ctx, apiLog)
+			response.Error(c, http.StatusBadRequest, err.Error())
+			return nil
+		}
+	}
+
+	job, err := services.CreateSubmission(ctx, user, body, versionCfg.Name, library, files, jobChecker, problem, contest, cacheKey)
+	if err != nil {
+		apiLog.ResponseCode = http.StatusInternalServerError
+		apiLog.RequestStatus = "failed"
+		apiLog.Status = "failed"
+		apiLog.ErrorMessage = err.Error()
+		_, _ = repository.SaveApiLog(ctx, apiLog)
+		response.Error(c, http.StatusInternalServerError, err.Error())
+		return nil
+	}
+
+	// Cached results are served without running the job; lookup
+	// failures fall back to running it
+	if job.CacheKey != "" {
+		served, err := services.ServeFromCache(ctx, job)
+		if err != nil {
+			log.Printf("result cache lookup failed for job %s: %v", job.ID.Hex(), err)
+		}
+		if served {
+			apiLog.ResponseCode = http.StatusOK
+			apiLog.RequestStatus = "success"
+			apiLog.Status = job.Status
+			apiLog.ErrorMessage = ""
+			apiLog.JobID = &job.ID
+			_, _ = repository.SaveApiLog(ctx, apiLog)
+
+			response.Success(
+				c,
+				http.StatusOK,
+				"job served from cache",
+				gin.H{"jobId": job.ID, "status": job.Status, "fromCache": true},
+			)
+			return job
+		}
+	}
+
+	// 5 Publish job
+	if !publishJob(c, apiLog, job) {
+		return nil
+	}
+
+	response.Success(
+		c,
+		http.StatusOK,
+		"job queued successfully",
+		gin.H{"jobId": job.ID, "status": job.Status},
+	)
+	return job
+}
+
+// publishJob queues a stored job for the workers and records it in the
+// API log. When publishing fails the job is deleted, the failure logged
+// and answered, and false returned.
+func publishJob(c *gin.Context, apiLog *models.ApiLog, job *models.Job) bool {
+	ctx := c.Request.Context()
+
+	jobBytes, _ := json.Marshal(job)
+	p, err := factory.GetPublisher()
+	if err != nil {
+		apiLog.ResponseCode = http.StatusInternalServerError
+		apiLog.RequestStatus = "failed"
+		apiLog.Status = "failed"
+		apiLog.ErrorMessage = "publisher unavailable"
+		_, _ = repository.SaveApiLog(ctx, apiLog)
+		_ = repository.DeleteJob(ctx, job)
+		response.Error(c, http.StatusInternalServerError, "publisher unavailable")
+		return false
+	}
+
+	if err := p.Publish(config.ExecutionTasksTopic, job.Language, jobBytes); err != nil {
+		apiLog.ResponseCode = http.StatusInternalServerError
+		apiLog.RequestStatus = "failed"
+		apiLog.Status = "failed"
+		apiLog.ErrorMessage = err.Error()
+		_, _ = repository.SaveApiLog(ctx, apiLog)
+		_ = repository.DeleteJob(ctx, job)
+		response.Error(c, http.StatusInternalServerError, err.Error())
+		return false
+	}
+
+	// Update api log
+	apiLog.ResponseCode = http.StatusOK
+	apiLog.RequestStatus = "success"
+	apiLog.Status = "success"
+	apiLog.ErrorMessage = ""
+	apiLog.JobID = &job.ID
+	_, _ = repository.SaveApiLog(ctx, apiLog)
+	return true
+}
+
+func GetJobStatusHandler(c *gin.Context) {
+
+	ctx := c.Request.Context()
+
+	user, err := util.GetUserFromContext(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	jobID := c.Param("jobId")
+	if jobID == "" {
+		response.Error(c, http.StatusBadRequest, "jobId is required")
+		return
+	}
+
+	objID, err := util.IsValidObjectID(jobID)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid Job ID")
+		return
+	}
+
+	job, err := repository.GetJobByIDAndUserID(ctx, objID, user.ID)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	// Return minimal info for queued or running jobs
+	if job.Status == models.StatusQueued || job.Status == models.StatusRunning {
+		response.Success(c, http.StatusOK, "status fetched successfully", gin.H{
+			"status": job.Status,
+			"jobId":  job.ID,
+		})
+		return
+	}
+
+	executionTime := job.FinishedAt.Sub(job.StartedAt)
+	queueTime := job.StartedAt.Sub(job.QueuedAt)
+	totalTime := job.FinishedAt.Sub(job.QueuedAt)
+
+	util.SetCreditsLeftHeader(c, user.Credits)
+	response.Success(c, http.StatusOK, "job result fetched successfully", gin.H{
+		"jobId":               job.ID,
+		"status":              job.Status,
+		"stdout":              job.Stdout,
+		"stderr":              job.Stderr,
+		"stdoutBlob":          job.StdoutBlob,
+		"stderrBlob":          job.StderrBlob,
+		"sandboxErrorType":    job.SandboxErrorType,
+		"sandboxErrorMessage": job.SandboxErrorMessage,
+		"language":            job.Language,
+		"version":             job.Version,
+		"library":             job.Library,
+		"exitCode":            job.ExitCode,
+		"diagnostics":         job.Diagnostics,
+		"artifacts":           job.Artifacts,
+		"artifactsSkipped":    job.ArtifactsSkipped,
+		"verdict":             job.Verdict,
+		"verdictMessage":      job.VerdictMessage,
+		"problemId":           job.ProblemID,
+		"testResults":         job.TestResults,
+		"contestId":           job.ContestID,
+		"parentJobId":         job.ParentJobID,
+		"rootJobId":           job.RootJobID,
+		"fromCache":           job.FromCache,
+
+		// timestamps
+		"queuedAt":   job.QueuedAt,
+		"startedAt":  job.StartedAt,
+		"finishedAt": job.FinishedAt,
+
+		// time statistics
+		"executionTimeMs": executionTime.Milliseconds(),
+		"queueTimeMs":     queueTime.Milliseconds(),
+		"totalTimeMs":     totalTime.Milliseconds(),
+	})
+
+}
+
+// GetJobArtifactHandler downloads a file produced by a job of the user.
+// Artifacts are always served as attachments.
+func GetJobArtifactHandler(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	user, err := util.GetUserFromContext(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	objID, err := util.IsValidObjectID(c.Param("jobId"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid Job ID")
+		return
+	}
+
+	job, err := repository.GetJobByIDAndUserID(ctx, objID, user.ID)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	name := strings.TrimPrefix(c.Param("name"), "/")
+	var artifact *models.Artifact
+	for i := range job.Artifacts {
+		if job.Artifacts[i].Name == name {
+			artifact = &job.Artifacts[i]
+			break
+		}
+	}
+	if artifact == nil {
+		response.Error(c, http.StatusNotFound, "artifact not found")
+		return
+	}
+
+	content, err := services.OpenArtifact(ctx, *artifact)
+	if errors.Is(err, storage.ErrNotFound) {
+		response.Error(c, http.StatusNotFound, "artifact not found")
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	defer content.Close()
+
+	c.DataFromReader(http.StatusOK, artifact.Size, artifact.ContentType, content, map[string]string{
+		"Content-Disposition":    mime.FormatMediaType("attachment", map[string]string{"filename": path.Base(name)}),
+		"X-Content-Type-Options": "nosniff",
+	})
+}
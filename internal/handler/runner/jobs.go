@@ -0,0 +1,43 @@
+package runnerHandler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/anurag-327/neuron/internal/dto"
+	"github.com/anurag-327/neuron/internal/services"
+	"github.com/anurag-327/neuron/internal/util"
+	"github.com/anurag-327/neuron/internal/util/response"
+	"github.com/gin-gonic/gin"
+)
+
+func ListJobsHandler(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	user, err := util.GetUserFromContext(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var query dto.ListJobsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	jobs, nextCursor, err := services.ListJobs(ctx, user, query)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidJobQuery) {
+			response.Error(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		response.Error(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, "jobs fetched successfully", gin.H{
+		"jobs":       jobs,
+		"nextCursor": nextCursor,
+	})
+}
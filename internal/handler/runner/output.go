@@ -0,0 +1,77 @@
+package runnerHandler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/anurag-327/neuron/internal/models"
+	"github.com/anurag-327/neuron/internal/repository"
+	"github.com/anurag-327/neuron/internal/services"
+	"github.com/anurag-327/neuron/internal/util"
+	"github.com/anurag-327/neuron/internal/util/response"
+	"github.com/anurag-327/neuron/pkg/storage"
+	"github.com/gin-gonic/gin"
+)
+
+// GetJobOutputHandler serves the whole stdout or stderr of a job of the
+// user as plain text. The job result only carries the beginning of
+// large outputs; this serves them in full, with range requests.
+func GetJobOutputHandler(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	user, err := util.GetUserFromContext(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	objID, err := util.IsValidObjectID(c.Param("jobId"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid Job ID")
+		return
+	}
+
+	job, err := repository.GetJobByIDAndUserID(ctx, objID, user.ID)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if job.Status == models.StatusQueued || job.Status == models.StatusRunning {
+		response.Error(c, http.StatusConflict, "job has not finished")
+		return
+	}
+
+	var inline string
+	var blob *models.OutputBlob
+	switch c.Param("stream") {
+	case "stdout":
+		inline, blob = job.Stdout, job.StdoutBlob
+	case "stderr":
+		inline, blob = job.Stderr, job.StderrBlob
+	default:
+		response.Error(c, http.StatusNotFound, "output must be stdout or stderr")
+		return
+	}
+
+	c.Header("Content-Type", "text/plain; charset=utf-8")
+	c.Header("X-Content-Type-Options", "nosniff")
+	if blob == nil {
+		http.ServeContent(c.Writer, c.Request, "", job.FinishedAt, strings.NewReader(inline))
+		return
+	}
+
+	content, err := services.OpenOutput(ctx, blob)
+	if errors.Is(err, storage.ErrNotFound) {
+		response.Error(c, http.StatusNotFound, "output not found")
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	defer content.Close()
+
+	c.Header("ETag", `"`+blob.SHA256+`"`)
+	http.ServeContent(c.Writer, c.Request, "", job.FinishedAt, storage.ReadSeeker(content, blob.Size))
+}
@@ -0,0 +1,191 @@
+package runnerHandler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/anurag-327/neuron/config"
+	"github.com/anurag-327/neuron/internal/dto"
+	"github.com/anurag-327/neuron/internal/registry"
+	"github.com/anurag-327/neuron/internal/repository"
+	"github.com/anurag-327/neuron/internal/services"
+	"github.com/anurag-327/neuron/internal/util"
+	"github.com/anurag-327/neuron/internal/util/response"
+	"github.com/anurag-327/neuron/pkg/analysis"
+	"github.com/gin-gonic/gin"
+)
+
+// RerunJobHandler queues a copy of a job of the user, optionally with
+// another input or a lower time limit, charged as a rerun
+func RerunJobHandler(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	user, err := util.GetUserFromContext(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	apiLog := newSubmitLog(c, user)
+	fail := func(code int, message string) {
+		apiLog.ResponseCode = int64(code)
+		apiLog.RequestStatus = "failed"
+		apiLog.Status = "failed"
+		apiLog.ErrorMessage = message
+		_, _ = repository.SaveApiLog(ctx, apiLog)
+		response.Error(c, code, message)
+	}
+
+	objID, err := util.IsValidObjectID(c.Param("jobId"))
+	if err != nil {
+		fail(http.StatusBadRequest, "Invalid Job ID")
+		return
+	}
+
+	// The body is optional
+	var body dto.RerunBody
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&body); err != nil {
+			fail(http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+	bodyJSON, err := json.Marshal(body)
+	if err != nil {
+		bodyJSON = []byte("{}")
+	}
+	apiLog.RequestBody = string(bodyJSON)
+
+	parent, err := repository.GetJobByIDAndUserID(ctx, objID, user.ID)
+	if errors.Is(err, repository.ErrJobNotFound) {
+		fail(http.StatusNotFound, "job not found")
+		return
+	}
+	if err != nil {
+		fail(http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	// The runtime of the job may have been removed since
+	langCfg, ok := registry.LanguageRegistry[parent.Language]
+	if !ok {
+		fail(http.StatusBadRequest, "language not supported")
+		return
+	}
+	version := parent.Version
+	if version == "" {
+		version = langCfg.DefaultVersion
+	}
+	if _, err := langCfg.ResolveRuntime(version, parent.Library); err != nil {
+		fail(http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := services.AssertCanRerun(ctx, user.ID); err != nil {
+		if errors.Is(err, repository.ErrInsufficientCredits) {
+			fail(http.StatusPaymentRequired, "insufficient credits")
+			return
+		}
+		fail(http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	job, err := services.CreateRerun(ctx, user, parent, body)
+	var findingsErr *analysis.FindingsError
+	if errors.As(err, &findingsErr) {
+		apiLog.ResponseCode = http.StatusBadRequest
+		apiLog.RequestStatus = "failed"
+		apiLog.Status = "failed"
+		apiLog.ErrorMessage = err.Error()
+		_, _ = repository.SaveApiLog(ctx, apiLog)
+		response.ErrorWithData(c, http.StatusBadRequest, err.Error(), gin.H{
+			"policy":   config.GetAnalysisPolicy(user.GetPlan()),
+			"findings": findingsErr.Findings,
+		})
+		return
+	}
+	if errors.Is(err, services.ErrJobArchived) {
+		fail(http.StatusGone, err.Error())
+		return
+	}
+	if errors.Is(err, services.ErrInvalidRerun) {
+		fail(http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		fail(http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if !publishJob(c, apiLog, job) {
+		return
+	}
+
+	response.Success(c, http.StatusOK, "job queued successfully", gin.H{
+		"jobId":       job.ID,
+		"status":      job.Status,
+		"parentJobId": job.ParentJobID,
+		"rootJobId":   job.RootJobID,
+	})
+}
+
+// ListRerunsHandler lists the rerun chain of a job of the user: the
+// first job and all its reruns in submission order
+func ListRerunsHandler(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	user, err := util.GetUserFromContext(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	objID, err := util.IsValidObjectID(c.Param("jobId"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid Job ID")
+		return
+	}
+
+	job, err := repository.GetJobByIDAndUserID(ctx, objID, user.ID)
+	if errors.Is(err, repository.ErrJobNotFound) {
+		response.Error(c, http.StatusNotFound, "job not found")
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	root := job.ID
+	if job.RootJobID != nil {
+		root = *job.RootJobID
+	}
+	chain, err := repository.GetRerunChain(ctx, root, user.ID)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	jobs := make([]gin.H, 0, len(chain))
+	for _, j := range chain {
+		jobs = append(jobs, gin.H{
+			"jobId":            j.ID,
+			"parentJobId":      j.ParentJobID,
+			"status":           j.Status,
+			"language":         j.Language,
+			"version":          j.Version,
+			"sandboxErrorType": j.SandboxErrorType,
+			"exitCode":         j.ExitCode,
+			"verdict":          j.Verdict,
+			"timeLimitMs":      j.TimeLimitMs,
+			"queuedAt":         j.QueuedAt,
+			"finishedAt":       j.FinishedAt,
+		})
+	}
+
+	response.Success(c, http.StatusOK, "rerun chain fetched successfully", gin.H{
+		"rootJobId": root,
+		"jobs":      jobs,
+	})
+}
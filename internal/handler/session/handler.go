@@ -0,0 +1,206 @@
+package sessionHandler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/anurag-327/neuron/config"
+	"github.com/anurag-327/neuron/internal/dto"
+	"github.com/anurag-327/neuron/internal/models"
+	"github.com/anurag-327/neuron/internal/registry"
+	"github.com/anurag-327/neuron/internal/repository"
+	"github.com/anurag-327/neuron/internal/services"
+	"github.com/anurag-327/neuron/internal/util"
+	"github.com/anurag-327/neuron/internal/util/response"
+	"github.com/anurag-327/neuron/pkg/analysis"
+	"github.com/anurag-327/neuron/pkg/sandbox"
+	"github.com/gin-gonic/gin"
+)
+
+// CreateSessionHandler validates an interactive session like a code
+// submission and returns the one-time WebSocket URL to join it on a
+// worker (see SessionSocketHandler).
+func CreateSessionHandler(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	user, err := util.GetUserFromContext(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	apiLog := &models.ApiLog{
+		UserID:        user.ID,
+		Endpoint:      c.Request.URL.String(),
+		Method:        c.Request.Method,
+		ResponseCode:  http.StatusOK,
+		RequestStatus: "success",
+		Status:        "running",
+	}
+
+	fail := func(code int, message string) {
+		apiLog.ResponseCode = int64(code)
+		apiLog.RequestStatus = "failed"
+		apiLog.ErrorMessage = message
+		apiLog.Status = "failed"
+		_, _ = repository.SaveApiLog(ctx, apiLog)
+		response.Error(c, code, message)
+	}
+
+	var body dto.CreateSessionBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		fail(http.StatusBadRequest, err.Error())
+		return
+	}
+
+	bodyJSON, err := json.Marshal(body)
+	if err != nil {
+		bodyJSON = []byte("{}")
+	}
+	apiLog.RequestBody = string(bodyJSON)
+
+	// 1 Language and runtime
+	langCfg, ok := registry.LanguageRegistry[body.Language]
+	if !ok {
+		fail(http.StatusBadRequest, "language not supported")
+		return
+	}
+
+	library := body.Library
+	if library == "" {
+		if cred, ok := util.GetCredentialFromContext(c); ok {
+			library = langCfg.DefaultLibrary(cred.Libraries)
+		}
+	}
+
+	versionCfg, err := langCfg.ResolveVersion(body.Version)
+	if err != nil {
+		fail(http.StatusBadRequest, err.Error())
+		return
+	}
+	runtime, err := langCfg.ResolveRuntime(versionCfg.Name, library)
+	if err != nil {
+		fail(http.StatusBadRequest, err.Error())
+		return
+	}
+	if runtime.Sandbox.Backend != registry.BackendDocker {
+		fail(http.StatusBadRequest, "interactive sessions are not supported for this runtime")
+		return
+	}
+
+	// 2 Arguments, environment and data files
+	files, err := services.DecodeDataFiles(body.Files)
+	if err == nil {
+		err = sandbox.ValidateDataFiles(files, langCfg)
+	}
+	if err == nil {
+		err = sandbox.ValidateArgs(body.Args)
+	}
+	if err == nil {
+		err = sandbox.ValidateEnv(body.Env)
+	}
+	if err != nil {
+		fail(http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// 3 Code validation under the plan's analysis policy
+	policy := config.GetAnalysisPolicy(user.GetPlan())
+	if err := langCfg.Validate(body.Code, library, policy, len(body.Files) > 0); err != nil {
+		var findingsErr *analysis.FindingsError
+		if errors.As(err, &findingsErr) {
+			apiLog.ResponseCode = http.StatusBadRequest
+			apiLog.RequestStatus = "failed"
+			apiLog.ErrorMessage = err.Error()
+			apiLog.Status = "failed"
+			_, _ = repository.SaveApiLog(ctx, apiLog)
+			response.ErrorWithData(c, http.StatusBadRequest, err.Error(), gin.H{
+				"policy":   policy,
+				"findings": findingsErr.Findings,
+			})
+			return
+		}
+		fail(http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// 4 Credit check: at least one minute
+	if err := services.AssertCanStartSession(ctx, user.ID); err != nil {
+		if errors.Is(err, repository.ErrInsufficientCredits) {
+			fail(http.StatusPaymentRequired, "insufficient credits")
+			return
+		}
+		fail(http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	// 5 Create session
+	session, token, err := services.CreateSession(ctx, user, body, versionCfg.Name, library, files)
+	if err != nil {
+		fail(http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	apiLog.Status = "success"
+	_, _ = repository.SaveApiLog(ctx, apiLog)
+
+	response.Success(c, http.StatusOK, "session created", gin.H{
+		"sessionId": session.ID,
+		"status":    session.Status,
+		"expiresAt": session.ExpiresAt,
+		"url":       sessionURL(session.ID.Hex(), token),
+	})
+}
+
+// GetSessionHandler returns the state of a session of the user.
+func GetSessionHandler(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	user, err := util.GetUserFromContext(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	objID, err := util.IsValidObjectID(c.Param("sessionId"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid Session ID")
+		return
+	}
+
+	session, err := repository.GetSessionByIDAndUserID(ctx, objID, user.ID)
+	if err != nil {
+		if errors.Is(err, repository.ErrSessionNotFound) {
+			response.Error(c, http.StatusNotFound, "session not found")
+			return
+		}
+		response.Error(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, "session fetched successfully", gin.H{
+		"sessionId":      session.ID,
+		"language":       session.Language,
+		"version":        session.Version,
+		"library":        session.Library,
+		"status":         session.Status,
+		"startedAt":      session.StartedAt,
+		"endedAt":        session.EndedAt,
+		"durationMs":     session.DurationMs,
+		"closeReason":    session.CloseReason,
+		"errorMessage":   session.ErrorMessage,
+		"exitCode":       session.ExitCode,
+		"creditsCharged": session.CreditsCharged,
+	})
+}
+
+// sessionURL is the WebSocket URL of a session on the workers, under
+// SESSION_PUBLIC_URL (e.g. "wss://sessions.neuron.dev").
+func sessionURL(sessionID, token string) string {
+	base := strings.TrimRight(os.Getenv("SESSION_PUBLIC_URL"), "/")
+	return base + "/sessions/" + sessionID + "/ws?token=" + url.QueryEscape(token)
+}
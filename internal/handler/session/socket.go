@@ -0,0 +1,141 @@
+package sessionHandler
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/anurag-327/neuron/internal/models"
+	"github.com/anurag-327/neuron/internal/repository"
+	"github.com/anurag-327/neuron/internal/services"
+	"github.com/anurag-327/neuron/internal/util"
+	"github.com/anurag-327/neuron/internal/util/response"
+	"github.com/anurag-327/neuron/pkg/sandbox"
+	"github.com/gin-gonic/gin"
+	"golang.org/x/net/websocket"
+)
+
+// maxSessionMessage bounds a single client message.
+const maxSessionMessage = 64 * 1024
+
+// SessionMessage is a JSON text frame of the session protocol.
+//
+// Client → worker: "stdin" (Data is written to the program) and "eof"
+// (closes its stdin). Worker → client: "stdout" / "stderr" with output
+// as it is produced, then a final "exit" or "error".
+type SessionMessage struct {
+	Type string `json:"type"`
+	Data string `json:"data,omitempty"`
+
+	ExitCode       *int64                    `json:"exitCode,omitempty"`
+	Reason         models.SessionCloseReason `json:"reason,omitempty"`
+	DurationMs     int64                     `json:"durationMs,omitempty"`
+	CreditsCharged int64                     `json:"creditsCharged,omitempty"`
+	Message        string                    `json:"message,omitempty"`
+}
+
+// SessionSocketHandler serves the WebSocket of a session on a worker.
+// The token query parameter authenticates the connection and can be
+// used once; the session runs until the program exits, a limit is hit
+// or the client disconnects.
+func SessionSocketHandler(c *gin.Context) {
+	objID, err := util.IsValidObjectID(c.Param("sessionId"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid Session ID")
+		return
+	}
+
+	session, err := services.JoinSession(c.Request.Context(), objID, c.Query("token"))
+	if err != nil {
+		if errors.Is(err, repository.ErrSessionUnavailable) {
+			response.Error(c, http.StatusGone, "session is not available")
+			return
+		}
+		response.Error(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	// The token authenticates the client, so any origin may connect
+	server := websocket.Server{
+		Handshake: func(*websocket.Config, *http.Request) error { return nil },
+		Handler: func(ws *websocket.Conn) {
+			serveSession(ws, session)
+		},
+	}
+	server.ServeHTTP(c.Writer, c.Request)
+}
+
+func serveSession(ws *websocket.Conn, session *models.Session) {
+	defer ws.Close()
+	ws.MaxPayloadBytes = maxSessionMessage
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var mu sync.Mutex
+	send := func(msg SessionMessage) error {
+		mu.Lock()
+		defer mu.Unlock()
+		return websocket.JSON.Send(ws, msg)
+	}
+
+	stdinR, stdinW := io.Pipe()
+	defer stdinR.Close()
+
+	// Client messages; a dropped connection ends the session
+	go func() {
+		defer cancel()
+		for {
+			var msg SessionMessage
+			if err := websocket.JSON.Receive(ws, &msg); err != nil {
+				_ = stdinW.CloseWithError(err)
+				return
+			}
+			switch msg.Type {
+			case "stdin":
+				if _, err := stdinW.Write([]byte(msg.Data)); err != nil {
+					// stdin closed by "eof" or the program ended
+					continue
+				}
+			case "eof":
+				_ = stdinW.Close()
+			}
+		}
+	}()
+
+	session = sandbox.RunSession(
+		ctx,
+		session,
+		stdinR,
+		streamWriter{kind: "stdout", send: send},
+		streamWriter{kind: "stderr", send: send},
+	)
+
+	if session.CloseReason == models.SessionFailed {
+		_ = send(SessionMessage{Type: "error", Message: session.ErrorMessage})
+		return
+	}
+	exitCode := session.ExitCode
+	_ = send(SessionMessage{
+		Type:           "exit",
+		ExitCode:       &exitCode,
+		Reason:         session.CloseReason,
+		DurationMs:     session.DurationMs,
+		CreditsCharged: session.CreditsCharged,
+	})
+}
+
+// streamWriter forwards program output as stdout / stderr messages.
+type streamWriter struct {
+	kind string
+	send func(SessionMessage) error
+}
+
+func (w streamWriter) Write(p []byte) (int, error) {
+	if err := w.send(SessionMessage{Type: w.kind, Data: string(p)}); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
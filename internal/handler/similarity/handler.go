@@ -0,0 +1,54 @@
+package similarityHandler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/anurag-327/neuron/internal/dto"
+	"github.com/anurag-327/neuron/internal/repository"
+	"github.com/anurag-327/neuron/internal/services"
+	"github.com/anurag-327/neuron/internal/util"
+	"github.com/anurag-327/neuron/internal/util/response"
+	"github.com/gin-gonic/gin"
+)
+
+// FindSimilarPairsHandler compares a set of jobs, or the submissions to
+// a problem, and returns the suspiciously similar pairs
+func FindSimilarPairsHandler(c *gin.Context) {
+	ctx := c.Request.Context()
+	user, err := util.GetUserFromContext(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var body dto.SimilarityBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	pairs, err := services.FindSimilarPairs(ctx, user, body)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidSimilarity):
+			response.Error(c, http.StatusBadRequest, err.Error())
+		case errors.Is(err, repository.ErrJobNotFound):
+			response.Error(c, http.StatusNotFound, "job not found")
+		case errors.Is(err, repository.ErrProblemNotFound):
+			response.Error(c, http.StatusNotFound, "problem not found")
+		case errors.Is(err, services.ErrJobNotComparable), errors.Is(err, services.ErrNotProblemAuthor):
+			response.Error(c, http.StatusForbidden, err.Error())
+		default:
+			response.Error(c, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+	if pairs == nil {
+		pairs = []services.SimilarPair{}
+	}
+
+	response.Success(c, http.StatusOK, "similar pairs fetched successfully", gin.H{
+		"pairs": pairs,
+	})
+}
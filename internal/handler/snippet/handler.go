@@ -0,0 +1,434 @@
+package snippetHandler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/anurag-327/neuron/internal/dto"
+	runnerHandler "github.com/anurag-327/neuron/internal/handler/runner"
+	"github.com/anurag-327/neuron/internal/models"
+	"github.com/anurag-327/neuron/internal/repository"
+	"github.com/anurag-327/neuron/internal/services"
+	"github.com/anurag-327/neuron/internal/util"
+	"github.com/anurag-327/neuron/internal/util/response"
+	"github.com/gin-gonic/gin"
+)
+
+// CreateSnippetHandler saves a new snippet of the user
+func CreateSnippetHandler(c *gin.Context) {
+	ctx := c.Request.Context()
+	user, err := util.GetUserFromContext(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var body dto.SnippetBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	snippet, err := services.CreateSnippet(ctx, user, body)
+	if err != nil {
+		snippetError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "snippet created successfully", gin.H{
+		"snippet": snippetView(snippet),
+	})
+}
+
+// ListSnippetsHandler lists the snippets of the user without their code
+func ListSnippetsHandler(c *gin.Context) {
+	ctx := c.Request.Context()
+	user, err := util.GetUserFromContext(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	page, limit := util.GetPageAndLimitFromQuery(c)
+
+	snippets, err := repository.ListSnippets(ctx, user.ID, page, limit)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	views := make([]gin.H, 0, len(snippets))
+	for i := range snippets {
+		view := snippetView(&snippets[i])
+		delete(view, "code")
+		delete(view, "input")
+		views = append(views, view)
+	}
+
+	response.Success(c, http.StatusOK, "snippets fetched successfully", gin.H{
+		"snippets": views,
+		"page":     page,
+		"limit":    limit,
+	})
+}
+
+// GetSnippetHandler returns a snippet of the user
+func GetSnippetHandler(c *gin.Context) {
+	ctx := c.Request.Context()
+	user, err := util.GetUserFromContext(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	objID, err := util.IsValidObjectID(c.Param("snippetId"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid Snippet ID")
+		return
+	}
+
+	snippet, err := services.GetAuthoredSnippet(ctx, user, objID)
+	if err != nil {
+		snippetError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "snippet fetched successfully", gin.H{
+		"snippet": snippetView(snippet),
+	})
+}
+
+// UpdateSnippetHandler saves an edit of a snippet of the user as a new
+// revision
+func UpdateSnippetHandler(c *gin.Context) {
+	ctx := c.Request.Context()
+	user, err := util.GetUserFromContext(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	objID, err := util.IsValidObjectID(c.Param("snippetId"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid Snippet ID")
+		return
+	}
+
+	var body dto.SnippetBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	snippet, err := services.UpdateSnippet(ctx, user, objID, body)
+	if err != nil {
+		snippetError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "snippet updated successfully", gin.H{
+		"snippet": snippetView(snippet),
+	})
+}
+
+// DeleteSnippetHandler deletes a snippet of the user and its revisions
+func DeleteSnippetHandler(c *gin.Context) {
+	ctx := c.Request.Context()
+	user, err := util.GetUserFromContext(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	objID, err := util.IsValidObjectID(c.Param("snippetId"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid Snippet ID")
+		return
+	}
+
+	if err := services.DeleteSnippet(ctx, user, objID); err != nil {
+		snippetError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "snippet deleted successfully", nil)
+}
+
+// ListSnippetRevisionsHandler lists the revisions of a snippet of the
+// user, the latest first, without their code
+func ListSnippetRevisionsHandler(c *gin.Context) {
+	ctx := c.Request.Context()
+	user, err := util.GetUserFromContext(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	objID, err := util.IsValidObjectID(c.Param("snippetId"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid Snippet ID")
+		return
+	}
+
+	snippet, err := services.GetAuthoredSnippet(ctx, user, objID)
+	if err != nil {
+		snippetError(c, err)
+		return
+	}
+
+	revisions, err := repository.ListSnippetRevisions(ctx, snippet.ID)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, "snippet revisions fetched successfully", gin.H{
+		"revisions": revisions,
+	})
+}
+
+// GetSnippetRevisionHandler returns one revision of a snippet of the user
+func GetSnippetRevisionHandler(c *gin.Context) {
+	ctx := c.Request.Context()
+	user, err := util.GetUserFromContext(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	objID, err := util.IsValidObjectID(c.Param("snippetId"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid Snippet ID")
+		return
+	}
+	revision, err := strconv.Atoi(c.Param("revision"))
+	if err != nil || revision < 1 {
+		response.Error(c, http.StatusBadRequest, "Invalid Revision")
+		return
+	}
+
+	snippet, err := services.GetAuthoredSnippet(ctx, user, objID)
+	if err != nil {
+		snippetError(c, err)
+		return
+	}
+
+	rev, err := repository.GetSnippetRevision(ctx, snippet.ID, revision)
+	if err != nil {
+		snippetError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "snippet revision fetched successfully", gin.H{
+		"revision": rev,
+	})
+}
+
+// ForkSnippetHandler copies a snippet of the user, or a shared one, into
+// a new snippet of the user
+func ForkSnippetHandler(c *gin.Context) {
+	ctx := c.Request.Context()
+	user, err := util.GetUserFromContext(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	objID, err := util.IsValidObjectID(c.Param("snippetId"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid Snippet ID")
+		return
+	}
+
+	// The body is optional
+	var body dto.ForkSnippetBody
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&body); err != nil {
+			response.Error(c, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	snippet, err := services.ForkSnippet(ctx, user, objID, body)
+	if err != nil {
+		snippetError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "snippet forked successfully", gin.H{
+		"snippet": snippetView(snippet),
+	})
+}
+
+// ShareSnippetHandler creates the public read-only link of a snippet of
+// the user. The token is only returned here; sharing again replaces it.
+func ShareSnippetHandler(c *gin.Context) {
+	ctx := c.Request.Context()
+	user, err := util.GetUserFromContext(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	objID, err := util.IsValidObjectID(c.Param("snippetId"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid Snippet ID")
+		return
+	}
+
+	var body dto.ShareSnippetBody
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&body); err != nil {
+			response.Error(c, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	snippet, token, err := services.ShareSnippet(ctx, user, objID, body)
+	if err != nil {
+		snippetError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "snippet shared successfully", gin.H{
+		"token":     token,
+		"url":       "/api/v1/share/" + token,
+		"expiresAt": snippet.ShareExpiresAt,
+	})
+}
+
+// UnshareSnippetHandler removes the share link of a snippet of the user
+func UnshareSnippetHandler(c *gin.Context) {
+	ctx := c.Request.Context()
+	user, err := util.GetUserFromContext(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	objID, err := util.IsValidObjectID(c.Param("snippetId"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid Snippet ID")
+		return
+	}
+
+	snippet, err := services.UnshareSnippet(ctx, user, objID)
+	if err != nil {
+		snippetError(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "snippet unshared successfully", gin.H{
+		"snippet": snippetView(snippet),
+	})
+}
+
+// RunSnippetHandler submits a snippet of the user as a new job, like
+// POST /runner/submit, and makes it the snippet's last run
+func RunSnippetHandler(c *gin.Context) {
+	ctx := c.Request.Context()
+	user, err := util.GetUserFromContext(c)
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	objID, err := util.IsValidObjectID(c.Param("snippetId"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid Snippet ID")
+		return
+	}
+
+	snippet, err := services.GetAuthoredSnippet(ctx, user, objID)
+	if err != nil {
+		snippetError(c, err)
+		return
+	}
+
+	job := runnerHandler.SubmitCode(c, user, services.SnippetSubmission(snippet))
+	if job == nil {
+		return
+	}
+	if err := repository.SetSnippetLastJob(ctx, snippet.ID, job.ID); err != nil {
+		log.Printf("failed to record run %s of snippet %s: %v", job.ID.Hex(), snippet.ID.Hex(), err)
+	}
+}
+
+// GetSharedSnippetHandler renders a shared snippet with the output of
+// its last run. It needs no authentication, only the token of the link.
+func GetSharedSnippetHandler(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	snippet, job, err := services.GetSharedSnippet(ctx, c.Param("token"))
+	if err != nil {
+		snippetError(c, err)
+		return
+	}
+
+	view := gin.H{
+		"snippetId": snippet.ID,
+		"name":      snippet.Name,
+		"language":  snippet.Language,
+		"version":   snippet.Version,
+		"code":      snippet.Code,
+		"input":     snippet.Input,
+		"revision":  snippet.Revision,
+		"updatedAt": snippet.UpdatedAt,
+		"lastRun":   nil,
+	}
+	if job != nil {
+		view["lastRun"] = gin.H{
+			"status":              job.Status,
+			"stdout":              job.Stdout,
+			"stderr":              job.Stderr,
+			"sandboxErrorType":    job.SandboxErrorType,
+			"sandboxErrorMessage": job.SandboxErrorMessage,
+			"exitCode":            job.ExitCode,
+			"finishedAt":          job.FinishedAt,
+		}
+	}
+
+	response.Success(c, http.StatusOK, "snippet fetched successfully", gin.H{
+		"snippet": view,
+	})
+}
+
+func snippetError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrInvalidSnippet):
+		response.Error(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, repository.ErrSnippetNotFound), errors.Is(err, services.ErrSnippetNotVisible):
+		response.Error(c, http.StatusNotFound, "snippet not found")
+	case errors.Is(err, repository.ErrSnippetRevisionNotFound):
+		response.Error(c, http.StatusNotFound, "snippet revision not found")
+	case errors.Is(err, services.ErrNotSnippetAuthor):
+		response.Error(c, http.StatusForbidden, err.Error())
+	case errors.Is(err, services.ErrShareLinkExpired):
+		response.Error(c, http.StatusGone, err.Error())
+	default:
+		response.Error(c, http.StatusInternalServerError, err.Error())
+	}
+}
+
+// snippetView is the API representation of a snippet for its author.
+func snippetView(s *models.Snippet) gin.H {
+	return gin.H{
+		"snippetId":      s.ID,
+		"name":           s.Name,
+		"language":       s.Language,
+		"version":        s.Version,
+		"code":           s.Code,
+		"input":          s.Input,
+		"revision":       s.Revision,
+		"forkedFrom":     s.ForkedFrom,
+		"forkedRevision": s.ForkedRevision,
+		"lastJobId":      s.LastJobID,
+		"shared":         s.IsShared(time.Now()),
+		"shareExpiresAt": s.ShareExpiresAt,
+		"createdAt":      s.CreatedAt,
+		"updatedAt":      s.UpdatedAt,
+	}
+}
@@ -51,6 +51,10 @@ type ApiLog struct {
 	StartedAt  time.Time `bson:"startedAt,omitempty" json:"started_at,omitempty"`
 	FinishedAt time.Time `bson:"finishedAt,omitempty" json:"finished_at,omitempty"`
 	QueuedAt   time.Time `bson:"queuedAt,omitempty" json:"queued_at,omitempty"`
+
+	// ArchivedAt is when data retention archived the log and removed
+	// its request body.
+	ArchivedAt *time.Time `bson:"archivedAt,omitempty" json:"archived_at,omitempty"`
 }
 
 func CreateApiLogIndexes() error {
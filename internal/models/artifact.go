@@ -0,0 +1,10 @@
+package models
+
+// Artifact is a file a job produced and that matched one of its
+// artifact globs. The content lives in object storage under Key.
+type Artifact struct {
+	Name        string `bson:"name" json:"name"`
+	Size        int64  `bson:"size" json:"size"`
+	ContentType string `bson:"contentType" json:"contentType"`
+	Key         string `bson:"key" json:"-"`
+}
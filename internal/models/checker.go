@@ -0,0 +1,32 @@
+package models
+
+type CheckerType string
+type Verdict string
+
+const (
+	CheckerExact          CheckerType = "exact"
+	CheckerTokens         CheckerType = "tokens"
+	CheckerFloat          CheckerType = "float"
+	CheckerUnorderedLines CheckerType = "unordered-lines"
+	CheckerCustom         CheckerType = "custom"
+
+	VerdictAccepted          Verdict = "Accepted"
+	VerdictWrongAnswer       Verdict = "WrongAnswer"
+	VerdictPresentationError Verdict = "PresentationError"
+	VerdictCheckerFailed     Verdict = "CheckerFailed"
+)
+
+// Checker decides whether the output of a job is correct. Built-in
+// checkers compare stdout with Job.ExpectedOutput; a custom checker is
+// a testlib-style program run in its own sandbox.
+type Checker struct {
+	Type CheckerType `bson:"type" json:"type"`
+
+	// Epsilon is the absolute or relative tolerance of the float checker.
+	Epsilon float64 `bson:"epsilon,omitempty" json:"epsilon,omitempty"`
+
+	// Language, Version and Code of a custom checker.
+	Language string `bson:"language,omitempty" json:"language,omitempty"`
+	Version  string `bson:"version,omitempty" json:"version,omitempty"`
+	Code     string `bson:"code,omitempty" json:"code,omitempty"`
+}
@@ -0,0 +1,107 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kamva/mgm/v3"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type ScoringMode string
+
+const (
+	// ScoringICPC ranks by solved problems, then by penalty time: the
+	// minutes to each first accepted submission plus PenaltyMinutes per
+	// rejected attempt before it.
+	ScoringICPC ScoringMode = "icpc"
+
+	// ScoringIOI ranks by points, each problem scoring its points times
+	// the share of tests passed by the best submission; ties go to the
+	// earlier final score.
+	ScoringIOI ScoringMode = "ioi"
+)
+
+// Contest is a timed set of problems. Registered participants can
+// submit to its problems between StartAt and EndAt.
+type Contest struct {
+	mgm.DefaultModel `bson:",inline"`
+
+	AuthorID primitive.ObjectID `bson:"authorId" json:"authorId"`
+
+	Title       string      `bson:"title" json:"title"`
+	Description string      `bson:"description,omitempty" json:"description,omitempty"`
+	Mode        ScoringMode `bson:"mode" json:"mode"`
+
+	StartAt time.Time `bson:"startAt" json:"startAt"`
+	EndAt   time.Time `bson:"endAt" json:"endAt"`
+
+	// FreezeAt freezes the scoreboard: submissions from then on are
+	// shown as pending to everyone but the author until Unfrozen.
+	FreezeAt time.Time `bson:"freezeAt,omitempty" json:"freezeAt,omitempty"`
+	Unfrozen bool      `bson:"unfrozen,omitempty" json:"unfrozen,omitempty"`
+
+	PenaltyMinutes int64            `bson:"penaltyMinutes" json:"penaltyMinutes"`
+	Problems       []ContestProblem `bson:"problems" json:"problems"`
+}
+
+type ContestProblem struct {
+	Label     string             `bson:"label" json:"label"`
+	ProblemID primitive.ObjectID `bson:"problemId" json:"problemId"`
+
+	// Points is the full score of the problem in IOI mode.
+	Points int64 `bson:"points,omitempty" json:"points,omitempty"`
+}
+
+// Problem returns the contest problem with the given ID.
+func (c *Contest) Problem(problemID primitive.ObjectID) (ContestProblem, bool) {
+	for _, p := range c.Problems {
+		if p.ProblemID == problemID {
+			return p, true
+		}
+	}
+	return ContestProblem{}, false
+}
+
+// IsFrozen reports whether the scoreboard is frozen at now.
+func (c *Contest) IsFrozen(now time.Time) bool {
+	return !c.FreezeAt.IsZero() && !now.Before(c.FreezeAt) && !c.Unfrozen
+}
+
+// ContestParticipant registers a user for a contest.
+type ContestParticipant struct {
+	mgm.DefaultModel `bson:",inline"`
+
+	ContestID primitive.ObjectID `bson:"contestId" json:"contestId"`
+	UserID    primitive.ObjectID `bson:"userId" json:"userId"`
+}
+
+func CreateContestIndexes() error {
+	if _, err := mgm.Coll(&Contest{}).Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "startAt", Value: -1}},
+			Options: options.Index().SetName("start_idx"),
+		},
+	}); err != nil {
+		return err
+	}
+
+	if _, err := mgm.Coll(&ContestParticipant{}).Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "contestId", Value: 1},
+				{Key: "userId", Value: 1},
+			},
+			Options: options.Index().SetName("contest_user_unique_idx").SetUnique(true),
+		},
+	}); err != nil {
+		return err
+	}
+
+	fmt.Println("Contest indexes created successfully")
+	return nil
+}
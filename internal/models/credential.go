@@ -21,6 +21,10 @@ type Credential struct {
 	Env        string             `bson:"env" json:"env"`                                   // e.g., "live", "test"
 	IsActive   bool               `bson:"isActive" json:"isActive"`                         // user can disable it
 	LastUsedAt *time.Time         `bson:"lastUsedAt,omitempty" json:"lastUsedAt,omitempty"` // Pointer so it can be nil
+
+	// Libraries are the default library sets for submissions made with
+	// this credential, at most one per language.
+	Libraries []string `bson:"libraries,omitempty" json:"libraries"`
 }
 
 func CreateCredentialIndexes() error {
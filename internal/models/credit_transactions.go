@@ -30,6 +30,11 @@ const (
 	// Credits OUT
 	CreditReasonSubmission CreditTransactionReason = "submission"
 	CreditReasonRerun      CreditTransactionReason = "rerun"
+	CreditReasonSession    CreditTransactionReason = "session"
+
+	// CreditReasonCachedResult charges submissions served from the
+	// result cache instead of running.
+	CreditReasonCachedResult CreditTransactionReason = "cached_result"
 )
 
 type CreditTransaction struct {
@@ -0,0 +1,9 @@
+package models
+
+// DataFile is an extra read-only file of a submission, e.g. a test
+// fixture, placed in the job directory next to the code.
+type DataFile struct {
+	// Name is the path relative to the job directory, e.g. "data/in.csv".
+	Name    string `bson:"name" json:"name"`
+	Content []byte `bson:"content" json:"content"`
+}
@@ -0,0 +1,35 @@
+package models
+
+type DiagnosticSeverity string
+
+const (
+	SeverityError   DiagnosticSeverity = "error"
+	SeverityWarning DiagnosticSeverity = "warning"
+	SeverityNote    DiagnosticSeverity = "note"
+)
+
+// Diagnostic is a compiler message or an uncaught runtime error parsed
+// from the output of a run. Files are relative to the job directory.
+type Diagnostic struct {
+	Severity DiagnosticSeverity `bson:"severity" json:"severity"`
+
+	// Code identifies the kind of problem where the tool reports one,
+	// e.g. "TS2322", "ZeroDivisionError" or "java.lang.NullPointerException".
+	Code    string `bson:"code,omitempty" json:"code,omitempty"`
+	Message string `bson:"message" json:"message"`
+
+	File   string `bson:"file,omitempty" json:"file,omitempty"`
+	Line   int    `bson:"line,omitempty" json:"line,omitempty"`
+	Column int    `bson:"column,omitempty" json:"column,omitempty"`
+
+	// Frames is the stack of a runtime error, innermost call first.
+	Frames []StackFrame `bson:"frames,omitempty" json:"frames,omitempty"`
+}
+
+// StackFrame is one call of a runtime stack trace.
+type StackFrame struct {
+	Function string `bson:"function,omitempty" json:"function,omitempty"`
+	File     string `bson:"file,omitempty" json:"file,omitempty"`
+	Line     int    `bson:"line,omitempty" json:"line,omitempty"`
+	Column   int    `bson:"column,omitempty" json:"column,omitempty"`
+}
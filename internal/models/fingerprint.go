@@ -0,0 +1,9 @@
+package models
+
+// Fingerprint is a winnowed hash of a run of normalized tokens of a
+// job's code, with the lines the run spans (see pkg/similarity).
+type Fingerprint struct {
+	Hash      int64 `bson:"h" json:"hash"`
+	StartLine int   `bson:"s" json:"startLine"`
+	EndLine   int   `bson:"e" json:"endLine"`
+}
@@ -0,0 +1,58 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kamva/mgm/v3"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// IdempotencyKey records a submission made with an Idempotency-Key
+// header, so a retried request returns the job of the first one instead
+// of queueing (and charging) it again. JobID is nil while the first
+// request is still in progress. Keys are dropped by MongoDB after
+// ExpiresAt.
+type IdempotencyKey struct {
+	mgm.DefaultModel `bson:",inline"`
+
+	UserID primitive.ObjectID `bson:"userId" json:"userId"`
+	Key    string             `bson:"key" json:"key"`
+
+	// RequestHash is the hash of the request body the key was first used with
+	RequestHash string `bson:"requestHash" json:"-"`
+
+	JobID *primitive.ObjectID `bson:"jobId,omitempty" json:"jobId,omitempty"`
+
+	ExpiresAt time.Time `bson:"expiresAt" json:"expiresAt"`
+}
+
+func CreateIdempotencyKeyIndexes() error {
+	coll := mgm.Coll(&IdempotencyKey{})
+
+	indexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "userId", Value: 1},
+				{Key: "key", Value: 1},
+			},
+			Options: options.Index().SetName("user_key_unique_idx").SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "expiresAt", Value: 1}},
+			Options: options.Index().SetName("expires_ttl_idx").SetExpireAfterSeconds(0),
+		},
+	}
+
+	_, err := coll.Indexes().CreateMany(context.Background(), indexes)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("Idempotency key indexes created successfully")
+	return nil
+}
@@ -1,89 +1,225 @@
-package models
-
-import (
-	"context"
-	"log"
-	"time"
-
-	"github.com/kamva/mgm/v3"
-	"go.mongodb.org/mongo-driver/bson"
-	"go.mongodb.org/mongo-driver/bson/primitive"
-	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
-)
-
-type RunStatus string
-type SandboxError string
-
-const (
-	StatusQueued  RunStatus = "queued"
-	StatusRunning RunStatus = "running"
-	StatusSuccess RunStatus = "success"
-	StatusFailed  RunStatus = "failed"
-
-	ErrTLE              SandboxError = "TLE"
-	ErrMLE              SandboxError = "MLE"
-	ErrCompilationError SandboxError = "CompilationError"
-	ErrRuntimeError     SandboxError = "RuntimeError"
-	ErrSandboxError     SandboxError = "SandboxError"
-	ErrInternalError    SandboxError = "InternalError"
-
-	MsgTLE              = "Time Limit Exceeded: the program ran longer than allowed."
-	MsgMLE              = "Memory Limit Exceeded: the program used more memory than allowed."
-	MsgCompilationError = "Compilation failed. See error details."
-	MsgRuntimeError     = "Runtime Error: the program crashed during execution."
-	MsgSandboxError     = "Sandbox Error: execution environment failed."
-	MsgInternalError    = "Internal Error: something went wrong on the server."
-)
-
-// JobStats represents aggregated job execution statistics
-type JobStats struct {
-	TotalExecutedJobs int64   `json:"totalExecutedJobs"`
-	AvgExecutionMs    float64 `json:"avgExecutionMs"`
-	SuccessJobs       int64   `json:"successJobs"`
-	FailedJobs        int64   `json:"failedJobs"`
-}
-
-type Job struct {
-	mgm.DefaultModel `bson:",inline"`
-
-	UserID primitive.ObjectID `bson:"userId" json:"userId"`
-
-	Language            string        `bson:"language" json:"language"`
-	Code                string        `bson:"code" json:"code"`
-	Input               string        `bson:"input,omitempty" json:"input,omitempty"`
-	Status              RunStatus     `bson:"status" json:"status"`
-	Stdout              string        `bson:"stdout,omitempty" json:"stdout,omitempty"`
-	Stderr              string        `bson:"stderr,omitempty" json:"stderr,omitempty"`
-	SandboxErrorType    *SandboxError `bson:"errorType,omitempty" json:"error_type,omitempty"`
-	SandboxErrorMessage string        `bson:"errorMessage,omitempty" json:"error_message,omitempty"`
-	ExitCode            int64         `bson:"exitCode,omitempty" json:"exit_code,omitempty"`
-	StartedAt           time.Time     `bson:"startedAt,omitempty" json:"started_at,omitempty"`
-	FinishedAt          time.Time     `bson:"finishedAt,omitempty" json:"finished_at,omitempty"`
-	QueuedAt            time.Time     `bson:"queuedAt,omitempty" json:"queued_at,omitempty"`
-
-	User *User `bson:"-" json:"user,omitempty"`
-}
-
-func CreateJobIndexes() error {
-	coll := mgm.Coll(&Job{})
-
-	indexes := []mongo.IndexModel{
-		{
-			Keys: bson.D{
-				{Key: "userId", Value: 1},
-				{Key: "_id", Value: 1},
-			},
-			Options: options.Index().
-				SetName("user_job_compound_idx"),
-		},
-	}
-
-	_, err := coll.Indexes().CreateMany(context.Background(), indexes)
-	if err != nil {
-		return err
-	}
-
-	log.Println("Job indexes created successfully")
-	return nil
-}
+package models
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/kamva/mgm/v3"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type RunStatus string
+type SandboxError string
+
+const (
+	StatusQueued  RunStatus = "queued"
+	StatusRunning RunStatus = "running"
+	StatusSuccess RunStatus = "success"
+	StatusFailed  RunStatus = "failed"
+
+	ErrTLE              SandboxError = "TLE"
+	ErrMLE              SandboxError = "MLE"
+	ErrCompilationError SandboxError = "CompilationError"
+	ErrRuntimeError     SandboxError = "RuntimeError"
+	ErrSandboxError     SandboxError = "SandboxError"
+	ErrInternalError    SandboxError = "InternalError"
+
+	MsgTLE              = "Time Limit Exceeded: the program ran longer than allowed."
+	MsgMLE              = "Memory Limit Exceeded: the program used more memory than allowed."
+	MsgCompilationError = "Compilation failed. See error details."
+	MsgRuntimeError     = "Runtime Error: the program crashed during execution."
+	MsgSandboxError     = "Sandbox Error: execution environment failed."
+	MsgInternalError    = "Internal Error: something went wrong on the server."
+
+	// Verdicts of runs judged by an interactor (see Problem.Interactor)
+	ErrWrongAnswer       SandboxError = "WrongAnswer"
+	ErrPresentationError SandboxError = "PresentationError"
+	ErrInteractorFailed  SandboxError = "InteractorFailed"
+
+	MsgWrongAnswer       = "Wrong Answer: the interactor rejected the program's answers."
+	MsgPresentationError = "Presentation Error: the program's output is not in the expected format."
+	MsgInteractorFailed  = "Interactor Failed: the judge could not check this run."
+)
+
+// JobStats represents aggregated job execution statistics
+type JobStats struct {
+	TotalExecutedJobs int64   `json:"totalExecutedJobs"`
+	AvgExecutionMs    float64 `json:"avgExecutionMs"`
+	SuccessJobs       int64   `json:"successJobs"`
+	FailedJobs        int64   `json:"failedJobs"`
+}
+
+type Job struct {
+	mgm.DefaultModel `bson:",inline"`
+
+	UserID primitive.ObjectID `bson:"userId" json:"userId"`
+
+	Language            string        `bson:"language" json:"language"`
+	Version             string        `bson:"version,omitempty" json:"version,omitempty"`
+	Library             string        `bson:"library,omitempty" json:"library,omitempty"`
+	Code                string        `bson:"code" json:"code"`
+	Input               string        `bson:"input,omitempty" json:"input,omitempty"`
+	Status              RunStatus     `bson:"status" json:"status"`
+	Stdout              string        `bson:"stdout,omitempty" json:"stdout,omitempty"`
+	Stderr              string        `bson:"stderr,omitempty" json:"stderr,omitempty"`
+	StdoutBlob          *OutputBlob   `bson:"stdoutBlob,omitempty" json:"stdout_blob,omitempty"`
+	StderrBlob          *OutputBlob   `bson:"stderrBlob,omitempty" json:"stderr_blob,omitempty"`
+	SandboxErrorType    *SandboxError `bson:"errorType,omitempty" json:"error_type,omitempty"`
+	SandboxErrorMessage string        `bson:"errorMessage,omitempty" json:"error_message,omitempty"`
+	ExitCode            int64         `bson:"exitCode,omitempty" json:"exit_code,omitempty"`
+	Diagnostics         []Diagnostic  `bson:"diagnostics,omitempty" json:"diagnostics,omitempty"`
+	ArtifactGlobs       []string      `bson:"artifactGlobs,omitempty" json:"artifact_globs,omitempty"`
+	Artifacts           []Artifact    `bson:"artifacts,omitempty" json:"artifacts,omitempty"`
+	ArtifactsSkipped    int           `bson:"artifactsSkipped,omitempty" json:"artifacts_skipped,omitempty"`
+	StartedAt           time.Time     `bson:"startedAt,omitempty" json:"started_at,omitempty"`
+	FinishedAt          time.Time     `bson:"finishedAt,omitempty" json:"finished_at,omitempty"`
+	QueuedAt            time.Time     `bson:"queuedAt,omitempty" json:"queued_at,omitempty"`
+
+	Args  []string          `bson:"args,omitempty" json:"args,omitempty"`
+	Env   map[string]string `bson:"env,omitempty" json:"env,omitempty"`
+	Files []DataFile        `bson:"files,omitempty" json:"files,omitempty"`
+
+	// Checker judges Stdout against ExpectedOutput once the run
+	// succeeded; the outcome is stored in Verdict and VerdictMessage.
+	ExpectedOutput string   `bson:"expectedOutput,omitempty" json:"expected_output,omitempty"`
+	Checker        *Checker `bson:"checker,omitempty" json:"checker,omitempty"`
+	Verdict        Verdict  `bson:"verdict,omitempty" json:"verdict,omitempty"`
+	VerdictMessage string   `bson:"verdictMessage,omitempty" json:"verdict_message,omitempty"`
+
+	// ProblemID runs the job against the tests of a problem instead of
+	// Input; TestResults holds the outcome of each test that ran.
+	ProblemID   *primitive.ObjectID `bson:"problemId,omitempty" json:"problem_id,omitempty"`
+	TestResults []TestResult        `bson:"testResults,omitempty" json:"test_results,omitempty"`
+
+	// ContestID is the contest the job was submitted to. RunAllTests
+	// keeps testing after a failed test, for partial (IOI) scores.
+	ContestID   *primitive.ObjectID `bson:"contestId,omitempty" json:"contest_id,omitempty"`
+	RunAllTests bool                `bson:"runAllTests,omitempty" json:"run_all_tests,omitempty"`
+
+	// Fingerprints of Code for similarity checks, taken when the job
+	// is created.
+	Fingerprints []Fingerprint `bson:"fingerprints,omitempty" json:"-"`
+
+	// ParentJobID is the job this one reruns and RootJobID the first job
+	// of the rerun chain. TimeLimitMs lowers the runtime's time limit.
+	ParentJobID *primitive.ObjectID `bson:"parentJobId,omitempty" json:"parent_job_id,omitempty"`
+	RootJobID   *primitive.ObjectID `bson:"rootJobId,omitempty" json:"root_job_id,omitempty"`
+	TimeLimitMs int64               `bson:"timeLimitMs,omitempty" json:"time_limit_ms,omitempty"`
+
+	// CacheKey opts the job into the result cache (see
+	// models.CachedResult); FromCache is set when its result was served
+	// from there instead of running.
+	CacheKey  string `bson:"cacheKey,omitempty" json:"cache_key,omitempty"`
+	FromCache bool   `bson:"fromCache,omitempty" json:"from_cache,omitempty"`
+
+	// LatestSnippetRun marks the job shown by the share link of a
+	// snippet, which data retention keeps whole.
+	LatestSnippetRun bool `bson:"latestSnippetRun,omitempty" json:"-"`
+
+	// ArchivedAt is when data retention archived the job and removed
+	// its code, input and output (see config.RetentionPolicy).
+	ArchivedAt *time.Time `bson:"archivedAt,omitempty" json:"archived_at,omitempty"`
+
+	User *User `bson:"-" json:"user,omitempty"`
+}
+
+// CreditReason is the reason the job is charged under.
+func (j *Job) CreditReason() CreditTransactionReason {
+	if j.ParentJobID != nil {
+		return CreditReasonRerun
+	}
+	return CreditReasonSubmission
+}
+
+func CreateJobIndexes() error {
+	coll := mgm.Coll(&Job{})
+
+	indexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "userId", Value: 1},
+				{Key: "_id", Value: 1},
+			},
+			Options: options.Index().
+				SetName("user_job_compound_idx"),
+		},
+		{
+			Keys: bson.D{
+				{Key: "contestId", Value: 1},
+				{Key: "queuedAt", Value: 1},
+			},
+			Options: options.Index().
+				SetName("contest_queued_idx").
+				SetSparse(true),
+		},
+		{
+			Keys: bson.D{
+				{Key: "problemId", Value: 1},
+				{Key: "queuedAt", Value: -1},
+			},
+			Options: options.Index().
+				SetName("problem_queued_idx").
+				SetSparse(true),
+		},
+		{
+			Keys: bson.D{
+				{Key: "rootJobId", Value: 1},
+				{Key: "queuedAt", Value: 1},
+			},
+			Options: options.Index().
+				SetName("root_queued_idx").
+				SetSparse(true),
+		},
+		// Filters of GET /jobs, newest first
+		{
+			Keys: bson.D{
+				{Key: "userId", Value: 1},
+				{Key: "status", Value: 1},
+				{Key: "_id", Value: -1},
+			},
+			Options: options.Index().
+				SetName("user_status_job_idx"),
+		},
+		{
+			Keys: bson.D{
+				{Key: "userId", Value: 1},
+				{Key: "language", Value: 1},
+				{Key: "_id", Value: -1},
+			},
+			Options: options.Index().
+				SetName("user_language_job_idx"),
+		},
+		{
+			Keys: bson.D{
+				{Key: "userId", Value: 1},
+				{Key: "errorType", Value: 1},
+				{Key: "_id", Value: -1},
+			},
+			Options: options.Index().
+				SetName("user_error_job_idx"),
+		},
+		{
+			// Code is searched word by word, without stemming or
+			// stop words
+			Keys: bson.D{
+				{Key: "userId", Value: 1},
+				{Key: "code", Value: "text"},
+			},
+			Options: options.Index().
+				SetName("user_code_text_idx").
+				SetDefaultLanguage("none"),
+		},
+	}
+
+	_, err := coll.Indexes().CreateMany(context.Background(), indexes)
+	if err != nil {
+		return err
+	}
+
+	log.Println("Job indexes created successfully")
+	return nil
+}
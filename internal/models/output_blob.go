@@ -0,0 +1,10 @@
+package models
+
+// OutputBlob is an output of a job larger than config.InlineOutputSize.
+// The whole output lives in object storage under Key; SHA256 is the hex
+// SHA-256 of its content.
+type OutputBlob struct {
+	Key    string `bson:"key" json:"-"`
+	Size   int64  `bson:"size" json:"size"`
+	SHA256 string `bson:"sha256" json:"sha256"`
+}
@@ -0,0 +1,95 @@
+package models
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kamva/mgm/v3"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Problem is a task stored with its tests. Submissions reference it by
+// ID instead of sending input, and the worker runs them against the
+// samples and then the hidden tests.
+type Problem struct {
+	mgm.DefaultModel `bson:",inline"`
+
+	AuthorID primitive.ObjectID `bson:"authorId" json:"authorId"`
+
+	Title     string `bson:"title" json:"title"`
+	Statement string `bson:"statement" json:"statement"`
+
+	// TimeLimitMs is the time limit of each test, capped by the
+	// runtime's limit; zero uses the runtime's limit.
+	TimeLimitMs int64 `bson:"timeLimitMs,omitempty" json:"timeLimitMs,omitempty"`
+
+	// Languages restricts submissions to these languages; empty allows
+	// every language.
+	Languages []string `bson:"languages,omitempty" json:"languages,omitempty"`
+
+	// Samples are shown to everyone. Tests are hidden: they are only
+	// returned to the author and never included in job results.
+	Samples []TestCase `bson:"samples,omitempty" json:"samples,omitempty"`
+	Tests   []TestCase `bson:"tests,omitempty" json:"-"`
+
+	// Checker judges the output of each test; nil compares exactly.
+	Checker *Checker `bson:"checker,omitempty" json:"-"`
+
+	// Interactor makes the problem interactive: it runs next to the
+	// program of each submission (see Interactor). Like the tests, only
+	// the author gets its code.
+	Interactor *Interactor `bson:"interactor,omitempty" json:"-"`
+}
+
+// Interactor is testlib-style judge code that talks to the program over
+// stdin / stdout. It runs in the submission's runtime, so Language is
+// the only language the problem accepts. It reads the test input from
+// its argument and its exit code decides the verdict.
+type Interactor struct {
+	Language string `bson:"language" json:"language"`
+	Code     string `bson:"code" json:"code"`
+}
+
+type TestCase struct {
+	Input          string `bson:"input" json:"input"`
+	ExpectedOutput string `bson:"expectedOutput" json:"expectedOutput"`
+}
+
+// TestResult is the outcome of one test of a problem submission.
+// Tests are numbered from 1, samples first. Only samples keep their
+// verdict message, since a checker may quote the expected output.
+type TestResult struct {
+	Test   int  `bson:"test" json:"test"`
+	Sample bool `bson:"sample,omitempty" json:"sample,omitempty"`
+
+	Verdict        Verdict      `bson:"verdict,omitempty" json:"verdict,omitempty"`
+	VerdictMessage string       `bson:"verdictMessage,omitempty" json:"verdict_message,omitempty"`
+	ErrorType      SandboxError `bson:"errorType,omitempty" json:"error_type,omitempty"`
+	ExitCode       int64        `bson:"exitCode,omitempty" json:"exit_code,omitempty"`
+	TimeMs         int64        `bson:"timeMs" json:"time_ms"`
+}
+
+func CreateProblemIndexes() error {
+	coll := mgm.Coll(&Problem{})
+
+	indexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "authorId", Value: 1},
+				{Key: "created_at", Value: -1},
+			},
+			Options: options.Index().SetName("author_created_idx"),
+		},
+	}
+
+	_, err := coll.Indexes().CreateMany(context.Background(), indexes)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("Problem indexes created successfully")
+	return nil
+}
@@ -0,0 +1,71 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kamva/mgm/v3"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// CachedResult is the result of a successful run, served to later jobs
+// that opt into the cache with the same Key: the hash of their runtime,
+// code, input and limits (see services.ResultCacheKey). Entries are
+// dropped by MongoDB after ExpiresAt.
+type CachedResult struct {
+	mgm.DefaultModel `bson:",inline"`
+
+	Key string `bson:"key" json:"key"`
+
+	// The runtime the result was produced on, for invalidation
+	Language string `bson:"language" json:"language"`
+	Version  string `bson:"version" json:"version"`
+	Library  string `bson:"library,omitempty" json:"library,omitempty"`
+	Image    string `bson:"image,omitempty" json:"image,omitempty"`
+
+	// JobID is the job that produced the result
+	JobID primitive.ObjectID `bson:"jobId" json:"jobId"`
+
+	Stdout         string       `bson:"stdout,omitempty" json:"stdout,omitempty"`
+	Stderr         string       `bson:"stderr,omitempty" json:"stderr,omitempty"`
+	ExitCode       int64        `bson:"exitCode,omitempty" json:"exitCode,omitempty"`
+	Diagnostics    []Diagnostic `bson:"diagnostics,omitempty" json:"diagnostics,omitempty"`
+	Verdict        Verdict      `bson:"verdict,omitempty" json:"verdict,omitempty"`
+	VerdictMessage string       `bson:"verdictMessage,omitempty" json:"verdictMessage,omitempty"`
+
+	ExpiresAt time.Time `bson:"expiresAt" json:"expiresAt"`
+}
+
+func CreateResultCacheIndexes() error {
+	coll := mgm.Coll(&CachedResult{})
+
+	indexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "key", Value: 1}},
+			Options: options.Index().SetName("key_unique_idx").SetUnique(true),
+		},
+		{
+			Keys: bson.D{
+				{Key: "language", Value: 1},
+				{Key: "version", Value: 1},
+			},
+			Options: options.Index().SetName("runtime_idx"),
+		},
+		{
+			Keys:    bson.D{{Key: "expiresAt", Value: 1}},
+			Options: options.Index().SetName("expires_ttl_idx").SetExpireAfterSeconds(0),
+		},
+	}
+
+	_, err := coll.Indexes().CreateMany(context.Background(), indexes)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("Result cache indexes created successfully")
+	return nil
+}
@@ -0,0 +1,85 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kamva/mgm/v3"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type SessionStatus string
+type SessionCloseReason string
+
+const (
+	SessionPending SessionStatus = "pending" // created, waiting for the WebSocket
+	SessionActive  SessionStatus = "active"
+	SessionClosed  SessionStatus = "closed"
+
+	SessionExited       SessionCloseReason = "exited"       // the program exited
+	SessionWallTimeout  SessionCloseReason = "wall_timeout" // ran into the wall-clock limit
+	SessionIdleTimeout  SessionCloseReason = "idle_timeout" // no input or output for too long
+	SessionClientClosed SessionCloseReason = "client_closed"
+	SessionFailed       SessionCloseReason = "error"
+)
+
+// Session is an interactive execution: the program runs in a pooled
+// sandbox with stdin and stdout/stderr streamed over a WebSocket.
+type Session struct {
+	mgm.DefaultModel `bson:",inline"`
+
+	UserID primitive.ObjectID `bson:"userId" json:"userId"`
+
+	Language string            `bson:"language" json:"language"`
+	Version  string            `bson:"version,omitempty" json:"version,omitempty"`
+	Library  string            `bson:"library,omitempty" json:"library,omitempty"`
+	Code     string            `bson:"code" json:"code"`
+	Args     []string          `bson:"args,omitempty" json:"args,omitempty"`
+	Env      map[string]string `bson:"env,omitempty" json:"env,omitempty"`
+	Files    []DataFile        `bson:"files,omitempty" json:"files,omitempty"`
+
+	Status SessionStatus `bson:"status" json:"status"`
+
+	// TokenHash is the SHA-256 of the one-time token the client
+	// connects with; pending sessions expire at ExpiresAt.
+	TokenHash string    `bson:"tokenHash" json:"-"`
+	ExpiresAt time.Time `bson:"expiresAt" json:"expires_at"`
+
+	StartedAt      time.Time          `bson:"startedAt,omitempty" json:"started_at,omitempty"`
+	EndedAt        time.Time          `bson:"endedAt,omitempty" json:"ended_at,omitempty"`
+	DurationMs     int64              `bson:"durationMs,omitempty" json:"duration_ms,omitempty"`
+	CloseReason    SessionCloseReason `bson:"closeReason,omitempty" json:"close_reason,omitempty"`
+	ErrorMessage   string             `bson:"errorMessage,omitempty" json:"error_message,omitempty"`
+	ExitCode       int64              `bson:"exitCode,omitempty" json:"exit_code,omitempty"`
+	CreditsCharged int64              `bson:"creditsCharged,omitempty" json:"credits_charged,omitempty"`
+
+	// CreditsReserved were deducted when the session started, for its
+	// whole wall-clock limit; the part not charged is refunded at the end.
+	CreditsReserved int64 `bson:"creditsReserved,omitempty" json:"credits_reserved,omitempty"`
+}
+
+func CreateSessionIndexes() error {
+	coll := mgm.Coll(&Session{})
+
+	indexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "userId", Value: 1},
+				{Key: "created_at", Value: -1},
+			},
+			Options: options.Index().SetName("user_created_idx"),
+		},
+	}
+
+	_, err := coll.Indexes().CreateMany(context.Background(), indexes)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("Session indexes created successfully")
+	return nil
+}
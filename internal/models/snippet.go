@@ -0,0 +1,99 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kamva/mgm/v3"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Snippet is a program saved by a user under a name. Every saved edit
+// is kept as a SnippetRevision, Revision being the latest.
+type Snippet struct {
+	mgm.DefaultModel `bson:",inline"`
+
+	AuthorID primitive.ObjectID `bson:"authorId" json:"authorId"`
+	Name     string             `bson:"name" json:"name"`
+	Language string             `bson:"language" json:"language"`
+	Version  string             `bson:"version" json:"version"`
+	Code     string             `bson:"code" json:"code"`
+	Input    string             `bson:"input" json:"input"`
+	Revision int                `bson:"revision" json:"revision"`
+
+	// ForkedFrom is the snippet this one was copied from, at
+	// ForkedRevision.
+	ForkedFrom     *primitive.ObjectID `bson:"forkedFrom,omitempty" json:"forkedFrom,omitempty"`
+	ForkedRevision int                 `bson:"forkedRevision,omitempty" json:"forkedRevision,omitempty"`
+
+	// LastJobID is the latest run of the snippet, shown on its share link.
+	LastJobID *primitive.ObjectID `bson:"lastJobId,omitempty" json:"lastJobId,omitempty"`
+
+	// ShareTokenHash is the SHA-256 of the token of the public read-only
+	// link of the snippet; the link stops working at ShareExpiresAt
+	// when set. Both are only written by repository.UpdateSnippetShare.
+	ShareTokenHash string     `bson:"shareTokenHash,omitempty" json:"-"`
+	ShareExpiresAt *time.Time `bson:"shareExpiresAt,omitempty" json:"shareExpiresAt,omitempty"`
+}
+
+// IsShared reports whether the share link of the snippet works at now.
+func (s *Snippet) IsShared(now time.Time) bool {
+	return s.ShareTokenHash != "" && (s.ShareExpiresAt == nil || now.Before(*s.ShareExpiresAt))
+}
+
+// SnippetRevision is a saved version of a snippet.
+type SnippetRevision struct {
+	mgm.DefaultModel `bson:",inline"`
+
+	SnippetID primitive.ObjectID `bson:"snippetId" json:"snippetId"`
+	Revision  int                `bson:"revision" json:"revision"`
+	Name      string             `bson:"name" json:"name"`
+	Language  string             `bson:"language" json:"language"`
+	Version   string             `bson:"version,omitempty" json:"version,omitempty"`
+	Code      string             `bson:"code" json:"code"`
+	Input     string             `bson:"input,omitempty" json:"input,omitempty"`
+}
+
+func CreateSnippetIndexes() error {
+	indexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "authorId", Value: 1},
+				{Key: "updated_at", Value: -1},
+			},
+			Options: options.Index().SetName("author_updated_idx"),
+		},
+		{
+			Keys: bson.D{{Key: "shareTokenHash", Value: 1}},
+			Options: options.Index().
+				SetName("share_token_idx").
+				SetUnique(true).
+				SetSparse(true),
+		},
+	}
+	if _, err := mgm.Coll(&Snippet{}).Indexes().CreateMany(context.Background(), indexes); err != nil {
+		return err
+	}
+
+	revisionIndexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "snippetId", Value: 1},
+				{Key: "revision", Value: -1},
+			},
+			Options: options.Index().
+				SetName("snippet_revision_idx").
+				SetUnique(true),
+		},
+	}
+	if _, err := mgm.Coll(&SnippetRevision{}).Indexes().CreateMany(context.Background(), revisionIndexes); err != nil {
+		return err
+	}
+
+	fmt.Println("Snippet indexes created successfully")
+	return nil
+}
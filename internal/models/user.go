@@ -12,6 +12,7 @@ import (
 
 type AuthProvider string
 type RoleType string
+type PlanType string
 
 const (
 	AuthProviderGoogle AuthProvider = "google"
@@ -19,6 +20,9 @@ const (
 	AuthProviderEmail  AuthProvider = "email"
 	RoleTypeUser       RoleType     = "user"
 	RoleTypeAdmin      RoleType     = "Admin"
+	PlanTypeFree       PlanType     = "free"
+	PlanTypePro        PlanType     = "pro"
+	PlanTypeEnterprise PlanType     = "enterprise"
 
 	DefaultSignupCredits int64 = 200 //  2x Bonus
 )
@@ -33,10 +37,20 @@ type User struct {
 	Role             RoleType `bson:"role,omitempty" json:"role" default:"user"`
 	Verified         bool     `bson:"verified" json:"verified" default:"false"`
 	AuthProvider     string   `bson:"authProvider,omitempty" json:"authProvider,omitempty"`
+	Plan             PlanType `bson:"plan,omitempty" json:"plan" default:"free"`
 
 	Credits int64 `bson:"credits" json:"credits"`
 }
 
+// GetPlan returns the plan of the user; users created before plans
+// existed are on PlanTypeFree.
+func (u *User) GetPlan() PlanType {
+	if u.Plan == "" {
+		return PlanTypeFree
+	}
+	return u.Plan
+}
+
 func (u *User) CollectionName() string {
 	return "users"
 }
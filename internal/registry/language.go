@@ -1,99 +1,411 @@
 package registry
 
 import (
+	"bytes"
 	"fmt"
+	"sort"
+	"text/template"
+	"time"
+
+	"github.com/anurag-327/neuron/pkg/analysis"
+	"github.com/anurag-327/neuron/pkg/diagnostics"
+)
+
+// DefaultTimeLimit is the wall-clock limit applied to a run when a
+// version does not declare its own.
+const DefaultTimeLimit = 3 * time.Second
+
+// DefaultContainerMemory is the memory limit applied to pooled
+// containers when a pool does not declare its own.
+const DefaultContainerMemory int64 = 256 * 1024 * 1024
+
+// DefaultTmpfsSize is the size of the writable /tmp of pooled containers.
+const DefaultTmpfsSize int64 = 64 * 1024 * 1024
+
+// DefaultIdleMemory is the memory an idle container may hold before
+// the health check replaces it.
+const DefaultIdleMemory int64 = 50 * 1024 * 1024
+
+// Sandbox backends a runtime can execute on.
+const (
+	// BackendDocker runs code in pooled Docker containers.
+	BackendDocker = "docker"
+
+	// BackendProcess runs code in a process-level sandbox
+	// (bubblewrap or nsjail) using toolchains installed on the host.
+	BackendProcess = "process"
 )
 
 type LanguageConfig struct {
-	Name string
+	Name        string
+	DisplayName string
 
 	// API layer
 	Validator func(code string) error
 
+	// Analyzer parses code for Rules. Nil skips static analysis.
+	Analyzer analysis.Analyzer
+	Rules    []analysis.Rule
+
+	// Syntax names the lexer used to fingerprint code for similarity
+	// checks (see analysis.Tokenize).
+	Syntax string
+
 	// Execution layer
-	DockerImage string
-	BaseName    string
-	Ext         string
-	RunCmd      func(n FileNames) string
+	BaseName string
+	Ext      string
+
+	// DefaultVersion is used when a submission does not pick a version.
+	DefaultVersion string
+	Versions       map[string]VersionConfig
+
+	// Errors classifies non-zero exits into compile / runtime errors.
+	Errors ErrorPatterns
 
-	// Billing
+	// Libraries holds the curated library sets available for this
+	// language, keyed by set name.
+	Libraries map[string]LibrarySet
+
+	// CreditCost is the price of a submission, the flat submission
+	// price when 0 (see config.GetJobCredits).
 	CreditCost int64
 }
 
+// VersionConfig describes a single runtime version of a language.
+//
+// Every version runs in its own container pool, so two versions may
+// share an image (e.g. C++17 and C++20 on gcc) and differ only in flags.
+type VersionConfig struct {
+	Name        string
+	DockerImage string
+
+	// CompileCmd and RunCmd are text/template command templates
+	// rendered with FileNames. CompileCmd is optional.
+	CompileCmd string
+	RunCmd     string
+
+	// TimeLimit is the authoritative in-container run limit.
+	// Zero means DefaultTimeLimit.
+	TimeLimit time.Duration
+
+	// Pool is nil when the version has no container pool.
+	Pool *PoolSettings
+
+	// Sandbox selects the backend that executes this version.
+	Sandbox SandboxSettings
+
+	compileTmpl *template.Template
+	runTmpl     *template.Template
+}
+
+// SandboxSettings selects and tunes the sandbox backend of a version.
+type SandboxSettings struct {
+	// Backend is BackendDocker or BackendProcess.
+	Backend string
+
+	// Runtime is an optional OCI runtime for the Docker backend,
+	// e.g. "runsc" to run containers under gVisor.
+	Runtime string
+
+	// Seccomp names the seccomp profile of the Docker backend
+	// (security/seccomp/<name>.json). Empty keeps Docker's default.
+	Seccomp string
+
+	// AppArmor names a host AppArmor profile for the Docker backend.
+	// Empty keeps Docker's default.
+	AppArmor string
+}
+
+// LibrarySet is a curated set of third-party packages baked into
+// derived images of a language, e.g. "python-datascience".
+//
+// Containers run without network access, so code can only use
+// packages that are already present in the image it runs on.
+type LibrarySet struct {
+	Name        string
+	DisplayName string
+
+	// Packages lists what the derived images provide. Informational.
+	Packages []string
+
+	// Imports are the modules the set provides, e.g. "numpy". Code
+	// importing them needs the set selected.
+	Imports []string
+
+	// Rules apply in addition to the language rules when the set is
+	// selected.
+	Rules []analysis.Rule
+
+	// Versions maps a base version name to the runtime serving it.
+	Versions map[string]VersionConfig
+}
+
+// PoolSettings controls the container pool serving a version.
+type PoolSettings struct {
+	InitSize        int
+	MaxSize         int
+	MemoryLimit     int64
+	TmpfsSize       int64
+	IdleMemoryLimit int64
+	HealthCmd       []string
+	HealthInterval  time.Duration
+
+	// WarmupCmd runs once in every new container before it joins
+	// the pool, e.g. to pre-fill a compiler cache in /tmp.
+	WarmupCmd []string
+}
+
+// ErrorPatterns holds substrings used to classify a failed run.
+//
+// Compile patterns are matched against stderr, runtime patterns
+// against stdout and stderr combined.
+type ErrorPatterns struct {
+	Compile []string
+	Runtime []string
+
+	// Diagnostics parse stderr into structured diagnostics, e.g. tsc
+	// for compile errors and node for uncaught exceptions.
+	Diagnostics []diagnostics.Parser
+}
+
 type FileNames struct {
 	BaseName string // "main" or "Main"
 	FullName string // "main.cpp"
 	PathBase string // "/host/job/main"
 	PathFull string // "/host/job/main.cpp"
+	Args     string // program arguments, shell-quoted: "'-n' '3'"
+}
+
+// LanguageRegistry holds every loaded language keyed by name.
+//
+// It is populated once at startup by LoadLanguages.
+var LanguageRegistry = map[string]LanguageConfig{}
+
+// ResolveVersion returns the requested version, or the default version
+// when version is empty.
+func (l LanguageConfig) ResolveVersion(version string) (VersionConfig, error) {
+	if version == "" {
+		version = l.DefaultVersion
+	}
+	v, ok := l.Versions[version]
+	if !ok {
+		return VersionConfig{}, fmt.Errorf("version %q not supported for %s", version, l.Name)
+	}
+	return v, nil
+}
+
+// ResolveRuntime returns the runtime for a version and an optional
+// library set. An empty library selects the stock runtime.
+func (l LanguageConfig) ResolveRuntime(version, library string) (VersionConfig, error) {
+	v, err := l.ResolveVersion(version)
+	if err != nil || library == "" {
+		return v, err
+	}
+	lib, ok := l.Libraries[library]
+	if !ok {
+		return VersionConfig{}, fmt.Errorf("library set %q not available for %s", library, l.Name)
+	}
+	variant, ok := lib.Versions[v.Name]
+	if !ok {
+		return VersionConfig{}, fmt.Errorf("library set %q does not support %s %s", library, l.Name, v.Name)
+	}
+	return variant, nil
+}
+
+// Validate runs the language validator and the static analysis of
+// code submitted with the given library set (empty for none).
+//
+// Rule violations are returned as an *analysis.FindingsError. policy
+// selects the rules enforced; library checks apply under every policy.
+// fileAccess lifts the file rules for code that reads declared files or
+// writes artifacts in its job directory.
+func (l LanguageConfig) Validate(code, library string, policy analysis.Policy, fileAccess bool) error {
+	if l.Validator != nil {
+		if err := l.Validator(code); err != nil {
+			return err
+		}
+	}
+	if l.Analyzer == nil {
+		return nil
+	}
+
+	src := l.Analyzer(code)
+	findings := l.libraryFindings(src, library)
+
+	rules := append(l.Rules[:len(l.Rules):len(l.Rules)], l.Libraries[library].Rules...)
+	if fileAccess {
+		rules = withoutLevel(rules, analysis.LevelFiles)
+	}
+	findings = append(findings, analysis.Check(src, rules, policy)...)
+
+	if len(findings) == 0 {
+		return nil
+	}
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].Line != findings[j].Line {
+			return findings[i].Line < findings[j].Line
+		}
+		return findings[i].Column < findings[j].Column
+	})
+	return &analysis.FindingsError{Findings: findings}
+}
+
+// withoutLevel returns the rules not at level.
+func withoutLevel(rules []analysis.Rule, level analysis.Level) []analysis.Rule {
+	kept := rules[:0:0]
+	for _, r := range rules {
+		if r.Level != level {
+			kept = append(kept, r)
+		}
+	}
+	return kept
+}
+
+// LibraryNames returns the library set names of a language in sorted order.
+func (l LanguageConfig) LibraryNames() []string {
+	names := make([]string, 0, len(l.Libraries))
+	for name := range l.Libraries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// DefaultLibrary returns the first of names that is a library set of
+// this language, or "" when none is.
+func (l LanguageConfig) DefaultLibrary(names []string) string {
+	for _, name := range names {
+		if _, ok := l.Libraries[name]; ok {
+			return name
+		}
+	}
+	return ""
+}
+
+// BackendsInUse returns the sandbox backends needed to execute every
+// loaded runtime that has a pool, in sorted order.
+func BackendsInUse() []string {
+	seen := map[string]bool{}
+	for _, langCfg := range LanguageRegistry {
+		for _, v := range langCfg.Versions {
+			if v.Pool != nil {
+				seen[v.Sandbox.Backend] = true
+			}
+		}
+		for _, lib := range langCfg.Libraries {
+			for _, v := range lib.Versions {
+				if v.Pool != nil {
+					seen[v.Sandbox.Backend] = true
+				}
+			}
+		}
+	}
+
+	backends := make([]string, 0, len(seen))
+	for b := range seen {
+		backends = append(backends, b)
+	}
+	sort.Strings(backends)
+	return backends
+}
+
+// FindLibrarySet returns the language that owns the named library set.
+func FindLibrarySet(name string) (string, bool) {
+	for lang, langCfg := range LanguageRegistry {
+		if _, ok := langCfg.Libraries[name]; ok {
+			return lang, true
+		}
+	}
+	return "", false
+}
+
+// VersionNames returns the version names of a language in sorted order.
+func (l LanguageConfig) VersionNames() []string {
+	names := make([]string, 0, len(l.Versions))
+	for name := range l.Versions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// GetTimeLimit returns the effective run time limit of the version.
+func (v VersionConfig) GetTimeLimit() time.Duration {
+	if v.TimeLimit <= 0 {
+		return DefaultTimeLimit
+	}
+	return v.TimeLimit
+}
+
+// Command renders the shell command that compiles (if needed) and
+// runs the program described by n.
+func (v VersionConfig) Command(n FileNames) (string, error) {
+	run, err := v.RunCommand(n)
+	if err != nil {
+		return "", err
+	}
+	compile, err := v.CompileCommand(n)
+	if err != nil || compile == "" {
+		return run, err
+	}
+	return compile + " && " + run, nil
+}
+
+// CompileCommand renders the compile command of the program described
+// by n, or "" when the runtime does not compile.
+func (v VersionConfig) CompileCommand(n FileNames) (string, error) {
+	if v.compileTmpl == nil {
+		return "", nil
+	}
+	return render(v.compileTmpl, n)
+}
+
+// RunCommand renders the command that runs the compiled program
+// described by n.
+func (v VersionConfig) RunCommand(n FileNames) (string, error) {
+	return render(v.runTmpl, n)
+}
+
+// GetMemoryLimit returns the effective container memory limit in bytes.
+func (p PoolSettings) GetMemoryLimit() int64 {
+	if p.MemoryLimit <= 0 {
+		return DefaultContainerMemory
+	}
+	return p.MemoryLimit
+}
+
+// GetTmpfsSize returns the effective /tmp size in bytes.
+func (p PoolSettings) GetTmpfsSize() int64 {
+	if p.TmpfsSize <= 0 {
+		return DefaultTmpfsSize
+	}
+	return p.TmpfsSize
+}
+
+// GetIdleMemoryLimit returns the effective idle memory threshold in bytes.
+func (p PoolSettings) GetIdleMemoryLimit() int64 {
+	if p.IdleMemoryLimit <= 0 {
+		return DefaultIdleMemory
+	}
+	return p.IdleMemoryLimit
+}
+
+// PoolKey identifies the container pool serving a language version,
+// optionally with a library set.
+func PoolKey(language, version, library string) string {
+	if library == "" {
+		return language + "@" + version
+	}
+	return language + "@" + version + "+" + library
 }
 
-var LanguageRegistry = map[string]LanguageConfig{
-	"cpp": {
-		Name:        "cpp",
-		Validator:   ValidateAndSanitizeCpp,
-		DockerImage: "gcc:latest",
-		BaseName:    "main",
-		Ext:         "cpp",
-		RunCmd: func(n FileNames) string {
-			return fmt.Sprintf(
-				"g++ %s -o %s && ./%s < input.txt",
-				n.FullName, n.BaseName, n.BaseName,
-			)
-		},
-		CreditCost: 5,
-	},
-
-	"go": {
-		Name:        "go",
-		Validator:   nil,
-		DockerImage: "golang:1.23-alpine",
-		BaseName:    "main",
-		Ext:         "go",
-		RunCmd: func(n FileNames) string {
-			return fmt.Sprintf(
-				"go build -o %s %s && ./%s",
-				n.BaseName, n.FullName, n.BaseName,
-			)
-		},
-		CreditCost: 4,
-	},
-
-	"python": {
-		Name:        "python",
-		Validator:   ValidateAndSanitizePython,
-		DockerImage: "python:3.12-alpine",
-		BaseName:    "main",
-		Ext:         "py",
-		RunCmd: func(n FileNames) string {
-			return fmt.Sprintf("python3 %s < input.txt", n.FullName)
-		},
-		CreditCost: 6,
-	},
-
-	"java": {
-		Name:        "java",
-		Validator:   ValidateAndSanitizeJava,
-		DockerImage: "eclipse-temurin:21-jdk-alpine",
-		BaseName:    "Main",
-		Ext:         "java",
-		RunCmd: func(n FileNames) string {
-			return fmt.Sprintf(
-				"javac %s && java %s < input.txt",
-				n.FullName, n.BaseName,
-			)
-		},
-		CreditCost: 7,
-	},
-
-	"javascript": {
-		Name:        "javascript",
-		Validator:   ValidateAndSanitizeJS,
-		DockerImage: "node:22-alpine",
-		BaseName:    "main",
-		Ext:         "js",
-		RunCmd: func(n FileNames) string {
-			return fmt.Sprintf("node %s < input.txt", n.FullName)
-		},
-		CreditCost: 5,
-	},
+func render(t *template.Template, n FileNames) (string, error) {
+	if t == nil {
+		return "", fmt.Errorf("command template not loaded")
+	}
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, n); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
 }
@@ -0,0 +1,527 @@
+package registry
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/anurag-327/neuron/pkg/analysis"
+	"github.com/anurag-327/neuron/pkg/diagnostics"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultLanguagesDir is where language definitions are read from when
+// LANGUAGES_DIR is not set. It is relative to the process working directory.
+const DefaultLanguagesDir = "languages"
+
+// DefaultSandboxBackend returns the backend used by runtimes that do not
+// pick one. It is BackendDocker unless SANDBOX_BACKEND says otherwise.
+func DefaultSandboxBackend() string {
+	if backend := os.Getenv("SANDBOX_BACKEND"); backend != "" {
+		return backend
+	}
+	return BackendDocker
+}
+
+// languageFile is the on-disk schema of a language definition.
+//
+// Files may be written in YAML or JSON; JSON is parsed as YAML.
+type languageFile struct {
+	Name           string         `yaml:"name"`
+	DisplayName    string         `yaml:"displayName"`
+	BaseName       string         `yaml:"baseName"`
+	Ext            string         `yaml:"ext"`
+	DefaultVersion string         `yaml:"defaultVersion"`
+	CreditCost     int64          `yaml:"creditCost"`
+	Validator      *validatorFile `yaml:"validator"`
+	Sandbox        sandboxFile    `yaml:"sandbox"`
+	Errors         errorsFile     `yaml:"errors"`
+	Versions       []versionFile  `yaml:"versions"`
+	Libraries      []libraryFile  `yaml:"libraries"`
+}
+
+type validatorFile struct {
+	MaxSizeKB int            `yaml:"maxSizeKB"`
+	Required  []requiredFile `yaml:"required"`
+
+	// Analyzer names the parser of the language; it defaults to the
+	// language name.
+	Analyzer string    `yaml:"analyzer"`
+	Rules    rulesFile `yaml:",inline"`
+}
+
+// rulesFile lists forbidden references by kind. See analysis.Rule for
+// how patterns match.
+type rulesFile struct {
+	Imports levelsFile `yaml:"imports"`
+	Calls   levelsFile `yaml:"calls"`
+	Names   levelsFile `yaml:"names"`
+}
+
+// levelsFile splits patterns by the policy level enforcing them.
+type levelsFile struct {
+	Standard []string `yaml:"standard"`
+	Strict   []string `yaml:"strict"`
+	Files    []string `yaml:"files"`
+}
+
+type requiredFile struct {
+	AnyOf   []string `yaml:"anyOf"`
+	Message string   `yaml:"message"`
+}
+
+type errorsFile struct {
+	Compile     []string `yaml:"compile"`
+	Runtime     []string `yaml:"runtime"`
+	Diagnostics []string `yaml:"diagnostics"`
+}
+
+type versionFile struct {
+	Name      string        `yaml:"name"`
+	Image     string        `yaml:"image"`
+	Compile   string        `yaml:"compile"`
+	Run       string        `yaml:"run"`
+	TimeLimit time.Duration `yaml:"timeLimit"`
+	Sandbox   sandboxFile   `yaml:"sandbox"`
+	Pool      *poolFile     `yaml:"pool"`
+}
+
+// sandboxFile selects a sandbox backend. Set on a language it applies
+// to every version; set on a version it overrides the language.
+type sandboxFile struct {
+	Backend  string `yaml:"backend"`
+	Runtime  string `yaml:"runtime"`
+	Seccomp  string `yaml:"seccomp"`
+	AppArmor string `yaml:"apparmor"`
+}
+
+// merge fills fields not set in s from parent.
+func (s sandboxFile) merge(parent sandboxFile) sandboxFile {
+	if s.Backend == "" {
+		s.Backend = parent.Backend
+	}
+	if s.Runtime == "" {
+		s.Runtime = parent.Runtime
+	}
+	if s.Seccomp == "" {
+		s.Seccomp = parent.Seccomp
+	}
+	if s.AppArmor == "" {
+		s.AppArmor = parent.AppArmor
+	}
+	return s
+}
+
+// libraryFile describes a library set. Its versions refer to base
+// versions by name and inherit their commands and time limit unless
+// overridden; image is always required.
+type libraryFile struct {
+	Name        string        `yaml:"name"`
+	DisplayName string        `yaml:"displayName"`
+	Packages    []string      `yaml:"packages"`
+	Imports     []string      `yaml:"imports"`
+	Rules       rulesFile     `yaml:"rules"`
+	Versions    []versionFile `yaml:"versions"`
+}
+
+type poolFile struct {
+	InitSize       int           `yaml:"initSize"`
+	MaxSize        int           `yaml:"maxSize"`
+	MemoryMB       int64         `yaml:"memoryMB"`
+	TmpfsMB        int64         `yaml:"tmpfsMB"`
+	IdleMemoryMB   int64         `yaml:"idleMemoryMB"`
+	HealthCmd      []string      `yaml:"healthCmd"`
+	HealthInterval time.Duration `yaml:"healthInterval"`
+	WarmupCmd      []string      `yaml:"warmupCmd"`
+}
+
+// LanguagesDir returns the directory holding language definitions.
+func LanguagesDir() string {
+	if dir := os.Getenv("LANGUAGES_DIR"); dir != "" {
+		return dir
+	}
+	return DefaultLanguagesDir
+}
+
+// LoadLanguages reads every *.yaml, *.yml and *.json file in dir,
+// validates it and replaces LanguageRegistry with the result.
+//
+// Loading is all-or-nothing: a single invalid file fails the whole
+// load and leaves the registry untouched.
+func LoadLanguages(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("failed to read languages dir: %w", err)
+	}
+
+	var files []string
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		switch strings.ToLower(filepath.Ext(e.Name())) {
+		case ".yaml", ".yml", ".json":
+			files = append(files, filepath.Join(dir, e.Name()))
+		}
+	}
+	sort.Strings(files)
+
+	if len(files) == 0 {
+		return fmt.Errorf("no language definitions found in %s", dir)
+	}
+
+	languages := make(map[string]LanguageConfig, len(files))
+	libraries := make(map[string]string)
+	for _, path := range files {
+		cfg, err := loadLanguageFile(path)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		if _, exists := languages[cfg.Name]; exists {
+			return fmt.Errorf("%s: language %q defined more than once", path, cfg.Name)
+		}
+		// Library set names are selected without a language (e.g. on
+		// credentials), so they must be unique across languages.
+		for name := range cfg.Libraries {
+			if owner, exists := libraries[name]; exists {
+				return fmt.Errorf("%s: library set %q already defined by %s", path, name, owner)
+			}
+			libraries[name] = cfg.Name
+		}
+		languages[cfg.Name] = cfg
+	}
+
+	LanguageRegistry = languages
+	return nil
+}
+
+func loadLanguageFile(path string) (LanguageConfig, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return LanguageConfig{}, err
+	}
+
+	var f languageFile
+	if err := yaml.Unmarshal(raw, &f); err != nil {
+		return LanguageConfig{}, fmt.Errorf("invalid definition: %w", err)
+	}
+
+	return f.build()
+}
+
+// build validates the file and converts it into a LanguageConfig.
+func (f languageFile) build() (LanguageConfig, error) {
+	switch {
+	case f.Name == "":
+		return LanguageConfig{}, fmt.Errorf("name is required")
+	case f.BaseName == "":
+		return LanguageConfig{}, fmt.Errorf("baseName is required")
+	case f.Ext == "":
+		return LanguageConfig{}, fmt.Errorf("ext is required")
+	case len(f.Versions) == 0:
+		return LanguageConfig{}, fmt.Errorf("at least one version is required")
+	case f.CreditCost < 0:
+		return LanguageConfig{}, fmt.Errorf("creditCost must not be negative")
+	}
+
+	cfg := LanguageConfig{
+		Name:           f.Name,
+		DisplayName:    f.DisplayName,
+		BaseName:       f.BaseName,
+		Ext:            f.Ext,
+		DefaultVersion: f.DefaultVersion,
+		Versions:       make(map[string]VersionConfig, len(f.Versions)),
+		CreditCost:     f.CreditCost,
+	}
+	if cfg.DisplayName == "" {
+		cfg.DisplayName = f.Name
+	}
+
+	for _, vf := range f.Versions {
+		vf.Sandbox = vf.Sandbox.merge(f.Sandbox)
+		v, err := vf.build()
+		if err != nil {
+			return LanguageConfig{}, fmt.Errorf("version %q: %w", vf.Name, err)
+		}
+		if _, exists := cfg.Versions[v.Name]; exists {
+			return LanguageConfig{}, fmt.Errorf("version %q defined more than once", v.Name)
+		}
+		cfg.Versions[v.Name] = v
+	}
+
+	if cfg.DefaultVersion == "" {
+		if len(f.Versions) > 1 {
+			return LanguageConfig{}, fmt.Errorf("defaultVersion is required when several versions exist")
+		}
+		cfg.DefaultVersion = f.Versions[0].Name
+	}
+	if _, ok := cfg.Versions[cfg.DefaultVersion]; !ok {
+		return LanguageConfig{}, fmt.Errorf("defaultVersion %q is not a defined version", cfg.DefaultVersion)
+	}
+
+	cfg.Syntax = f.Name
+	if f.Validator != nil {
+		rules, err := f.Validator.build()
+		if err != nil {
+			return LanguageConfig{}, fmt.Errorf("validator: %w", err)
+		}
+		cfg.Validator = NewRuleValidator(strings.ToLower(cfg.DisplayName), rules)
+
+		name := f.Validator.Analyzer
+		if name == "" {
+			name = f.Name
+		}
+		analyzer, ok := analysis.Get(name)
+		if !ok {
+			return LanguageConfig{}, fmt.Errorf("validator: no analyzer %q (available: %s)", name, strings.Join(analysis.Names(), ", "))
+		}
+		cfg.Analyzer = analyzer
+		cfg.Syntax = name
+		if cfg.Rules, err = f.Validator.Rules.build(); err != nil {
+			return LanguageConfig{}, fmt.Errorf("validator: %w", err)
+		}
+	}
+
+	if err := nonEmpty(f.Errors.Compile); err != nil {
+		return LanguageConfig{}, fmt.Errorf("errors.compile: %w", err)
+	}
+	if err := nonEmpty(f.Errors.Runtime); err != nil {
+		return LanguageConfig{}, fmt.Errorf("errors.runtime: %w", err)
+	}
+	cfg.Errors = ErrorPatterns{Compile: f.Errors.Compile, Runtime: f.Errors.Runtime}
+	for _, name := range f.Errors.Diagnostics {
+		parser, ok := diagnostics.Get(name)
+		if !ok {
+			return LanguageConfig{}, fmt.Errorf("errors.diagnostics: no parser %q (available: %s)", name, strings.Join(diagnostics.Names(), ", "))
+		}
+		cfg.Errors.Diagnostics = append(cfg.Errors.Diagnostics, parser)
+	}
+
+	if len(f.Libraries) > 0 {
+		cfg.Libraries = make(map[string]LibrarySet, len(f.Libraries))
+	}
+	for _, lf := range f.Libraries {
+		lib, err := lf.build(cfg)
+		if err != nil {
+			return LanguageConfig{}, fmt.Errorf("library set %q: %w", lf.Name, err)
+		}
+		if _, exists := cfg.Libraries[lib.Name]; exists {
+			return LanguageConfig{}, fmt.Errorf("library set %q defined more than once", lib.Name)
+		}
+		cfg.Libraries[lib.Name] = lib
+	}
+
+	return cfg, nil
+}
+
+func (lf libraryFile) build(lang LanguageConfig) (LibrarySet, error) {
+	switch {
+	case lf.Name == "":
+		return LibrarySet{}, fmt.Errorf("name is required")
+	case len(lf.Versions) == 0:
+		return LibrarySet{}, fmt.Errorf("at least one version is required")
+	}
+	if err := nonEmpty(lf.Imports); err != nil {
+		return LibrarySet{}, fmt.Errorf("imports: %w", err)
+	}
+	rules, err := lf.Rules.build()
+	if err != nil {
+		return LibrarySet{}, fmt.Errorf("rules: %w", err)
+	}
+	if lang.Analyzer == nil && (len(lf.Imports) > 0 || len(rules) > 0) {
+		return LibrarySet{}, fmt.Errorf("imports and rules need a language validator")
+	}
+
+	lib := LibrarySet{
+		Name:        lf.Name,
+		DisplayName: lf.DisplayName,
+		Packages:    lf.Packages,
+		Imports:     lf.Imports,
+		Rules:       rules,
+		Versions:    make(map[string]VersionConfig, len(lf.Versions)),
+	}
+	if lib.DisplayName == "" {
+		lib.DisplayName = lf.Name
+	}
+
+	for _, vf := range lf.Versions {
+		base, ok := lang.Versions[vf.Name]
+		if !ok {
+			return LibrarySet{}, fmt.Errorf("version %q is not a defined version", vf.Name)
+		}
+		if _, exists := lib.Versions[vf.Name]; exists {
+			return LibrarySet{}, fmt.Errorf("version %q defined more than once", vf.Name)
+		}
+		if vf.Compile == "" {
+			vf.Compile = base.CompileCmd
+		}
+		if vf.Run == "" {
+			vf.Run = base.RunCmd
+		}
+		if vf.TimeLimit == 0 {
+			vf.TimeLimit = base.TimeLimit
+		}
+		vf.Sandbox = vf.Sandbox.merge(sandboxFile{
+			Backend:  base.Sandbox.Backend,
+			Runtime:  base.Sandbox.Runtime,
+			Seccomp:  base.Sandbox.Seccomp,
+			AppArmor: base.Sandbox.AppArmor,
+		})
+		v, err := vf.build()
+		if err != nil {
+			return LibrarySet{}, fmt.Errorf("version %q: %w", vf.Name, err)
+		}
+		lib.Versions[v.Name] = v
+	}
+	return lib, nil
+}
+
+func (vf versionFile) build() (VersionConfig, error) {
+	switch {
+	case vf.Name == "":
+		return VersionConfig{}, fmt.Errorf("name is required")
+	case vf.Image == "":
+		return VersionConfig{}, fmt.Errorf("image is required")
+	case vf.Run == "":
+		return VersionConfig{}, fmt.Errorf("run command is required")
+	case vf.TimeLimit < 0:
+		return VersionConfig{}, fmt.Errorf("timeLimit must not be negative")
+	}
+
+	v := VersionConfig{
+		Name:        vf.Name,
+		DockerImage: vf.Image,
+		CompileCmd:  vf.Compile,
+		RunCmd:      vf.Run,
+		TimeLimit:   vf.TimeLimit,
+	}
+
+	v.Sandbox = SandboxSettings{
+		Backend:  vf.Sandbox.Backend,
+		Runtime:  vf.Sandbox.Runtime,
+		Seccomp:  vf.Sandbox.Seccomp,
+		AppArmor: vf.Sandbox.AppArmor,
+	}
+	if strings.ContainsAny(v.Sandbox.Seccomp, `/\.`) {
+		return VersionConfig{}, fmt.Errorf("sandbox.seccomp must be a profile name, not a path")
+	}
+	if v.Sandbox.Backend == "" {
+		v.Sandbox.Backend = DefaultSandboxBackend()
+	}
+	switch v.Sandbox.Backend {
+	case BackendDocker:
+	case BackendProcess:
+		if v.Sandbox.Runtime != "" {
+			return VersionConfig{}, fmt.Errorf("sandbox.runtime is only supported by the %s backend", BackendDocker)
+		}
+	default:
+		return VersionConfig{}, fmt.Errorf("unknown sandbox backend %q", v.Sandbox.Backend)
+	}
+
+	var err error
+	if v.runTmpl, err = parseCommand("run", vf.Run); err != nil {
+		return VersionConfig{}, err
+	}
+	if vf.Compile != "" {
+		if v.compileTmpl, err = parseCommand("compile", vf.Compile); err != nil {
+			return VersionConfig{}, err
+		}
+	}
+
+	if vf.Pool != nil {
+		p := vf.Pool
+		switch {
+		case p.MaxSize <= 0:
+			return VersionConfig{}, fmt.Errorf("pool.maxSize must be positive")
+		case p.InitSize < 0 || p.InitSize > p.MaxSize:
+			return VersionConfig{}, fmt.Errorf("pool.initSize must be between 0 and maxSize")
+		case p.MemoryMB < 0 || p.TmpfsMB < 0 || p.IdleMemoryMB < 0:
+			return VersionConfig{}, fmt.Errorf("pool memory sizes must not be negative")
+		}
+		v.Pool = &PoolSettings{
+			InitSize:        p.InitSize,
+			MaxSize:         p.MaxSize,
+			MemoryLimit:     p.MemoryMB * 1024 * 1024,
+			TmpfsSize:       p.TmpfsMB * 1024 * 1024,
+			IdleMemoryLimit: p.IdleMemoryMB * 1024 * 1024,
+			HealthCmd:       p.HealthCmd,
+			HealthInterval:  p.HealthInterval,
+			WarmupCmd:       p.WarmupCmd,
+		}
+	}
+
+	return v, nil
+}
+
+func (vf validatorFile) build() (ValidatorRules, error) {
+	if vf.MaxSizeKB <= 0 {
+		return ValidatorRules{}, fmt.Errorf("maxSizeKB must be positive")
+	}
+	rules := ValidatorRules{MaxSizeKB: vf.MaxSizeKB}
+	for i, r := range vf.Required {
+		if len(r.AnyOf) == 0 || r.Message == "" {
+			return ValidatorRules{}, fmt.Errorf("required[%d]: anyOf and message are required", i)
+		}
+		if err := nonEmpty(r.AnyOf); err != nil {
+			return ValidatorRules{}, fmt.Errorf("required[%d]: %w", i, err)
+		}
+		rules.Required = append(rules.Required, RequiredRule{AnyOf: r.AnyOf, Message: r.Message})
+	}
+	return rules, nil
+}
+
+func (rf rulesFile) build() ([]analysis.Rule, error) {
+	var rules []analysis.Rule
+	kinds := []struct {
+		kind   analysis.Kind
+		levels levelsFile
+	}{
+		{analysis.KindImport, rf.Imports},
+		{analysis.KindCall, rf.Calls},
+		{analysis.KindName, rf.Names},
+	}
+	for _, k := range kinds {
+		for _, l := range []struct {
+			level    analysis.Level
+			patterns []string
+		}{
+			{analysis.LevelStandard, k.levels.Standard},
+			{analysis.LevelStrict, k.levels.Strict},
+			{analysis.LevelFiles, k.levels.Files},
+		} {
+			if err := nonEmpty(l.patterns); err != nil {
+				return nil, fmt.Errorf("%ss.%s: %w", k.kind, l.level, err)
+			}
+			for _, p := range l.patterns {
+				rules = append(rules, analysis.Rule{Kind: k.kind, Pattern: p, Level: l.level})
+			}
+		}
+	}
+	return rules, nil
+}
+
+// parseCommand parses a command template.
+func parseCommand(name, cmd string) (*template.Template, error) {
+	t, err := template.New(name).Option("missingkey=error").Parse(cmd)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s command: %w", name, err)
+	}
+	// Dry-run to catch references to unknown fields early
+	if _, err := render(t, FileNames{}); err != nil {
+		return nil, fmt.Errorf("invalid %s command: %w", name, err)
+	}
+	return t, nil
+}
+
+func nonEmpty(patterns []string) error {
+	for i, p := range patterns {
+		if p == "" {
+			return fmt.Errorf("entry %d is empty", i)
+		}
+	}
+	return nil
+}
@@ -4,154 +4,98 @@ import (
 	"fmt"
 	"strings"
 	"unicode"
-)
-
-func ValidateAndSanitizeCpp(code string) error {
-	// 1 Size limit
-	if len(code) > 256*1024 {
-		return fmt.Errorf("c++ code too large (>256KB)")
-	}
 
-	// 2 Non-printable characters
-	for _, r := range code {
-		if !unicode.IsPrint(r) && r != '\n' && r != '\t' {
-			return fmt.Errorf("contains invalid characters")
-		}
-	}
+	"github.com/anurag-327/neuron/pkg/analysis"
+)
 
-	// 3 Basic language heuristics
-	if !strings.Contains(code, "main(") {
-		return fmt.Errorf("missing main() function")
-	}
-	if !strings.Contains(code, "#include") && !strings.Contains(code, "int main(") {
-		return fmt.Errorf("not valid C++ source")
-	}
+// ValidatorRules describes the static checks applied to submitted code.
+type ValidatorRules struct {
+	// MaxSizeKB rejects code larger than this many kilobytes.
+	MaxSizeKB int
 
-	// 4 Dangerous keywords
-	blocked := []string{
-		"system(", "popen(", "execv", "fork(", "socket", "open(", "ofstream", "ifstream",
-		"std::filesystem", "unistd.h", "netinet", "arpa", "winsock", "dirent.h",
-	}
-	for _, bad := range blocked {
-		if strings.Contains(code, bad) {
-			return fmt.Errorf("code contains forbidden keyword: %s", bad)
-		}
-	}
-	return nil
+	// Required lists structural checks; each must be satisfied.
+	Required []RequiredRule
 }
 
-func ValidateAndSanitizeJS(code string) error {
-	// 1. Size limit
-	if len(code) > 256*1024 {
-		return fmt.Errorf("javascript code too large (>256KB)")
-	}
+// RequiredRule is satisfied when code contains any of AnyOf.
+type RequiredRule struct {
+	AnyOf   []string
+	Message string
+}
 
-	// 2. Non-printable characters
-	for _, r := range code {
-		if !unicode.IsPrint(r) && r != '\n' && r != '\t' {
-			return fmt.Errorf("contains invalid characters")
+// NewRuleValidator builds a code validator from declarative rules.
+//
+// label is used in error messages, e.g. "python code too large (>256KB)".
+// Forbidden imports and calls are checked separately by the language
+// analyzer (see LanguageConfig.Validate).
+func NewRuleValidator(label string, rules ValidatorRules) func(code string) error {
+	return func(code string) error {
+		// 1 Size limit
+		if len(code) > rules.MaxSizeKB*1024 {
+			return fmt.Errorf("%s code too large (>%dKB)", label, rules.MaxSizeKB)
 		}
-	}
 
-	// 4. Dangerous APIs
-	blocked := []string{
-		"require('child_process')",
-		"require(\"child_process\")",
-		"exec(",
-		"spawn(",
-		"fork(",
-		"process.exit",
-		"fs.writeFile",
-		"fs.unlink",
-		"fs.rm",
-		"net.createServer",
-		"dgram.createSocket",
-	}
+		// 2 Non-printable characters
+		for _, r := range code {
+			if !unicode.IsPrint(r) && r != '\n' && r != '\t' {
+				return fmt.Errorf("contains invalid characters")
+			}
+		}
 
-	for _, bad := range blocked {
-		if strings.Contains(code, bad) {
-			return fmt.Errorf("code contains forbidden keyword: %s", bad)
+		// 3 Basic language heuristics
+		for _, req := range rules.Required {
+			if !containsAny(code, req.AnyOf) {
+				return fmt.Errorf("%s", req.Message)
+			}
 		}
-	}
 
-	return nil
+		return nil
+	}
 }
 
-func ValidateAndSanitizePython(code string) error {
-	// 1. Size limit
-	if len(code) > 256*1024 {
-		return fmt.Errorf("python code too large (>256KB)")
-	}
+// libraryFindings reports imports of library sets other than the
+// selected one. The packages are missing from other images, so these
+// are enforced under every policy.
+func (l LanguageConfig) libraryFindings(src analysis.Source, library string) []analysis.Finding {
+	selected := l.Libraries[library]
 
-	// 2. Non-printable characters
-	for _, r := range code {
-		if !unicode.IsPrint(r) && r != '\n' && r != '\t' {
-			return fmt.Errorf("contains invalid characters")
+	var findings []analysis.Finding
+	for _, ref := range src.Imports {
+		if matchesAny(selected.Imports, ref.Name) {
+			continue
 		}
-	}
-
-	// 4. Dangerous modules / functions
-	blocked := []string{
-		"import os",
-		"import sys",
-		"subprocess",
-		"eval(",
-		"exec(",
-		"open(",
-		"__import__",
-		"socket",
-		"shutil",
-		"pickle",
-	}
-
-	for _, bad := range blocked {
-		if strings.Contains(code, bad) {
-			return fmt.Errorf("code contains forbidden keyword: %s", bad)
+		for _, name := range l.LibraryNames() {
+			if name == library || !matchesAny(l.Libraries[name].Imports, ref.Name) {
+				continue
+			}
+			findings = append(findings, analysis.Finding{
+				Kind:    analysis.KindLibrary,
+				Level:   analysis.LevelStandard,
+				Target:  ref.Name,
+				Message: fmt.Sprintf("%s requires library set %q", ref.Name, name),
+				Line:    ref.Line,
+				Column:  ref.Column,
+			})
+			break
 		}
 	}
-
-	return nil
+	return findings
 }
 
-func ValidateAndSanitizeJava(code string) error {
-	// 1. Size limit
-	if len(code) > 256*1024 {
-		return fmt.Errorf("java code too large (>256KB)")
-	}
-
-	// 2. Non-printable characters
-	for _, r := range code {
-		if !unicode.IsPrint(r) && r != '\n' && r != '\t' {
-			return fmt.Errorf("contains invalid characters")
+func matchesAny(patterns []string, name string) bool {
+	for _, p := range patterns {
+		if analysis.Matches(p, name) {
+			return true
 		}
 	}
+	return false
+}
 
-	// 3. Must contain class and main
-	if !strings.Contains(code, "class ") {
-		return fmt.Errorf("missing class declaration")
-	}
-	if !strings.Contains(code, "public static void main") {
-		return fmt.Errorf("missing main method")
-	}
-
-	// 4. Dangerous APIs
-	blocked := []string{
-		"Runtime.getRuntime",
-		"ProcessBuilder",
-		"System.exit",
-		"java.io.File",
-		"java.nio.file",
-		"java.net",
-		"Thread.sleep",
-		"Executors",
-		"ForkJoinPool",
-	}
-
-	for _, bad := range blocked {
-		if strings.Contains(code, bad) {
-			return fmt.Errorf("code contains forbidden keyword: %s", bad)
+func containsAny(s string, subs []string) bool {
+	for _, sub := range subs {
+		if strings.Contains(s, sub) {
+			return true
 		}
 	}
-
-	return nil
+	return false
 }
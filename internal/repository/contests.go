@@ -0,0 +1,185 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/anurag-327/neuron/internal/models"
+	"github.com/kamva/mgm/v3"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	ErrContestNotFound   = errors.New("contest not found")
+	ErrAlreadyRegistered = errors.New("already registered for this contest")
+)
+
+func SaveContest(ctx context.Context, contest *models.Contest) (*models.Contest, error) {
+	coll := mgm.Coll(contest)
+	if contest.ID.IsZero() {
+		if err := coll.CreateWithCtx(ctx, contest); err != nil {
+			return nil, err
+		}
+	} else {
+		if err := coll.UpdateWithCtx(ctx, contest); err != nil {
+			return nil, err
+		}
+	}
+	return contest, nil
+}
+
+func GetContestByID(ctx context.Context, contestID primitive.ObjectID) (*models.Contest, error) {
+	contest := &models.Contest{}
+	coll := mgm.Coll(contest)
+
+	err := coll.FindByIDWithCtx(ctx, contestID, contest)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrContestNotFound
+		}
+		return nil, fmt.Errorf("failed to get contest: %w", err)
+	}
+	return contest, nil
+}
+
+// ListContests returns contests, the latest start first.
+func ListContests(ctx context.Context, page, limit int64) ([]models.Contest, error) {
+	if page <= 0 {
+		page = 1
+	}
+	if limit <= 0 {
+		limit = 20
+	}
+	const maxLimit int64 = 100
+	if limit > maxLimit {
+		limit = maxLimit
+	}
+
+	skip := (page - 1) * limit
+	coll := mgm.Coll(&models.Contest{})
+	cursor, err := coll.Find(
+		ctx,
+		bson.M{},
+		options.Find().
+			SetSort(bson.M{"startAt": -1}).
+			SetSkip(skip).
+			SetLimit(limit),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get contests: %w", err)
+	}
+	defer cursor.Close(ctx)
+	var contests []models.Contest
+	if err := cursor.All(ctx, &contests); err != nil {
+		return nil, fmt.Errorf("failed to decode contests: %w", err)
+	}
+	return contests, nil
+}
+
+// DeleteContest deletes a contest and its registrations.
+func DeleteContest(ctx context.Context, contest *models.Contest) error {
+	if _, err := mgm.Coll(&models.ContestParticipant{}).DeleteMany(ctx, bson.M{"contestId": contest.ID}); err != nil {
+		return fmt.Errorf("failed to delete participants: %w", err)
+	}
+	return mgm.Coll(contest).DeleteWithCtx(ctx, contest)
+}
+
+func RegisterContestParticipant(ctx context.Context, contestID, userID primitive.ObjectID) (*models.ContestParticipant, error) {
+	participant := &models.ContestParticipant{ContestID: contestID, UserID: userID}
+	if err := mgm.Coll(participant).CreateWithCtx(ctx, participant); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, ErrAlreadyRegistered
+		}
+		return nil, fmt.Errorf("failed to register participant: %w", err)
+	}
+	return participant, nil
+}
+
+func IsContestParticipant(ctx context.Context, contestID, userID primitive.ObjectID) (bool, error) {
+	count, err := mgm.Coll(&models.ContestParticipant{}).CountDocuments(ctx, bson.M{
+		"contestId": contestID,
+		"userId":    userID,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to check participant: %w", err)
+	}
+	return count > 0, nil
+}
+
+func GetContestParticipants(ctx context.Context, contestID primitive.ObjectID) ([]models.ContestParticipant, error) {
+	coll := mgm.Coll(&models.ContestParticipant{})
+	cursor, err := coll.Find(ctx, bson.M{"contestId": contestID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get participants: %w", err)
+	}
+	defer cursor.Close(ctx)
+	var participants []models.ContestParticipant
+	if err := cursor.All(ctx, &participants); err != nil {
+		return nil, fmt.Errorf("failed to decode participants: %w", err)
+	}
+	return participants, nil
+}
+
+// GetUnstartedContestProblemIDs returns the problems of contests that
+// start after now, or only problemID among them if it is not zero.
+func GetUnstartedContestProblemIDs(ctx context.Context, now time.Time, problemID primitive.ObjectID) ([]primitive.ObjectID, error) {
+	filter := bson.M{"startAt": bson.M{"$gt": now}}
+	if !problemID.IsZero() {
+		filter["problems.problemId"] = problemID
+	}
+	coll := mgm.Coll(&models.Contest{})
+	cursor, err := coll.Find(ctx, filter, options.Find().SetProjection(bson.M{"problems": 1}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get contests: %w", err)
+	}
+	defer cursor.Close(ctx)
+	var contests []models.Contest
+	if err := cursor.All(ctx, &contests); err != nil {
+		return nil, fmt.Errorf("failed to decode contests: %w", err)
+	}
+
+	var ids []primitive.ObjectID
+	for _, c := range contests {
+		for _, p := range c.Problems {
+			if problemID.IsZero() || p.ProblemID == problemID {
+				ids = append(ids, p.ProblemID)
+			}
+		}
+	}
+	return ids, nil
+}
+
+// GetContestJobs returns the jobs of a contest in submission order,
+// with only the fields scoring needs.
+func GetContestJobs(ctx context.Context, contestID primitive.ObjectID) ([]models.Job, error) {
+	coll := mgm.Coll(&models.Job{})
+	cursor, err := coll.Find(
+		ctx,
+		bson.M{"contestId": contestID},
+		options.Find().
+			SetSort(bson.M{"queuedAt": 1}).
+			SetProjection(bson.M{
+				"userId":      1,
+				"problemId":   1,
+				"status":      1,
+				"errorType":   1,
+				"verdict":     1,
+				"testResults": 1,
+				"queuedAt":    1,
+			}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get contest jobs: %w", err)
+	}
+	defer cursor.Close(ctx)
+	var jobs []models.Job
+	if err := cursor.All(ctx, &jobs); err != nil {
+		return nil, fmt.Errorf("failed to decode contest jobs: %w", err)
+	}
+	return jobs, nil
+}
@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/anurag-327/neuron/internal/models"
+	"github.com/kamva/mgm/v3"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var ErrIdempotencyKeyExists = errors.New("idempotency key already exists")
+
+// CreateIdempotencyKey stores key. When its user already holds an
+// unexpired key of the same name, the stored key is returned with
+// ErrIdempotencyKeyExists; it is nil if that key was deleted meanwhile.
+func CreateIdempotencyKey(ctx context.Context, key *models.IdempotencyKey) (*models.IdempotencyKey, error) {
+	coll := mgm.Coll(key)
+	filter := bson.M{"userId": key.UserID, "key": key.Key}
+
+	// Expired keys linger until the TTL monitor runs
+	_, err := coll.DeleteOne(ctx, bson.M{
+		"userId":    key.UserID,
+		"key":       key.Key,
+		"expiresAt": bson.M{"$lte": time.Now()},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete expired idempotency key: %w", err)
+	}
+
+	if err := coll.CreateWithCtx(ctx, key); err != nil {
+		if !mongo.IsDuplicateKeyError(err) {
+			return nil, fmt.Errorf("failed to create idempotency key: %w", err)
+		}
+		existing := &models.IdempotencyKey{}
+		if err := coll.FirstWithCtx(ctx, filter, existing); err != nil {
+			if errors.Is(err, mongo.ErrNoDocuments) {
+				return nil, ErrIdempotencyKeyExists
+			}
+			return nil, fmt.Errorf("failed to get idempotency key: %w", err)
+		}
+		return existing, ErrIdempotencyKeyExists
+	}
+	return key, nil
+}
+
+// CompleteIdempotencyKey attaches the job queued under key and keeps
+// the key until expiresAt.
+func CompleteIdempotencyKey(ctx context.Context, key *models.IdempotencyKey, jobID primitive.ObjectID, expiresAt time.Time) error {
+	key.JobID = &jobID
+	key.ExpiresAt = expiresAt
+	_, err := mgm.Coll(key).UpdateByID(ctx, key.ID, bson.M{"$set": bson.M{
+		"jobId":      jobID,
+		"expiresAt":  expiresAt,
+		"updated_at": time.Now().UTC(),
+	}})
+	if err != nil {
+		return fmt.Errorf("failed to complete idempotency key: %w", err)
+	}
+	return nil
+}
+
+func DeleteIdempotencyKey(ctx context.Context, key *models.IdempotencyKey) error {
+	if _, err := mgm.Coll(key).DeleteOne(ctx, bson.M{"_id": key.ID}); err != nil {
+		return fmt.Errorf("failed to delete idempotency key: %w", err)
+	}
+	return nil
+}
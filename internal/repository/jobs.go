@@ -1,201 +1,399 @@
-package repository
-
-import (
-	"context"
-	"errors"
-	"fmt"
-
-	"github.com/anurag-327/neuron/internal/models"
-	"github.com/kamva/mgm/v3"
-	"go.mongodb.org/mongo-driver/bson"
-	"go.mongodb.org/mongo-driver/bson/primitive"
-	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
-)
-
-var ErrJobNotFound = errors.New("job not found")
-
-func SaveJob(ctx context.Context, job *models.Job) (*models.Job, error) {
-	coll := mgm.Coll(job)
-	if job.ID.IsZero() {
-		// New job → create
-		if err := coll.CreateWithCtx(ctx, job); err != nil {
-			return nil, err
-		}
-	} else {
-		// Existing job → update
-		if err := coll.UpdateWithCtx(ctx, job); err != nil {
-			return nil, err
-		}
-	}
-	return job, nil
-}
-
-func GetJobByID(ctx context.Context, id string) (*models.Job, error) {
-	job := &models.Job{}
-	coll := mgm.Coll(job)
-	if err := coll.FindByIDWithCtx(ctx, id, job); err != nil {
-		if errors.Is(err, mongo.ErrNoDocuments) {
-			return nil, ErrJobNotFound
-		}
-		return nil, err
-	}
-	return job, nil
-}
-
-func GetJobByIDAndUserID(
-	ctx context.Context,
-	jobID primitive.ObjectID,
-	userID primitive.ObjectID,
-) (*models.Job, error) {
-
-	job := &models.Job{}
-	coll := mgm.Coll(job)
-
-	err := coll.FindOne(
-		ctx,
-		bson.M{
-			"_id":    jobID,
-			"userId": userID,
-		},
-	).Decode(job)
-
-	if err != nil {
-		if errors.Is(err, mongo.ErrNoDocuments) {
-			return nil, ErrJobNotFound
-		}
-		return nil, fmt.Errorf("failed to get job: %w", err)
-	}
-	return job, nil
-}
-
-func DeleteJob(ctx context.Context, job *models.Job) error {
-	coll := mgm.Coll(job)
-	return coll.DeleteWithCtx(ctx, job)
-}
-
-func GetJobsByUserID(ctx context.Context, userID primitive.ObjectID, page, limit int64) ([]models.Job, error) {
-	if page <= 0 {
-		page = 1
-	}
-	if limit <= 0 {
-		limit = 20
-	}
-	const maxLimit int64 = 100
-	if limit > maxLimit {
-		limit = maxLimit
-	}
-
-	skip := (page - 1) * limit
-	coll := mgm.Coll(&models.Job{})
-	cursor, err := coll.Find(
-		ctx,
-		bson.M{"userId": userID},
-		options.Find().
-			SetSort(bson.M{"created_at": -1}).
-			SetSkip(skip).
-			SetLimit(limit),
-	)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get jobs: %w", err)
-	}
-	defer cursor.Close(ctx)
-	var jobs []models.Job
-	if err := cursor.All(ctx, &jobs); err != nil {
-		return nil, fmt.Errorf("failed to decode jobs: %w", err)
-	}
-	return jobs, nil
-}
-
-func CountJobsByUserID(ctx context.Context, userID primitive.ObjectID) (int64, error) {
-	coll := mgm.Coll(&models.Job{})
-	count, err := coll.CountDocuments(ctx, bson.M{"userId": userID})
-	if err != nil {
-		return 0, fmt.Errorf("failed to count jobs: %w", err)
-	}
-	return count, nil
-}
-
-// GetJobStatsByUserID aggregates job statistics for a user within a date range
-func GetJobStatsByUserID(
-	ctx context.Context,
-	userID primitive.ObjectID,
-	startDate, endDate primitive.DateTime,
-) (*models.JobStats, error) {
-
-	coll := mgm.Coll(&models.Job{})
-	matchFilter := bson.M{"userId": userID}
-
-	// Date filter
-	if startDate > 0 || endDate > 0 {
-		dateFilter := bson.M{}
-		if startDate > 0 {
-			dateFilter["$gte"] = startDate
-		}
-		if endDate > 0 {
-			dateFilter["$lte"] = endDate
-		}
-		// Assuming we care about when the job finished for stats
-		matchFilter["finishedAt"] = dateFilter
-	}
-	// Also ensure job is finished
-	matchFilter["status"] = bson.M{"$in": bson.A{models.StatusSuccess, models.StatusFailed, models.StatusQueued, models.StatusRunning}}
-	// Actually for "executed" stats we probably only want Success/Failed
-	matchFilter["status"] = bson.M{"$in": bson.A{models.StatusSuccess, models.StatusFailed}}
-
-	pipeline := mongo.Pipeline{
-		{{Key: "$match", Value: matchFilter}},
-		{{Key: "$group", Value: bson.M{
-			"_id":               nil,
-			"totalExecutedJobs": bson.M{"$sum": 1},
-			"successJobs": bson.M{
-				"$sum": bson.M{
-					"$cond": bson.A{
-						bson.M{"$eq": bson.A{"$status", models.StatusSuccess}},
-						1,
-						0,
-					},
-				},
-			},
-			"failedJobs": bson.M{
-				"$sum": bson.M{
-					"$cond": bson.A{
-						bson.M{"$eq": bson.A{"$status", models.StatusFailed}},
-						1,
-						0,
-					},
-				},
-			},
-			"avgExecutionMs": bson.M{
-				"$avg": bson.M{"$subtract": bson.A{"$finishedAt", "$startedAt"}},
-			},
-		}}},
-	}
-
-	cursor, err := coll.Aggregate(ctx, pipeline)
-	if err != nil {
-		return nil, fmt.Errorf("failed to aggregate job stats: %w", err)
-	}
-	defer cursor.Close(ctx)
-
-	var results []struct {
-		TotalExecutedJobs int64   `bson:"totalExecutedJobs"`
-		SuccessJobs       int64   `bson:"successJobs"`
-		FailedJobs        int64   `bson:"failedJobs"`
-		AvgExecutionMs    float64 `bson:"avgExecutionMs"`
-	}
-
-	if err := cursor.All(ctx, &results); err != nil {
-		return nil, fmt.Errorf("failed to decode job stats: %w", err)
-	}
-
-	stats := &models.JobStats{}
-	if len(results) > 0 {
-		stats.TotalExecutedJobs = results[0].TotalExecutedJobs
-		stats.SuccessJobs = results[0].SuccessJobs
-		stats.FailedJobs = results[0].FailedJobs
-		stats.AvgExecutionMs = results[0].AvgExecutionMs
-	}
-
-	return stats, nil
-}
+package repository
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/anurag-327/neuron/internal/models"
+	"github.com/kamva/mgm/v3"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var ErrJobNotFound = errors.New("job not found")
+
+func SaveJob(ctx context.Context, job *models.Job) (*models.Job, error) {
+	coll := mgm.Coll(job)
+	if job.ID.IsZero() {
+		// New job → create
+		if err := coll.CreateWithCtx(ctx, job); err != nil {
+			return nil, err
+		}
+	} else {
+		// Existing job → update
+		if err := coll.UpdateWithCtx(ctx, job); err != nil {
+			return nil, err
+		}
+	}
+	return job, nil
+}
+
+func GetJobByID(ctx context.Context, id string) (*models.Job, error) {
+	job := &models.Job{}
+	coll := mgm.Coll(job)
+	if err := coll.FindByIDWithCtx(ctx, id, job); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrJobNotFound
+		}
+		return nil, err
+	}
+	return job, nil
+}
+
+func GetJobByIDAndUserID(
+	ctx context.Context,
+	jobID primitive.ObjectID,
+	userID primitive.ObjectID,
+) (*models.Job, error) {
+
+	job := &models.Job{}
+	coll := mgm.Coll(job)
+
+	err := coll.FindOne(
+		ctx,
+		bson.M{
+			"_id":    jobID,
+			"userId": userID,
+		},
+	).Decode(job)
+
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrJobNotFound
+		}
+		return nil, fmt.Errorf("failed to get job: %w", err)
+	}
+	return job, nil
+}
+
+func DeleteJob(ctx context.Context, job *models.Job) error {
+	coll := mgm.Coll(job)
+	return coll.DeleteWithCtx(ctx, job)
+}
+
+func GetJobsByUserID(ctx context.Context, userID primitive.ObjectID, page, limit int64) ([]models.Job, error) {
+	if page <= 0 {
+		page = 1
+	}
+	if limit <= 0 {
+		limit = 20
+	}
+	const maxLimit int64 = 100
+	if limit > maxLimit {
+		limit = maxLimit
+	}
+
+	skip := (page - 1) * limit
+	coll := mgm.Coll(&models.Job{})
+	cursor, err := coll.Find(
+		ctx,
+		bson.M{"userId": userID},
+		options.Find().
+			SetSort(bson.M{"created_at": -1}).
+			SetSkip(skip).
+			SetLimit(limit),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get jobs: %w", err)
+	}
+	defer cursor.Close(ctx)
+	var jobs []models.Job
+	if err := cursor.All(ctx, &jobs); err != nil {
+		return nil, fmt.Errorf("failed to decode jobs: %w", err)
+	}
+	return jobs, nil
+}
+
+func CountJobsByUserID(ctx context.Context, userID primitive.ObjectID) (int64, error) {
+	coll := mgm.Coll(&models.Job{})
+	count, err := coll.CountDocuments(ctx, bson.M{"userId": userID})
+	if err != nil {
+		return 0, fmt.Errorf("failed to count jobs: %w", err)
+	}
+	return count, nil
+}
+
+// GetJobStatsByUserID aggregates job statistics for a user within a date range
+func GetJobStatsByUserID(
+	ctx context.Context,
+	userID primitive.ObjectID,
+	startDate, endDate primitive.DateTime,
+) (*models.JobStats, error) {
+
+	coll := mgm.Coll(&models.Job{})
+	matchFilter := bson.M{"userId": userID}
+
+	// Date filter
+	if startDate > 0 || endDate > 0 {
+		dateFilter := bson.M{}
+		if startDate > 0 {
+			dateFilter["$gte"] = startDate
+		}
+		if endDate > 0 {
+			dateFilter["$lte"] = endDate
+		}
+		// Assuming we care about when the job finished for stats
+		matchFilter["finishedAt"] = dateFilter
+	}
+	// Also ensure job is finished
+	matchFilter["status"] = bson.M{"$in": bson.A{models.StatusSuccess, models.StatusFailed, models.StatusQueued, models.StatusRunning}}
+	// Actually for "executed" stats we probably only want Success/Failed
+	matchFilter["status"] = bson.M{"$in": bson.A{models.StatusSuccess, models.StatusFailed}}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: matchFilter}},
+		{{Key: "$group", Value: bson.M{
+			"_id":               nil,
+			"totalExecutedJobs": bson.M{"$sum": 1},
+			"successJobs": bson.M{
+				"$sum": bson.M{
+					"$cond": bson.A{
+						bson.M{"$eq": bson.A{"$status", models.StatusSuccess}},
+						1,
+						0,
+					},
+				},
+			},
+			"failedJobs": bson.M{
+				"$sum": bson.M{
+					"$cond": bson.A{
+						bson.M{"$eq": bson.A{"$status", models.StatusFailed}},
+						1,
+						0,
+					},
+				},
+			},
+			"avgExecutionMs": bson.M{
+				"$avg": bson.M{"$subtract": bson.A{"$finishedAt", "$startedAt"}},
+			},
+		}}},
+	}
+
+	cursor, err := coll.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate job stats: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var results []struct {
+		TotalExecutedJobs int64   `bson:"totalExecutedJobs"`
+		SuccessJobs       int64   `bson:"successJobs"`
+		FailedJobs        int64   `bson:"failedJobs"`
+		AvgExecutionMs    float64 `bson:"avgExecutionMs"`
+	}
+
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, fmt.Errorf("failed to decode job stats: %w", err)
+	}
+
+	stats := &models.JobStats{}
+	if len(results) > 0 {
+		stats.TotalExecutedJobs = results[0].TotalExecutedJobs
+		stats.SuccessJobs = results[0].SuccessJobs
+		stats.FailedJobs = results[0].FailedJobs
+		stats.AvgExecutionMs = results[0].AvgExecutionMs
+	}
+
+	return stats, nil
+}
+
+// similarityProjection holds the job fields similarity checks need.
+var similarityProjection = bson.M{
+	"userId":       1,
+	"language":     1,
+	"code":         1,
+	"fingerprints": 1,
+	"problemId":    1,
+	"contestId":    1,
+	"queuedAt":     1,
+}
+
+// GetJobsForSimilarity returns the jobs with the given IDs, with only
+// the fields similarity checks need.
+func GetJobsForSimilarity(ctx context.Context, ids []primitive.ObjectID) ([]models.Job, error) {
+	coll := mgm.Coll(&models.Job{})
+	cursor, err := coll.Find(
+		ctx,
+		bson.M{"_id": bson.M{"$in": ids}},
+		options.Find().SetProjection(similarityProjection),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get jobs: %w", err)
+	}
+	defer cursor.Close(ctx)
+	var jobs []models.Job
+	if err := cursor.All(ctx, &jobs); err != nil {
+		return nil, fmt.Errorf("failed to decode jobs: %w", err)
+	}
+	return jobs, nil
+}
+
+// GetLatestProblemJobs returns the latest job of each user submitted to
+// a problem, for at most limit users, with only the fields similarity
+// checks need.
+func GetLatestProblemJobs(ctx context.Context, problemID primitive.ObjectID, limit int) ([]models.Job, error) {
+	coll := mgm.Coll(&models.Job{})
+	cursor, err := coll.Find(
+		ctx,
+		bson.M{"problemId": problemID},
+		options.Find().
+			SetSort(bson.M{"queuedAt": -1}).
+			SetProjection(similarityProjection),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get problem jobs: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var jobs []models.Job
+	seen := map[primitive.ObjectID]bool{}
+	for len(jobs) < limit && cursor.Next(ctx) {
+		var job models.Job
+		if err := cursor.Decode(&job); err != nil {
+			return nil, fmt.Errorf("failed to decode job: %w", err)
+		}
+		if seen[job.UserID] {
+			continue
+		}
+		seen[job.UserID] = true
+		jobs = append(jobs, job)
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get problem jobs: %w", err)
+	}
+	return jobs, nil
+}
+
+// GetRerunChain returns the first job of a rerun chain of a user and
+// all its reruns in submission order, without their code and output.
+func GetRerunChain(ctx context.Context, rootJobID, userID primitive.ObjectID) ([]models.Job, error) {
+	coll := mgm.Coll(&models.Job{})
+	cursor, err := coll.Find(
+		ctx,
+		bson.M{
+			"userId": userID,
+			"$or": bson.A{
+				bson.M{"_id": rootJobID},
+				bson.M{"rootJobId": rootJobID},
+			},
+		},
+		options.Find().
+			SetSort(bson.M{"queuedAt": 1}).
+			SetProjection(bson.M{
+				"status":      1,
+				"language":    1,
+				"version":     1,
+				"errorType":   1,
+				"exitCode":    1,
+				"verdict":     1,
+				"parentJobId": 1,
+				"rootJobId":   1,
+				"timeLimitMs": 1,
+				"queuedAt":    1,
+				"startedAt":   1,
+				"finishedAt":  1,
+			}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rerun chain: %w", err)
+	}
+	defer cursor.Close(ctx)
+	var jobs []models.Job
+	if err := cursor.All(ctx, &jobs); err != nil {
+		return nil, fmt.Errorf("failed to decode rerun chain: %w", err)
+	}
+	return jobs, nil
+}
+
+// JobFilter selects the jobs of a user returned by ListJobs. Zero
+// fields match any job. Search matches code containing its words in
+// order; Before is the ID the page starts after, newest first.
+type JobFilter struct {
+	UserID    primitive.ObjectID
+	Status    models.RunStatus
+	Language  string
+	ErrorType models.SandboxError
+	From      time.Time
+	To        time.Time
+	Search    string
+	Before    primitive.ObjectID
+	Limit     int64
+}
+
+// jobSummaryProjection drops the job fields that can be large: code,
+// input, output and everything derived from them.
+var jobSummaryProjection = bson.M{
+	"code":           0,
+	"input":          0,
+	"stdout":         0,
+	"stderr":         0,
+	"diagnostics":    0,
+	"args":           0,
+	"env":            0,
+	"files":          0,
+	"expectedOutput": 0,
+	"fingerprints":   0,
+}
+
+// ListJobs returns up to filter.Limit jobs matching filter, newest
+// first, as summaries without their code, input and output. Paging on
+// _id keeps deep pages as fast as the first.
+func ListJobs(ctx context.Context, filter JobFilter) ([]models.Job, error) {
+	query := bson.M{"userId": filter.UserID}
+	if filter.Status != "" {
+		query["status"] = filter.Status
+	}
+	if filter.Language != "" {
+		query["language"] = filter.Language
+	}
+	if filter.ErrorType != "" {
+		query["errorType"] = filter.ErrorType
+	}
+	if filter.Search != "" {
+		query["$text"] = bson.M{"$search": `"` + filter.Search + `"`}
+	}
+
+	// The date range is a range of IDs, which embed their creation time
+	idRange := bson.M{}
+	if !filter.From.IsZero() {
+		idRange["$gte"] = primitive.NewObjectIDFromTimestamp(filter.From)
+	}
+	upper := filter.Before
+	if !filter.To.IsZero() {
+		// IDs hold whole seconds: stop before the next second
+		to := primitive.NewObjectIDFromTimestamp(filter.To.Truncate(time.Second).Add(time.Second))
+		if upper.IsZero() || bytes.Compare(to[:], upper[:]) < 0 {
+			upper = to
+		}
+	}
+	if !upper.IsZero() {
+		idRange["$lt"] = upper
+	}
+	if len(idRange) > 0 {
+		query["_id"] = idRange
+	}
+
+	coll := mgm.Coll(&models.Job{})
+	cursor, err := coll.Find(
+		ctx,
+		query,
+		options.Find().
+			SetSort(bson.M{"_id": -1}).
+			SetLimit(filter.Limit).
+			SetProjection(jobSummaryProjection),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list jobs: %w", err)
+	}
+	defer cursor.Close(ctx)
+	jobs := []models.Job{}
+	if err := cursor.All(ctx, &jobs); err != nil {
+		return nil, fmt.Errorf("failed to decode jobs: %w", err)
+	}
+	return jobs, nil
+}
@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/anurag-327/neuron/internal/models"
+	"github.com/kamva/mgm/v3"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var ErrProblemNotFound = errors.New("problem not found")
+
+func SaveProblem(ctx context.Context, problem *models.Problem) (*models.Problem, error) {
+	coll := mgm.Coll(problem)
+	if problem.ID.IsZero() {
+		if err := coll.CreateWithCtx(ctx, problem); err != nil {
+			return nil, err
+		}
+	} else {
+		if err := coll.UpdateWithCtx(ctx, problem); err != nil {
+			return nil, err
+		}
+	}
+	return problem, nil
+}
+
+func GetProblemByID(ctx context.Context, problemID primitive.ObjectID) (*models.Problem, error) {
+	problem := &models.Problem{}
+	coll := mgm.Coll(problem)
+
+	err := coll.FindByIDWithCtx(ctx, problemID, problem)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrProblemNotFound
+		}
+		return nil, fmt.Errorf("failed to get problem: %w", err)
+	}
+	return problem, nil
+}
+
+// ListProblems returns problems newest first, without their tests.
+// A non-zero authorID lists only the problems of that author. Problems
+// in hidden are left out unless viewerID is their author.
+func ListProblems(ctx context.Context, authorID, viewerID primitive.ObjectID, hidden []primitive.ObjectID, page, limit int64) ([]models.Problem, error) {
+	if page <= 0 {
+		page = 1
+	}
+	if limit <= 0 {
+		limit = 20
+	}
+	const maxLimit int64 = 100
+	if limit > maxLimit {
+		limit = maxLimit
+	}
+
+	filter := bson.M{}
+	if !authorID.IsZero() {
+		filter["authorId"] = authorID
+	}
+	if len(hidden) > 0 {
+		filter["$or"] = bson.A{
+			bson.M{"authorId": viewerID},
+			bson.M{"_id": bson.M{"$nin": hidden}},
+		}
+	}
+
+	skip := (page - 1) * limit
+	coll := mgm.Coll(&models.Problem{})
+	cursor, err := coll.Find(
+		ctx,
+		filter,
+		options.Find().
+			SetSort(bson.M{"created_at": -1}).
+			SetSkip(skip).
+			SetLimit(limit).
+			SetProjection(bson.M{"tests": 0, "statement": 0}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get problems: %w", err)
+	}
+	defer cursor.Close(ctx)
+	var problems []models.Problem
+	if err := cursor.All(ctx, &problems); err != nil {
+		return nil, fmt.Errorf("failed to decode problems: %w", err)
+	}
+	return problems, nil
+}
+
+func DeleteProblem(ctx context.Context, problem *models.Problem) error {
+	coll := mgm.Coll(problem)
+	return coll.DeleteWithCtx(ctx, problem)
+}
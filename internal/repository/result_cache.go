@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/anurag-327/neuron/internal/models"
+	"github.com/kamva/mgm/v3"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var ErrCachedResultNotFound = errors.New("cached result not found")
+
+// GetCachedResult returns the unexpired cached result stored under key.
+func GetCachedResult(ctx context.Context, key string) (*models.CachedResult, error) {
+	result := &models.CachedResult{}
+	err := mgm.Coll(result).FirstWithCtx(ctx, bson.M{
+		"key":       key,
+		"expiresAt": bson.M{"$gt": time.Now()},
+	}, result)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrCachedResultNotFound
+		}
+		return nil, fmt.Errorf("failed to get cached result: %w", err)
+	}
+	return result, nil
+}
+
+// SaveCachedResult stores result under its key, replacing any result
+// already stored there.
+func SaveCachedResult(ctx context.Context, result *models.CachedResult) error {
+	now := time.Now().UTC()
+	result.CreatedAt = now
+	result.UpdatedAt = now
+
+	_, err := mgm.Coll(result).ReplaceOne(
+		ctx,
+		bson.M{"key": result.Key},
+		result,
+		options.Replace().SetUpsert(true),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save cached result: %w", err)
+	}
+	return nil
+}
+
+// DeleteCachedResults drops the cached results of a language, or of one
+// of its versions when version is set, and returns how many there were.
+func DeleteCachedResults(ctx context.Context, language, version string) (int64, error) {
+	filter := bson.M{"language": language}
+	if version != "" {
+		filter["version"] = version
+	}
+	res, err := mgm.Coll(&models.CachedResult{}).DeleteMany(ctx, filter)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete cached results: %w", err)
+	}
+	return res.DeletedCount, nil
+}
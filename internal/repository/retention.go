@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kamva/mgm/v3"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// GetRetainedDocuments returns up to limit whole documents of the
+// collection of model created before cutoff and owned by the users
+// matched by users, oldest first. Documents matched by a non-nil exempt
+// are skipped, and with unarchivedOnly those already archived.
+func GetRetainedDocuments(
+	ctx context.Context,
+	model mgm.Model,
+	users bson.M,
+	exempt bson.M,
+	cutoff time.Time,
+	unarchivedOnly bool,
+	limit int64,
+) ([]bson.Raw, error) {
+	filter := bson.M{
+		"userId": users,
+		"_id":    bson.M{"$lt": primitive.NewObjectIDFromTimestamp(cutoff)},
+	}
+	if exempt != nil {
+		filter["$nor"] = bson.A{exempt}
+	}
+	if unarchivedOnly {
+		filter["archivedAt"] = bson.M{"$exists": false}
+	}
+
+	coll := mgm.Coll(model)
+	cursor, err := coll.Find(
+		ctx,
+		filter,
+		options.Find().
+			SetSort(bson.M{"_id": 1}).
+			SetLimit(limit),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s: %w", coll.Name(), err)
+	}
+	defer cursor.Close(ctx)
+	var docs []bson.Raw
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", coll.Name(), err)
+	}
+	return docs, nil
+}
+
+// StripDocuments removes fields from documents of the collection of
+// model and marks them archived at archivedAt.
+func StripDocuments(ctx context.Context, model mgm.Model, ids []primitive.ObjectID, fields []string, archivedAt time.Time) error {
+	unset := bson.M{}
+	for _, f := range fields {
+		unset[f] = ""
+	}
+	coll := mgm.Coll(model)
+	_, err := coll.UpdateMany(ctx, bson.M{"_id": bson.M{"$in": ids}}, bson.M{
+		"$unset": unset,
+		"$set":   bson.M{"archivedAt": archivedAt},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to strip %s: %w", coll.Name(), err)
+	}
+	return nil
+}
+
+// DeleteDocuments deletes documents of the collection of model.
+func DeleteDocuments(ctx context.Context, model mgm.Model, ids []primitive.ObjectID) error {
+	coll := mgm.Coll(model)
+	if _, err := coll.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": ids}}); err != nil {
+		return fmt.Errorf("failed to delete %s: %w", coll.Name(), err)
+	}
+	return nil
+}
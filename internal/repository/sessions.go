@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/anurag-327/neuron/internal/models"
+	"github.com/kamva/mgm/v3"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	ErrSessionNotFound = errors.New("session not found")
+
+	// ErrSessionUnavailable is returned when joining a session that is
+	// already active or closed, has expired, or when the token is wrong.
+	ErrSessionUnavailable = errors.New("session is not available")
+)
+
+func SaveSession(ctx context.Context, session *models.Session) (*models.Session, error) {
+	coll := mgm.Coll(session)
+	if session.ID.IsZero() {
+		if err := coll.CreateWithCtx(ctx, session); err != nil {
+			return nil, err
+		}
+	} else {
+		if err := coll.UpdateWithCtx(ctx, session); err != nil {
+			return nil, err
+		}
+	}
+	return session, nil
+}
+
+func GetSessionByIDAndUserID(
+	ctx context.Context,
+	sessionID primitive.ObjectID,
+	userID primitive.ObjectID,
+) (*models.Session, error) {
+
+	session := &models.Session{}
+	coll := mgm.Coll(session)
+
+	err := coll.FindOne(
+		ctx,
+		bson.M{
+			"_id":    sessionID,
+			"userId": userID,
+		},
+	).Decode(session)
+
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrSessionNotFound
+		}
+		return nil, fmt.Errorf("failed to get session: %w", err)
+	}
+	return session, nil
+}
+
+// ActivateSession atomically moves a pending, unexpired session with
+// the given token hash to active, so each token is used only once.
+func ActivateSession(
+	ctx context.Context,
+	sessionID primitive.ObjectID,
+	tokenHash string,
+) (*models.Session, error) {
+
+	now := time.Now()
+	session := &models.Session{}
+	coll := mgm.Coll(session)
+
+	err := coll.FindOneAndUpdate(
+		ctx,
+		bson.M{
+			"_id":       sessionID,
+			"tokenHash": tokenHash,
+			"status":    models.SessionPending,
+			"expiresAt": bson.M{"$gt": now},
+		},
+		bson.M{
+			"$set": bson.M{
+				"status":     models.SessionActive,
+				"startedAt":  now,
+				"updated_at": now,
+			},
+		},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(session)
+
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrSessionUnavailable
+		}
+		return nil, fmt.Errorf("failed to activate session: %w", err)
+	}
+	return session, nil
+}
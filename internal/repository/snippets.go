@@ -0,0 +1,222 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/anurag-327/neuron/internal/models"
+	"github.com/kamva/mgm/v3"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	ErrSnippetNotFound         = errors.New("snippet not found")
+	ErrSnippetRevisionNotFound = errors.New("snippet revision not found")
+)
+
+func SaveSnippet(ctx context.Context, snippet *models.Snippet) (*models.Snippet, error) {
+	coll := mgm.Coll(snippet)
+	if snippet.ID.IsZero() {
+		if err := coll.CreateWithCtx(ctx, snippet); err != nil {
+			return nil, err
+		}
+	} else {
+		if err := coll.UpdateWithCtx(ctx, snippet); err != nil {
+			return nil, err
+		}
+	}
+	return snippet, nil
+}
+
+func GetSnippetByID(ctx context.Context, snippetID primitive.ObjectID) (*models.Snippet, error) {
+	snippet := &models.Snippet{}
+	coll := mgm.Coll(snippet)
+
+	err := coll.FindByIDWithCtx(ctx, snippetID, snippet)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrSnippetNotFound
+		}
+		return nil, fmt.Errorf("failed to get snippet: %w", err)
+	}
+	return snippet, nil
+}
+
+// GetSnippetByShareTokenHash returns the snippet shared under a token,
+// whether or not its link has expired.
+func GetSnippetByShareTokenHash(ctx context.Context, tokenHash string) (*models.Snippet, error) {
+	snippet := &models.Snippet{}
+	coll := mgm.Coll(snippet)
+
+	err := coll.FirstWithCtx(ctx, bson.M{"shareTokenHash": tokenHash}, snippet)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrSnippetNotFound
+		}
+		return nil, fmt.Errorf("failed to get snippet: %w", err)
+	}
+	return snippet, nil
+}
+
+// ListSnippets returns the snippets of an author, the latest edited
+// first, without their code and input.
+func ListSnippets(ctx context.Context, authorID primitive.ObjectID, page, limit int64) ([]models.Snippet, error) {
+	if page <= 0 {
+		page = 1
+	}
+	if limit <= 0 {
+		limit = 20
+	}
+	const maxLimit int64 = 100
+	if limit > maxLimit {
+		limit = maxLimit
+	}
+
+	skip := (page - 1) * limit
+	coll := mgm.Coll(&models.Snippet{})
+	cursor, err := coll.Find(
+		ctx,
+		bson.M{"authorId": authorID},
+		options.Find().
+			SetSort(bson.M{"updated_at": -1}).
+			SetSkip(skip).
+			SetLimit(limit).
+			SetProjection(bson.M{"code": 0, "input": 0}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get snippets: %w", err)
+	}
+	defer cursor.Close(ctx)
+	var snippets []models.Snippet
+	if err := cursor.All(ctx, &snippets); err != nil {
+		return nil, fmt.Errorf("failed to decode snippets: %w", err)
+	}
+	return snippets, nil
+}
+
+// DeleteSnippet deletes a snippet and its revisions. Its forks and jobs
+// are kept.
+func DeleteSnippet(ctx context.Context, snippet *models.Snippet) error {
+	if _, err := mgm.Coll(&models.SnippetRevision{}).DeleteMany(ctx, bson.M{"snippetId": snippet.ID}); err != nil {
+		return fmt.Errorf("failed to delete snippet revisions: %w", err)
+	}
+	if snippet.LastJobID != nil {
+		if err := unmarkSnippetJob(ctx, *snippet.LastJobID); err != nil {
+			return err
+		}
+	}
+	return mgm.Coll(snippet).DeleteWithCtx(ctx, snippet)
+}
+
+// UpdateSnippetShare sets the share link of a snippet, or removes it
+// when tokenHash is empty.
+func UpdateSnippetShare(ctx context.Context, snippet *models.Snippet, tokenHash string, expiresAt *time.Time) error {
+	set := bson.M{"updated_at": time.Now().UTC()}
+	unset := bson.M{}
+	if tokenHash == "" {
+		unset["shareTokenHash"] = ""
+		unset["shareExpiresAt"] = ""
+	} else {
+		set["shareTokenHash"] = tokenHash
+		if expiresAt != nil {
+			set["shareExpiresAt"] = *expiresAt
+		} else {
+			unset["shareExpiresAt"] = ""
+		}
+	}
+	update := bson.M{"$set": set}
+	if len(unset) > 0 {
+		update["$unset"] = unset
+	}
+
+	if _, err := mgm.Coll(snippet).UpdateByID(ctx, snippet.ID, update); err != nil {
+		return fmt.Errorf("failed to update snippet share: %w", err)
+	}
+	snippet.ShareTokenHash = tokenHash
+	snippet.ShareExpiresAt = expiresAt
+	if tokenHash == "" {
+		snippet.ShareExpiresAt = nil
+	}
+	return nil
+}
+
+// SetSnippetLastJob records the latest run of a snippet and moves the
+// latestSnippetRun mark, which data retention exempts, from its
+// previous run to it.
+func SetSnippetLastJob(ctx context.Context, snippetID, jobID primitive.ObjectID) error {
+	var previous models.Snippet
+	err := mgm.Coll(&models.Snippet{}).FindOneAndUpdate(ctx,
+		bson.M{"_id": snippetID},
+		bson.M{"$set": bson.M{"lastJobId": jobID}},
+		options.FindOneAndUpdate().
+			SetReturnDocument(options.Before).
+			SetProjection(bson.M{"lastJobId": 1}),
+	).Decode(&previous)
+	if err != nil {
+		return fmt.Errorf("failed to update snippet: %w", err)
+	}
+
+	jobs := mgm.Coll(&models.Job{})
+	if _, err := jobs.UpdateByID(ctx, jobID, bson.M{"$set": bson.M{"latestSnippetRun": true}}); err != nil {
+		return fmt.Errorf("failed to mark snippet job: %w", err)
+	}
+	if previous.LastJobID != nil && *previous.LastJobID != jobID {
+		return unmarkSnippetJob(ctx, *previous.LastJobID)
+	}
+	return nil
+}
+
+// unmarkSnippetJob removes the latestSnippetRun mark from a job.
+func unmarkSnippetJob(ctx context.Context, jobID primitive.ObjectID) error {
+	_, err := mgm.Coll(&models.Job{}).UpdateByID(ctx, jobID, bson.M{"$unset": bson.M{"latestSnippetRun": ""}})
+	if err != nil {
+		return fmt.Errorf("failed to unmark snippet job: %w", err)
+	}
+	return nil
+}
+
+func SaveSnippetRevision(ctx context.Context, revision *models.SnippetRevision) (*models.SnippetRevision, error) {
+	if err := mgm.Coll(revision).CreateWithCtx(ctx, revision); err != nil {
+		return nil, err
+	}
+	return revision, nil
+}
+
+// ListSnippetRevisions returns the revisions of a snippet, the latest
+// first, without their code and input.
+func ListSnippetRevisions(ctx context.Context, snippetID primitive.ObjectID) ([]models.SnippetRevision, error) {
+	coll := mgm.Coll(&models.SnippetRevision{})
+	cursor, err := coll.Find(
+		ctx,
+		bson.M{"snippetId": snippetID},
+		options.Find().
+			SetSort(bson.M{"revision": -1}).
+			SetProjection(bson.M{"code": 0, "input": 0}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get snippet revisions: %w", err)
+	}
+	defer cursor.Close(ctx)
+	var revisions []models.SnippetRevision
+	if err := cursor.All(ctx, &revisions); err != nil {
+		return nil, fmt.Errorf("failed to decode snippet revisions: %w", err)
+	}
+	return revisions, nil
+}
+
+func GetSnippetRevision(ctx context.Context, snippetID primitive.ObjectID, revision int) (*models.SnippetRevision, error) {
+	rev := &models.SnippetRevision{}
+	err := mgm.Coll(rev).FirstWithCtx(ctx, bson.M{"snippetId": snippetID, "revision": revision}, rev)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrSnippetRevisionNotFound
+		}
+		return nil, fmt.Errorf("failed to get snippet revision: %w", err)
+	}
+	return rev, nil
+}
@@ -150,3 +150,49 @@ func AddUserCredits(
 
 	return updatedUser.Credits, nil
 }
+
+// GetUsersByIDs returns the users with the given IDs, by ID.
+func GetUsersByIDs(ctx context.Context, ids []primitive.ObjectID) (map[primitive.ObjectID]models.User, error) {
+	users := make(map[primitive.ObjectID]models.User, len(ids))
+	if len(ids) == 0 {
+		return users, nil
+	}
+
+	coll := mgm.Coll(&models.User{})
+	cursor, err := coll.Find(
+		ctx,
+		bson.M{"_id": bson.M{"$in": ids}},
+		options.Find().SetProjection(bson.M{"username": 1, "name": 1}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get users: %w", err)
+	}
+	defer cursor.Close(ctx)
+	var list []models.User
+	if err := cursor.All(ctx, &list); err != nil {
+		return nil, fmt.Errorf("failed to decode users: %w", err)
+	}
+	for _, u := range list {
+		users[u.ID] = u
+	}
+	return users, nil
+}
+
+// GetUserIDsByPlan returns the IDs of the users on plan.
+func GetUserIDsByPlan(ctx context.Context, plan models.PlanType) ([]primitive.ObjectID, error) {
+	coll := mgm.Coll(&models.User{})
+	cursor, err := coll.Find(ctx, bson.M{"plan": plan}, options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get users: %w", err)
+	}
+	defer cursor.Close(ctx)
+	var list []models.User
+	if err := cursor.All(ctx, &list); err != nil {
+		return nil, fmt.Errorf("failed to decode users: %w", err)
+	}
+	ids := make([]primitive.ObjectID, len(list))
+	for i, u := range list {
+		ids[i] = u.ID
+	}
+	return ids, nil
+}
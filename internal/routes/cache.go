@@ -0,0 +1,15 @@
+package routes
+
+import (
+	cacheHandler "github.com/anurag-327/neuron/internal/handler/cache"
+	"github.com/anurag-327/neuron/internal/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterCacheRoutes(router *gin.RouterGroup) {
+	cacheRouter := router.Group("/admin/result-cache")
+	cacheRouter.Use(middleware.VerifyAdminMiddleware())
+	{
+		cacheRouter.DELETE("", cacheHandler.InvalidateResultCacheHandler)
+	}
+}
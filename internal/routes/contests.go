@@ -0,0 +1,22 @@
+package routes
+
+import (
+	contestHandler "github.com/anurag-327/neuron/internal/handler/contest"
+	"github.com/anurag-327/neuron/internal/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterContestRoutes(router *gin.RouterGroup) {
+	contestRouter := router.Group("/contests")
+	contestRouter.Use(middleware.HybridAuthMiddleware())
+	{
+		contestRouter.POST("", contestHandler.CreateContestHandler)
+		contestRouter.GET("", contestHandler.ListContestsHandler)
+		contestRouter.GET("/:contestId", contestHandler.GetContestHandler)
+		contestRouter.PUT("/:contestId", contestHandler.UpdateContestHandler)
+		contestRouter.DELETE("/:contestId", contestHandler.DeleteContestHandler)
+		contestRouter.POST("/:contestId/register", contestHandler.RegisterContestHandler)
+		contestRouter.POST("/:contestId/unfreeze", contestHandler.UnfreezeContestHandler)
+		contestRouter.GET("/:contestId/scoreboard", contestHandler.GetScoreboardHandler)
+	}
+}
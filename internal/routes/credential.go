@@ -14,5 +14,6 @@ func RegisterCredentialRoutes(router *gin.RouterGroup) {
 		credRouter.GET("/get", credentialHandler.GetCredentialHandler)
 		credRouter.POST("/reveal", credentialHandler.RevealCredentialHandler)
 		credRouter.DELETE("/revoke", credentialHandler.RevokeCredentialHandler)
+		credRouter.PUT("/libraries", credentialHandler.SetCredentialLibrariesHandler)
 	}
 }
@@ -11,5 +11,13 @@ func RegisterRunnerRoutes(router *gin.RouterGroup) {
 	{
 		runnerRouter.POST("/submit", middleware.SubmissionRateLimit(), middleware.HybridAuthMiddleware(), runnerHandler.SubmitCodeHandler)
 		runnerRouter.GET("/:jobId/result", middleware.HybridAuthMiddleware(), runnerHandler.GetJobStatusHandler)
+		runnerRouter.GET("/:jobId/artifacts/*name", middleware.HybridAuthMiddleware(), runnerHandler.GetJobArtifactHandler)
+		runnerRouter.GET("/:jobId/output/:stream", middleware.HybridAuthMiddleware(), runnerHandler.GetJobOutputHandler)
+		runnerRouter.POST("/:jobId/rerun", middleware.SubmissionRateLimit(), middleware.HybridAuthMiddleware(), runnerHandler.RerunJobHandler)
+		runnerRouter.GET("/:jobId/reruns", middleware.HybridAuthMiddleware(), runnerHandler.ListRerunsHandler)
 	}
 }
+
+func RegisterJobRoutes(router *gin.RouterGroup) {
+	router.GET("/jobs", middleware.HybridAuthMiddleware(), runnerHandler.ListJobsHandler)
+}
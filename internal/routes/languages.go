@@ -0,0 +1,10 @@
+package routes
+
+import (
+	languageHandler "github.com/anurag-327/neuron/internal/handler/language"
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterLanguageRoutes(router *gin.RouterGroup) {
+	router.GET("/languages", languageHandler.ListLanguagesHandler)
+}
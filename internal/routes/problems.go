@@ -0,0 +1,19 @@
+package routes
+
+import (
+	problemHandler "github.com/anurag-327/neuron/internal/handler/problem"
+	"github.com/anurag-327/neuron/internal/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterProblemRoutes(router *gin.RouterGroup) {
+	problemRouter := router.Group("/problems")
+	problemRouter.Use(middleware.HybridAuthMiddleware())
+	{
+		problemRouter.POST("", problemHandler.CreateProblemHandler)
+		problemRouter.GET("", problemHandler.ListProblemsHandler)
+		problemRouter.GET("/:problemId", problemHandler.GetProblemHandler)
+		problemRouter.PUT("/:problemId", problemHandler.UpdateProblemHandler)
+		problemRouter.DELETE("/:problemId", problemHandler.DeleteProblemHandler)
+	}
+}
@@ -0,0 +1,21 @@
+package routes
+
+import (
+	sessionHandler "github.com/anurag-327/neuron/internal/handler/session"
+	"github.com/anurag-327/neuron/internal/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterSessionRoutes(router *gin.RouterGroup) {
+	sessionRouter := router.Group("/sessions")
+	{
+		sessionRouter.POST("", middleware.SubmissionRateLimit(), middleware.HybridAuthMiddleware(), sessionHandler.CreateSessionHandler)
+		sessionRouter.GET("/:sessionId", middleware.HybridAuthMiddleware(), sessionHandler.GetSessionHandler)
+	}
+}
+
+// RegisterSessionSocketRoutes registers the session WebSocket served
+// by workers. Clients authenticate with the token of the session URL.
+func RegisterSessionSocketRoutes(router *gin.Engine) {
+	router.GET("/sessions/:sessionId/ws", sessionHandler.SessionSocketHandler)
+}
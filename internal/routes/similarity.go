@@ -0,0 +1,15 @@
+package routes
+
+import (
+	similarityHandler "github.com/anurag-327/neuron/internal/handler/similarity"
+	"github.com/anurag-327/neuron/internal/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterSimilarityRoutes(router *gin.RouterGroup) {
+	similarityRouter := router.Group("/similarity")
+	similarityRouter.Use(middleware.HybridAuthMiddleware())
+	{
+		similarityRouter.POST("", similarityHandler.FindSimilarPairsHandler)
+	}
+}
@@ -0,0 +1,28 @@
+package routes
+
+import (
+	snippetHandler "github.com/anurag-327/neuron/internal/handler/snippet"
+	"github.com/anurag-327/neuron/internal/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterSnippetRoutes(router *gin.RouterGroup) {
+	snippetRouter := router.Group("/snippets")
+	snippetRouter.Use(middleware.HybridAuthMiddleware())
+	{
+		snippetRouter.POST("", snippetHandler.CreateSnippetHandler)
+		snippetRouter.GET("", snippetHandler.ListSnippetsHandler)
+		snippetRouter.GET("/:snippetId", snippetHandler.GetSnippetHandler)
+		snippetRouter.PUT("/:snippetId", snippetHandler.UpdateSnippetHandler)
+		snippetRouter.DELETE("/:snippetId", snippetHandler.DeleteSnippetHandler)
+		snippetRouter.GET("/:snippetId/revisions", snippetHandler.ListSnippetRevisionsHandler)
+		snippetRouter.GET("/:snippetId/revisions/:revision", snippetHandler.GetSnippetRevisionHandler)
+		snippetRouter.POST("/:snippetId/fork", snippetHandler.ForkSnippetHandler)
+		snippetRouter.POST("/:snippetId/share", snippetHandler.ShareSnippetHandler)
+		snippetRouter.DELETE("/:snippetId/share", snippetHandler.UnshareSnippetHandler)
+		snippetRouter.POST("/:snippetId/run", middleware.SubmissionRateLimit(), snippetHandler.RunSnippetHandler)
+	}
+
+	// Share links are public
+	router.GET("/share/:token", snippetHandler.GetSharedSnippetHandler)
+}
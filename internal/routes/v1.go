@@ -8,8 +8,16 @@ func RegisterV1Group(router *gin.Engine) {
 	v1 := router.Group("/api/v1")
 	RegisterAuthRoutes(v1)
 	RegisterRunnerRoutes(v1)
+	RegisterJobRoutes(v1)
 	RegisterUserRoutes(v1)
 	RegisterLogsRoutes(v1)
 	RegisterCredentialRoutes(v1)
 	RegisterStatsRoutes(v1)
+	RegisterLanguageRoutes(v1)
+	RegisterSessionRoutes(v1)
+	RegisterProblemRoutes(v1)
+	RegisterContestRoutes(v1)
+	RegisterSimilarityRoutes(v1)
+	RegisterSnippetRoutes(v1)
+	RegisterCacheRoutes(v1)
 }
@@ -0,0 +1,49 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+
+	"github.com/anurag-327/neuron/conn"
+	"github.com/anurag-327/neuron/internal/models"
+	"github.com/anurag-327/neuron/pkg/storage"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// SaveArtifact stores a file produced by a job and returns its metadata.
+func SaveArtifact(ctx context.Context, jobID primitive.ObjectID, name string, content []byte) (models.Artifact, error) {
+	store, err := conn.GetStorage()
+	if err != nil {
+		return models.Artifact{}, err
+	}
+
+	key := storage.Join("artifacts", jobID.Hex(), name)
+	if err := store.Put(ctx, key, bytes.NewReader(content)); err != nil {
+		return models.Artifact{}, err
+	}
+
+	contentType := mime.TypeByExtension(path.Ext(name))
+	if contentType == "" {
+		contentType = http.DetectContentType(content)
+	}
+
+	return models.Artifact{
+		Name:        name,
+		Size:        int64(len(content)),
+		ContentType: contentType,
+		Key:         key,
+	}, nil
+}
+
+// OpenArtifact returns the content of a stored artifact.
+func OpenArtifact(ctx context.Context, artifact models.Artifact) (io.ReadCloser, error) {
+	store, err := conn.GetStorage()
+	if err != nil {
+		return nil, err
+	}
+	return store.Open(ctx, artifact.Key)
+}
@@ -0,0 +1,49 @@
+package services
+
+import (
+	"fmt"
+
+	"github.com/anurag-327/neuron/config"
+	"github.com/anurag-327/neuron/internal/dto"
+	"github.com/anurag-327/neuron/internal/models"
+	"github.com/anurag-327/neuron/internal/registry"
+	"github.com/anurag-327/neuron/pkg/checker"
+)
+
+// ResolveChecker validates a checker and resolves the runtime of a
+// custom checker, whose language defaults to defaultLanguage. Custom
+// checker code is checked under config.CheckerAnalysisPolicy with file
+// access, since checkers read the output and answer files.
+func ResolveChecker(c dto.CheckerBody, defaultLanguage string) (*models.Checker, error) {
+	resolved := models.Checker{
+		Type:    models.CheckerType(c.Type),
+		Epsilon: c.Epsilon,
+		Code:    c.Code,
+	}
+	if err := checker.Validate(resolved); err != nil {
+		return nil, err
+	}
+	if resolved.Type != models.CheckerCustom {
+		resolved.Code = ""
+		return &resolved, nil
+	}
+
+	resolved.Language = c.Language
+	if resolved.Language == "" {
+		resolved.Language = defaultLanguage
+	}
+	langCfg, ok := registry.LanguageRegistry[resolved.Language]
+	if !ok {
+		return nil, fmt.Errorf("checker language %q not supported", resolved.Language)
+	}
+	versionCfg, err := langCfg.ResolveVersion(c.Version)
+	if err != nil {
+		return nil, fmt.Errorf("checker: %w", err)
+	}
+	resolved.Version = versionCfg.Name
+
+	if err := langCfg.Validate(resolved.Code, "", config.CheckerAnalysisPolicy, true); err != nil {
+		return nil, fmt.Errorf("checker: %w", err)
+	}
+	return &resolved, nil
+}
@@ -0,0 +1,209 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/anurag-327/neuron/config"
+	"github.com/anurag-327/neuron/internal/dto"
+	"github.com/anurag-327/neuron/internal/models"
+	"github.com/anurag-327/neuron/internal/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	ErrInvalidContest      = errors.New("invalid contest")
+	ErrNotContestAuthor    = errors.New("only the author can change this contest")
+	ErrContestNotRunning   = errors.New("contest is not running")
+	ErrContestEnded        = errors.New("contest has ended")
+	ErrNotContestant       = errors.New("not registered for this contest")
+	ErrProblemNotInContest = errors.New("problem is not part of this contest")
+)
+
+// CreateContest validates body and stores it as a new contest of user.
+func CreateContest(ctx context.Context, user *models.User, body dto.ContestBody) (*models.Contest, error) {
+	contest := &models.Contest{AuthorID: user.ID}
+	if err := applyContestBody(ctx, contest, body); err != nil {
+		return nil, err
+	}
+	return repository.SaveContest(ctx, contest)
+}
+
+// UpdateContest replaces a contest of user with body.
+func UpdateContest(ctx context.Context, user *models.User, contestID primitive.ObjectID, body dto.ContestBody) (*models.Contest, error) {
+	contest, err := GetAuthoredContest(ctx, user, contestID)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyContestBody(ctx, contest, body); err != nil {
+		return nil, err
+	}
+	return repository.SaveContest(ctx, contest)
+}
+
+// UnfreezeContest reveals the full scoreboard of a contest of user.
+func UnfreezeContest(ctx context.Context, user *models.User, contestID primitive.ObjectID) (*models.Contest, error) {
+	contest, err := GetAuthoredContest(ctx, user, contestID)
+	if err != nil {
+		return nil, err
+	}
+	contest.Unfrozen = true
+	return repository.SaveContest(ctx, contest)
+}
+
+// DeleteContest deletes a contest of user. Its jobs keep their results.
+func DeleteContest(ctx context.Context, user *models.User, contestID primitive.ObjectID) error {
+	contest, err := GetAuthoredContest(ctx, user, contestID)
+	if err != nil {
+		return err
+	}
+	return repository.DeleteContest(ctx, contest)
+}
+
+// GetAuthoredContest returns a contest if user is its author.
+func GetAuthoredContest(ctx context.Context, user *models.User, contestID primitive.ObjectID) (*models.Contest, error) {
+	contest, err := repository.GetContestByID(ctx, contestID)
+	if err != nil {
+		return nil, err
+	}
+	if contest.AuthorID != user.ID {
+		return nil, ErrNotContestAuthor
+	}
+	return contest, nil
+}
+
+// RegisterForContest registers user for a contest that has not ended.
+func RegisterForContest(ctx context.Context, user *models.User, contestID primitive.ObjectID) (*models.ContestParticipant, error) {
+	contest, err := repository.GetContestByID(ctx, contestID)
+	if err != nil {
+		return nil, err
+	}
+	if !time.Now().Before(contest.EndAt) {
+		return nil, ErrContestEnded
+	}
+	return repository.RegisterContestParticipant(ctx, contest.ID, user.ID)
+}
+
+// GetSubmissionContest returns the contest a submission is made to and
+// checks that user may submit the problem to it now.
+func GetSubmissionContest(ctx context.Context, user *models.User, body dto.SubmitCodeBody) (*models.Contest, error) {
+	contestID, err := primitive.ObjectIDFromHex(body.ContestID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid contestId", ErrInvalidContest)
+	}
+	problemID, err := primitive.ObjectIDFromHex(body.ProblemID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: contest submissions need a problemId", ErrInvalidContest)
+	}
+
+	contest, err := repository.GetContestByID(ctx, contestID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if now.Before(contest.StartAt) || !now.Before(contest.EndAt) {
+		return nil, ErrContestNotRunning
+	}
+	if _, ok := contest.Problem(problemID); !ok {
+		return nil, ErrProblemNotInContest
+	}
+
+	registered, err := repository.IsContestParticipant(ctx, contest.ID, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !registered {
+		return nil, ErrNotContestant
+	}
+	return contest, nil
+}
+
+func applyContestBody(ctx context.Context, contest *models.Contest, body dto.ContestBody) error {
+	invalid := func(format string, args ...any) error {
+		return fmt.Errorf("%w: %s", ErrInvalidContest, fmt.Sprintf(format, args...))
+	}
+
+	title := strings.TrimSpace(body.Title)
+	if title == "" || len(title) > config.ProblemMaxTitleLength {
+		return invalid("title must be 1 to %d characters", config.ProblemMaxTitleLength)
+	}
+	if len(body.Description) > config.ProblemMaxStatementSize {
+		return invalid("description is larger than %d bytes", config.ProblemMaxStatementSize)
+	}
+
+	mode := models.ScoringMode(body.Mode)
+	if mode != models.ScoringICPC && mode != models.ScoringIOI {
+		return invalid("mode must be %q or %q", models.ScoringICPC, models.ScoringIOI)
+	}
+	if !body.EndAt.After(body.StartAt) {
+		return invalid("endAt must be after startAt")
+	}
+	var freezeAt time.Time
+	if body.FreezeAt != nil {
+		freezeAt = *body.FreezeAt
+		if !freezeAt.After(body.StartAt) || !freezeAt.Before(body.EndAt) {
+			return invalid("freezeAt must be between startAt and endAt")
+		}
+	}
+	penalty := config.ContestPenaltyMinutes
+	if body.PenaltyMinutes != nil {
+		penalty = *body.PenaltyMinutes
+		if penalty < 0 {
+			return invalid("penaltyMinutes must not be negative")
+		}
+	}
+
+	if len(body.Problems) == 0 || len(body.Problems) > config.ContestMaxProblems {
+		return invalid("a contest needs 1 to %d problems", config.ContestMaxProblems)
+	}
+	problems := make([]models.ContestProblem, 0, len(body.Problems))
+	labels := map[string]bool{}
+	ids := map[primitive.ObjectID]bool{}
+	for i, p := range body.Problems {
+		label := strings.TrimSpace(p.Label)
+		if label == "" {
+			label = string(rune('A' + i))
+		}
+		if len(label) > 10 || labels[label] {
+			return invalid("problem labels must be unique and at most 10 characters")
+		}
+		labels[label] = true
+
+		problemID, err := primitive.ObjectIDFromHex(p.ProblemID)
+		if err != nil {
+			return invalid("invalid problemId %q", p.ProblemID)
+		}
+		if ids[problemID] {
+			return invalid("problem %s is listed twice", p.ProblemID)
+		}
+		ids[problemID] = true
+		if _, err := repository.GetProblemByID(ctx, problemID); err != nil {
+			if errors.Is(err, repository.ErrProblemNotFound) {
+				return invalid("problem %s not found", p.ProblemID)
+			}
+			return err
+		}
+
+		points := p.Points
+		if points == 0 {
+			points = config.ContestProblemPoints
+		}
+		if points < 0 {
+			return invalid("points must be positive")
+		}
+		problems = append(problems, models.ContestProblem{Label: label, ProblemID: problemID, Points: points})
+	}
+
+	contest.Title = title
+	contest.Description = body.Description
+	contest.Mode = mode
+	contest.StartAt = body.StartAt
+	contest.EndAt = body.EndAt
+	contest.FreezeAt = freezeAt
+	contest.PenaltyMinutes = penalty
+	contest.Problems = problems
+	return nil
+}
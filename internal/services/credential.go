@@ -9,11 +9,13 @@ import (
 	"fmt"
 
 	"github.com/anurag-327/neuron/internal/models"
+	"github.com/anurag-327/neuron/internal/registry"
 	"github.com/anurag-327/neuron/internal/repository"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 var ErrCredentialAlreadyExists = errors.New("active credential already exists for this user")
+var ErrInvalidLibrarySet = errors.New("invalid library set")
 
 // GenerateAPIKey generates a random API key string
 // Format: nr_live_<32_char_hex>
@@ -111,6 +113,9 @@ func RegenerateCredential(ctx context.Context, userID primitive.ObjectID) (*mode
 		return nil, err
 	}
 
+	// Library defaults carry over to the new key
+	libraries := existing.Libraries
+
 	// Delete the old credential
 	if err := repository.DeleteCredential(ctx, existing); err != nil {
 		return nil, fmt.Errorf("failed to delete old credential: %w", err)
@@ -126,10 +131,11 @@ func RegenerateCredential(ctx context.Context, userID primitive.ObjectID) (*mode
 
 	// Create new credential
 	cred := &models.Credential{
-		UserID:   userID,
-		Key:      hashedKey,
-		Env:      "live",
-		IsActive: true,
+		UserID:    userID,
+		Key:       hashedKey,
+		Env:       "live",
+		IsActive:  true,
+		Libraries: libraries,
 	}
 
 	savedCred, err := repository.CreateCredential(ctx, cred)
@@ -141,3 +147,30 @@ func RegenerateCredential(ctx context.Context, userID primitive.ObjectID) (*mode
 	savedCred.Key = plainKey
 	return savedCred, nil
 }
+
+// SetCredentialLibraries replaces the default library sets of the user's
+// credential. Each set must exist, and at most one may be given per language.
+func SetCredentialLibraries(ctx context.Context, userID primitive.ObjectID, libraries []string) (*models.Credential, error) {
+	seen := make(map[string]string, len(libraries))
+	for _, name := range libraries {
+		lang, ok := registry.FindLibrarySet(name)
+		if !ok {
+			return nil, fmt.Errorf("%w: %q does not exist", ErrInvalidLibrarySet, name)
+		}
+		if other, exists := seen[lang]; exists {
+			return nil, fmt.Errorf("%w: %q and %q are both %s library sets", ErrInvalidLibrarySet, other, name, lang)
+		}
+		seen[lang] = name
+	}
+
+	cred, err := repository.GetCredentialByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	cred.Libraries = libraries
+	if err := repository.UpdateCredential(ctx, cred); err != nil {
+		return nil, fmt.Errorf("failed to update credential: %w", err)
+	}
+	return cred, nil
+}
@@ -5,14 +5,17 @@ import (
 
 	"github.com/anurag-327/neuron/config"
 	"github.com/anurag-327/neuron/internal/models"
+	"github.com/anurag-327/neuron/internal/registry"
 	"github.com/anurag-327/neuron/internal/repository"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// AssertCanSubmit checks the user can pay for a submission in langCfg.
 func AssertCanSubmit(
 	ctx context.Context,
 	userID primitive.ObjectID,
+	langCfg registry.LanguageConfig,
 ) error {
-	amount := config.GetCreditsForReason(models.CreditReasonSubmission)
+	amount := config.GetJobCredits(models.CreditReasonSubmission, langCfg)
 	return repository.HasSufficientCredits(ctx, userID, amount)
 }
@@ -0,0 +1,75 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/anurag-327/neuron/config"
+	"github.com/anurag-327/neuron/internal/models"
+	"github.com/anurag-327/neuron/internal/repository"
+)
+
+var (
+	ErrInvalidIdempotencyKey    = errors.New("invalid idempotency key")
+	ErrIdempotencyKeyReused     = errors.New("idempotency key was already used with a different request body")
+	ErrIdempotencyKeyInProgress = errors.New("a request with this idempotency key is still in progress")
+)
+
+// ReserveIdempotencyKey claims key for a request of user with body.
+// When key is new it returns the reservation, to be completed with the
+// queued job or released when the request fails. When an earlier
+// request with the same key and body queued a job, it returns that job.
+func ReserveIdempotencyKey(ctx context.Context, user *models.User, key string, body any) (*models.IdempotencyKey, *models.Job, error) {
+	if len(key) > config.IdempotencyKeyMaxLength {
+		return nil, nil, fmt.Errorf("%w: longer than %d characters", ErrInvalidIdempotencyKey, config.IdempotencyKeyMaxLength)
+	}
+	raw, err := json.Marshal(body)
+	if err != nil {
+		return nil, nil, err
+	}
+	sum := sha256.Sum256(raw)
+	hash := hex.EncodeToString(sum[:])
+
+	reservation, err := repository.CreateIdempotencyKey(ctx, &models.IdempotencyKey{
+		UserID:      user.ID,
+		Key:         key,
+		RequestHash: hash,
+		ExpiresAt:   time.Now().Add(config.IdempotencyPendingTTL),
+	})
+	if err == nil {
+		return reservation, nil, nil
+	}
+	if !errors.Is(err, repository.ErrIdempotencyKeyExists) {
+		return nil, nil, err
+	}
+
+	existing := reservation
+	if existing != nil && existing.RequestHash != hash {
+		return nil, nil, ErrIdempotencyKeyReused
+	}
+	if existing == nil || existing.JobID == nil {
+		return nil, nil, ErrIdempotencyKeyInProgress
+	}
+	job, err := repository.GetJobByID(ctx, existing.JobID.Hex())
+	if err != nil {
+		return nil, nil, err
+	}
+	return nil, job, nil
+}
+
+// CompleteIdempotencyKey records job as the result of a reservation and
+// keeps it for config.IdempotencyKeyTTL.
+func CompleteIdempotencyKey(ctx context.Context, reservation *models.IdempotencyKey, job *models.Job) error {
+	return repository.CompleteIdempotencyKey(ctx, reservation, job.ID, time.Now().Add(config.IdempotencyKeyTTL))
+}
+
+// ReleaseIdempotencyKey frees a reservation whose request was rejected,
+// so the request can be retried with the same key.
+func ReleaseIdempotencyKey(ctx context.Context, reservation *models.IdempotencyKey) error {
+	return repository.DeleteIdempotencyKey(ctx, reservation)
+}
@@ -0,0 +1,93 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/anurag-327/neuron/internal/dto"
+	"github.com/anurag-327/neuron/internal/models"
+	"github.com/anurag-327/neuron/internal/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var ErrInvalidJobQuery = errors.New("invalid job query")
+
+// jobSearchMaxLength is the longest search text of a job query.
+const jobSearchMaxLength = 200
+
+// ListJobs returns a page of the jobs of user matching query, newest
+// first, and the cursor of the next page, which is empty on the last.
+func ListJobs(ctx context.Context, user *models.User, query dto.ListJobsQuery) ([]models.Job, string, error) {
+	invalid := func(format string, args ...any) error {
+		return fmt.Errorf("%w: %s", ErrInvalidJobQuery, fmt.Sprintf(format, args...))
+	}
+
+	filter := repository.JobFilter{
+		UserID:    user.ID,
+		Status:    models.RunStatus(query.Status),
+		Language:  query.Language,
+		ErrorType: models.SandboxError(query.ErrorType),
+		Limit:     query.Limit,
+	}
+	switch filter.Status {
+	case "", models.StatusQueued, models.StatusRunning, models.StatusSuccess, models.StatusFailed:
+	default:
+		return nil, "", invalid("unknown status %q", query.Status)
+	}
+	switch filter.ErrorType {
+	case "", models.ErrTLE, models.ErrMLE, models.ErrCompilationError, models.ErrRuntimeError,
+		models.ErrSandboxError, models.ErrInternalError, models.ErrWrongAnswer,
+		models.ErrPresentationError, models.ErrInteractorFailed:
+	default:
+		return nil, "", invalid("unknown errorType %q", query.ErrorType)
+	}
+
+	var err error
+	if query.From != "" {
+		if filter.From, err = time.Parse(time.RFC3339, query.From); err != nil {
+			return nil, "", invalid("from must be an RFC 3339 time")
+		}
+	}
+	if query.To != "" {
+		if filter.To, err = time.Parse(time.RFC3339, query.To); err != nil {
+			return nil, "", invalid("to must be an RFC 3339 time")
+		}
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && filter.To.Before(filter.From) {
+		return nil, "", invalid("to is before from")
+	}
+	if query.Cursor != "" {
+		if filter.Before, err = primitive.ObjectIDFromHex(query.Cursor); err != nil {
+			return nil, "", invalid("invalid cursor")
+		}
+	}
+
+	// Quotes would end the phrase the search is run as
+	filter.Search = strings.TrimSpace(strings.ReplaceAll(query.Q, `"`, " "))
+	if len(filter.Search) > jobSearchMaxLength {
+		return nil, "", invalid("q is longer than %d characters", jobSearchMaxLength)
+	}
+
+	if filter.Limit <= 0 {
+		filter.Limit = 20
+	}
+	if filter.Limit > 100 {
+		filter.Limit = 100
+	}
+
+	// One job more than the page tells whether there is a next page
+	limit := filter.Limit
+	filter.Limit++
+	jobs, err := repository.ListJobs(ctx, filter)
+	if err != nil {
+		return nil, "", err
+	}
+	if int64(len(jobs)) <= limit {
+		return jobs, "", nil
+	}
+	jobs = jobs[:limit]
+	return jobs, jobs[len(jobs)-1].ID.Hex(), nil
+}
@@ -0,0 +1,64 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/anurag-327/neuron/config"
+	"github.com/anurag-327/neuron/conn"
+	"github.com/anurag-327/neuron/internal/models"
+	"github.com/anurag-327/neuron/pkg/storage"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// SaveOutput returns the part of an output of a job kept in the job
+// document. Output larger than config.InlineOutputSize is stored whole
+// in the object store, and only its beginning is kept inline.
+func SaveOutput(ctx context.Context, jobID primitive.ObjectID, stream, output string) (string, *models.OutputBlob, error) {
+	if len(output) <= config.InlineOutputSize {
+		return output, nil, nil
+	}
+
+	store, err := conn.GetStorage()
+	if err != nil {
+		return "", nil, err
+	}
+	key := storage.Join("outputs", jobID.Hex(), stream)
+	if err := store.Put(ctx, key, strings.NewReader(output)); err != nil {
+		return "", nil, err
+	}
+
+	sum := sha256.Sum256([]byte(output))
+	blob := &models.OutputBlob{
+		Key:    key,
+		Size:   int64(len(output)),
+		SHA256: hex.EncodeToString(sum[:]),
+	}
+	return OutputPreview(output), blob, nil
+}
+
+// OutputPreview returns the first config.InlineOutputSize bytes of
+// output, without splitting a UTF-8 character.
+func OutputPreview(output string) string {
+	if len(output) <= config.InlineOutputSize {
+		return output
+	}
+	n := config.InlineOutputSize
+	for n > 0 && !utf8.RuneStart(output[n]) {
+		n--
+	}
+	return output[:n]
+}
+
+// OpenOutput returns the content of a stored output.
+func OpenOutput(ctx context.Context, blob *models.OutputBlob) (io.ReadCloser, error) {
+	store, err := conn.GetStorage()
+	if err != nil {
+		return nil, err
+	}
+	return store.Open(ctx, blob.Key)
+}
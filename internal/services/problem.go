@@ -0,0 +1,229 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/anurag-327/neuron/config"
+	"github.com/anurag-327/neuron/internal/dto"
+	"github.com/anurag-327/neuron/internal/models"
+	"github.com/anurag-327/neuron/internal/registry"
+	"github.com/anurag-327/neuron/internal/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	ErrInvalidProblem   = errors.New("invalid problem")
+	ErrNotProblemAuthor = errors.New("only the author can change this problem")
+)
+
+// CreateProblem validates body and stores it as a new problem of user.
+func CreateProblem(ctx context.Context, user *models.User, body dto.ProblemBody) (*models.Problem, error) {
+	problem := &models.Problem{AuthorID: user.ID}
+	if err := applyProblemBody(problem, body); err != nil {
+		return nil, err
+	}
+	return repository.SaveProblem(ctx, problem)
+}
+
+// UpdateProblem replaces a problem of user with body.
+func UpdateProblem(ctx context.Context, user *models.User, problemID primitive.ObjectID, body dto.ProblemBody) (*models.Problem, error) {
+	problem, err := GetAuthoredProblem(ctx, user, problemID)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyProblemBody(problem, body); err != nil {
+		return nil, err
+	}
+	return repository.SaveProblem(ctx, problem)
+}
+
+// DeleteProblem deletes a problem of user. Jobs that ran against it
+// keep their results.
+func DeleteProblem(ctx context.Context, user *models.User, problemID primitive.ObjectID) error {
+	problem, err := GetAuthoredProblem(ctx, user, problemID)
+	if err != nil {
+		return err
+	}
+	return repository.DeleteProblem(ctx, problem)
+}
+
+// GetVisibleProblem returns a problem unless it is hidden from user: a
+// problem of a contest that has not started is only shown to its author
+// and reported as not found to everyone else.
+func GetVisibleProblem(ctx context.Context, user *models.User, problemID primitive.ObjectID) (*models.Problem, error) {
+	problem, err := repository.GetProblemByID(ctx, problemID)
+	if err != nil {
+		return nil, err
+	}
+	if problem.AuthorID == user.ID {
+		return problem, nil
+	}
+	hidden, err := repository.GetUnstartedContestProblemIDs(ctx, time.Now(), problem.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(hidden) > 0 {
+		return nil, repository.ErrProblemNotFound
+	}
+	return problem, nil
+}
+
+// ListProblems lists the problems visible to user (see
+// GetVisibleProblem); with mine only the user's own.
+func ListProblems(ctx context.Context, user *models.User, mine bool, page, limit int64) ([]models.Problem, error) {
+	if mine {
+		return repository.ListProblems(ctx, user.ID, user.ID, nil, page, limit)
+	}
+	hidden, err := repository.GetUnstartedContestProblemIDs(ctx, time.Now(), primitive.NilObjectID)
+	if err != nil {
+		return nil, err
+	}
+	return repository.ListProblems(ctx, primitive.NilObjectID, user.ID, hidden, page, limit)
+}
+
+// GetAuthoredProblem returns a problem if user is its author.
+func GetAuthoredProblem(ctx context.Context, user *models.User, problemID primitive.ObjectID) (*models.Problem, error) {
+	problem, err := repository.GetProblemByID(ctx, problemID)
+	if err != nil {
+		return nil, err
+	}
+	if problem.AuthorID != user.ID {
+		return nil, ErrNotProblemAuthor
+	}
+	return problem, nil
+}
+
+func applyProblemBody(problem *models.Problem, body dto.ProblemBody) error {
+	invalid := func(format string, args ...any) error {
+		return fmt.Errorf("%w: %s", ErrInvalidProblem, fmt.Sprintf(format, args...))
+	}
+
+	title := strings.TrimSpace(body.Title)
+	if title == "" || len(title) > config.ProblemMaxTitleLength {
+		return invalid("title must be 1 to %d characters", config.ProblemMaxTitleLength)
+	}
+	if len(body.Statement) > config.ProblemMaxStatementSize {
+		return invalid("statement is larger than %d bytes", config.ProblemMaxStatementSize)
+	}
+	if body.TimeLimitMs < 0 || body.TimeLimitMs > config.ProblemMaxTimeLimitMs {
+		return invalid("timeLimitMs must be between 0 and %d", config.ProblemMaxTimeLimitMs)
+	}
+	for _, lang := range body.Languages {
+		if _, ok := registry.LanguageRegistry[lang]; !ok {
+			return invalid("language %q not supported", lang)
+		}
+	}
+
+	count := len(body.Samples) + len(body.Tests)
+	if count == 0 || count > config.ProblemMaxTests {
+		return invalid("a problem needs 1 to %d tests, samples included", config.ProblemMaxTests)
+	}
+	size := 0
+	for _, t := range append(append([]dto.TestCaseBody{}, body.Samples...), body.Tests...) {
+		size += len(t.Input) + len(t.ExpectedOutput)
+	}
+	if size > config.ProblemMaxTestDataSize {
+		return invalid("tests are larger than %d bytes in total", config.ProblemMaxTestDataSize)
+	}
+
+	var checker *models.Checker
+	if body.Checker != nil {
+		// Custom checkers must name their language; problems have none
+		c, err := ResolveChecker(*body.Checker, "")
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidProblem, err)
+		}
+		checker = c
+	}
+
+	languages := body.Languages
+	var interactor *models.Interactor
+	if body.Interactor != nil {
+		if checker != nil {
+			return invalid("a checker cannot be combined with an interactor")
+		}
+		i, err := resolveInteractor(*body.Interactor)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidProblem, err)
+		}
+		// The interactor runs in the submission's runtime
+		if len(languages) == 0 {
+			languages = []string{i.Language}
+		}
+		if len(languages) != 1 || languages[0] != i.Language {
+			return invalid("an interactive problem only accepts the interactor's language")
+		}
+		interactor = i
+	}
+
+	problem.Title = title
+	problem.Statement = body.Statement
+	problem.TimeLimitMs = body.TimeLimitMs
+	problem.Languages = languages
+	problem.Samples = testCases(body.Samples)
+	problem.Tests = testCases(body.Tests)
+	problem.Checker = checker
+	problem.Interactor = interactor
+	return nil
+}
+
+// resolveInteractor checks the interactor of a problem. It is validated
+// like a custom checker, since it reads the test from a file, and its
+// language must run interactors by default.
+func resolveInteractor(body dto.InteractorBody) (*models.Interactor, error) {
+	langCfg, ok := registry.LanguageRegistry[body.Language]
+	if !ok {
+		return nil, fmt.Errorf("interactor language %q not supported", body.Language)
+	}
+	runtime, err := langCfg.ResolveRuntime(langCfg.DefaultVersion, "")
+	if err != nil {
+		return nil, fmt.Errorf("interactor: %w", err)
+	}
+	if runtime.Sandbox.Backend != registry.BackendDocker {
+		return nil, fmt.Errorf("interactors are not supported for %s", body.Language)
+	}
+	if err := langCfg.Validate(body.Code, "", config.CheckerAnalysisPolicy, true); err != nil {
+		return nil, fmt.Errorf("interactor: %w", err)
+	}
+	return &models.Interactor{Language: body.Language, Code: body.Code}, nil
+}
+
+func testCases(body []dto.TestCaseBody) []models.TestCase {
+	tests := make([]models.TestCase, 0, len(body))
+	for _, t := range body {
+		tests = append(tests, models.TestCase{Input: t.Input, ExpectedOutput: t.ExpectedOutput})
+	}
+	return tests
+}
+
+// GetSubmissionProblem returns the problem a submission of user
+// references and checks that the submission can run against it.
+// Outside contests only visible problems can be submitted to; contest
+// submissions are checked against their contest instead.
+func GetSubmissionProblem(ctx context.Context, user *models.User, body dto.SubmitCodeBody) (*models.Problem, error) {
+	if body.Input != "" || body.ExpectedOutput != "" || body.Checker != nil || len(body.Artifacts) > 0 {
+		return nil, errors.New("problem submissions cannot set input, expectedOutput, checker or artifacts")
+	}
+	problemID, err := primitive.ObjectIDFromHex(body.ProblemID)
+	if err != nil {
+		return nil, errors.New("invalid problemId")
+	}
+	var problem *models.Problem
+	if body.ContestID != "" {
+		problem, err = repository.GetProblemByID(ctx, problemID)
+	} else {
+		problem, err = GetVisibleProblem(ctx, user, problemID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(problem.Languages) > 0 && !slices.Contains(problem.Languages, body.Language) {
+		return nil, fmt.Errorf("language %q is not allowed for this problem", body.Language)
+	}
+	return problem, nil
+}
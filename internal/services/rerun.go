@@ -0,0 +1,101 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/anurag-327/neuron/config"
+	"github.com/anurag-327/neuron/internal/dto"
+	"github.com/anurag-327/neuron/internal/models"
+	"github.com/anurag-327/neuron/internal/registry"
+	"github.com/anurag-327/neuron/internal/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	ErrInvalidRerun = errors.New("invalid rerun")
+	ErrJobArchived  = errors.New("job was archived and its code removed, it cannot be rerun")
+)
+
+// AssertCanRerun checks the user can pay for a rerun.
+func AssertCanRerun(ctx context.Context, userID primitive.ObjectID) error {
+	amount := config.GetCreditsForReason(models.CreditReasonRerun)
+	return repository.HasSufficientCredits(ctx, userID, amount)
+}
+
+// CreateRerun stores a queued copy of parent, a job of user, with the
+// overrides of body. The copy is linked to parent and to the first job
+// of its rerun chain, and is charged as a rerun. Reruns of contest jobs
+// are not contest submissions.
+//
+// The code is analysed again like a submission, under the user's
+// current plan and the current rules of its language and library set,
+// so a rerun cannot run code that would no longer be accepted. Archived
+// jobs have lost their code and input and are rejected with
+// ErrJobArchived.
+func CreateRerun(ctx context.Context, user *models.User, parent *models.Job, body dto.RerunBody) (*models.Job, error) {
+	if parent.ArchivedAt != nil {
+		return nil, ErrJobArchived
+	}
+	langCfg, ok := registry.LanguageRegistry[parent.Language]
+	if !ok {
+		return nil, fmt.Errorf("%w: language not supported", ErrInvalidRerun)
+	}
+	policy := config.GetAnalysisPolicy(user.GetPlan())
+	fileAccess := len(parent.Files) > 0 || len(parent.ArtifactGlobs) > 0
+	if err := langCfg.Validate(parent.Code, parent.Library, policy, fileAccess); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidRerun, err)
+	}
+
+	input := parent.Input
+	if body.Input != nil {
+		if parent.ProblemID != nil {
+			return nil, fmt.Errorf("%w: problem jobs run against the problem's tests and take no input", ErrInvalidRerun)
+		}
+		input = *body.Input
+	}
+	timeLimitMs := parent.TimeLimitMs
+	if body.TimeLimitMs != 0 {
+		if body.TimeLimitMs < 0 || body.TimeLimitMs > config.ProblemMaxTimeLimitMs {
+			return nil, fmt.Errorf("%w: timeLimitMs must be 1 to %d", ErrInvalidRerun, config.ProblemMaxTimeLimitMs)
+		}
+		timeLimitMs = body.TimeLimitMs
+	}
+
+	root := parent.ID
+	if parent.RootJobID != nil {
+		root = *parent.RootJobID
+	}
+
+	job := &models.Job{
+		Language: parent.Language,
+		Version:  parent.Version,
+		Library:  parent.Library,
+		Code:     parent.Code,
+		Input:    input,
+		Status:   models.StatusQueued,
+		QueuedAt: time.Now(),
+		UserID:   user.ID,
+
+		ArtifactGlobs: parent.ArtifactGlobs,
+
+		Args:  parent.Args,
+		Env:   parent.Env,
+		Files: parent.Files,
+
+		ExpectedOutput: parent.ExpectedOutput,
+		Checker:        parent.Checker,
+
+		ProblemID:   parent.ProblemID,
+		RunAllTests: parent.RunAllTests,
+
+		Fingerprints: parent.Fingerprints,
+
+		ParentJobID: &parent.ID,
+		RootJobID:   &root,
+		TimeLimitMs: timeLimitMs,
+	}
+	return repository.SaveJob(ctx, job)
+}
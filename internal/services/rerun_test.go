@@ -0,0 +1,35 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/anurag-327/neuron/internal/dto"
+	"github.com/anurag-327/neuron/internal/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestCreateRerunRejectsArchivedJobs(t *testing.T) {
+	archivedAt := time.Now()
+	user := &models.User{}
+	user.ID = primitive.NewObjectID()
+
+	// a job stripped by data retention: no code, input or files left
+	parent := &models.Job{
+		Language:   "python",
+		UserID:     user.ID,
+		Status:     models.StatusSuccess,
+		ArchivedAt: &archivedAt,
+	}
+	parent.ID = primitive.NewObjectID()
+
+	job, err := CreateRerun(context.Background(), user, parent, dto.RerunBody{})
+	if !errors.Is(err, ErrJobArchived) {
+		t.Fatalf("err = %v, want ErrJobArchived", err)
+	}
+	if job != nil {
+		t.Errorf("queued %+v for an archived job", job)
+	}
+}
@@ -0,0 +1,148 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/anurag-327/neuron/config"
+	"github.com/anurag-327/neuron/internal/dto"
+	"github.com/anurag-327/neuron/internal/models"
+	"github.com/anurag-327/neuron/internal/registry"
+	"github.com/anurag-327/neuron/internal/repository"
+)
+
+var ErrNotCacheable = errors.New("the result cache cannot be used with problemId, contestId or artifacts")
+
+// ResultCacheKey returns the result cache key of a submission on
+// runtime: a SHA-256 over the runtime, including its image, sandbox and
+// commands so that changing them invalidates earlier results, and over
+// everything the program sees or is judged by.
+func ResultCacheKey(
+	body dto.SubmitCodeBody,
+	runtime registry.VersionConfig,
+	library string,
+	files []models.DataFile,
+	checker *models.Checker,
+) (string, error) {
+	if body.ProblemID != "" || body.ContestID != "" || len(body.Artifacts) > 0 {
+		return "", ErrNotCacheable
+	}
+
+	// encoding/json sorts map keys, so equal submissions encode equally
+	b, err := json.Marshal(struct {
+		Language   string
+		Version    string
+		Library    string
+		Image      string
+		Sandbox    registry.SandboxSettings
+		CompileCmd string
+		RunCmd     string
+		TimeLimit  time.Duration
+
+		Code           string
+		Input          string
+		Args           []string
+		Env            map[string]string
+		Files          []models.DataFile
+		ExpectedOutput string
+		Checker        *models.Checker
+	}{
+		Language:   body.Language,
+		Version:    runtime.Name,
+		Library:    library,
+		Image:      runtime.DockerImage,
+		Sandbox:    runtime.Sandbox,
+		CompileCmd: runtime.CompileCmd,
+		RunCmd:     runtime.RunCmd,
+		TimeLimit:  runtime.GetTimeLimit(),
+
+		Code:           body.Code,
+		Input:          body.Input,
+		Args:           body.Args,
+		Env:            body.Env,
+		Files:          files,
+		ExpectedOutput: body.ExpectedOutput,
+		Checker:        checker,
+	})
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// ServeFromCache completes a queued job with the cached result of its
+// CacheKey, if there is one, and charges CreditReasonCachedResult. It
+// reports whether the job was served.
+func ServeFromCache(ctx context.Context, job *models.Job) (bool, error) {
+	cached, err := repository.GetCachedResult(ctx, job.CacheKey)
+	if errors.Is(err, repository.ErrCachedResultNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	now := time.Now()
+	job.Status = models.StatusSuccess
+	job.StartedAt = now
+	job.FinishedAt = now
+	job.Stdout = cached.Stdout
+	job.Stderr = cached.Stderr
+	job.ExitCode = cached.ExitCode
+	job.Diagnostics = cached.Diagnostics
+	job.Verdict = cached.Verdict
+	job.VerdictMessage = cached.VerdictMessage
+	job.FromCache = true
+
+	if _, err := repository.SaveJob(ctx, job); err != nil {
+		return false, fmt.Errorf("failed to save cached job: %w", err)
+	}
+
+	amount := config.GetCreditsForReason(models.CreditReasonCachedResult)
+	if amount > 0 {
+		err := DeductCreditsAndLog(
+			ctx,
+			job.UserID,
+			amount,
+			models.CreditReasonCachedResult,
+			&job.ID,
+			map[string]interface{}{
+				"language":    job.Language,
+				"version":     job.Version,
+				"library":     job.Library,
+				"cachedJobId": cached.JobID,
+			},
+		)
+		if err != nil {
+			log.Printf("credit deduction failed for cached job %s: %v", job.ID.Hex(), err)
+		}
+	}
+	return true, nil
+}
+
+// StoreCachedResult caches the result of a successful run of a job that
+// opted into the result cache.
+func StoreCachedResult(ctx context.Context, job *models.Job, runtime registry.VersionConfig) error {
+	return repository.SaveCachedResult(ctx, &models.CachedResult{
+		Key:            job.CacheKey,
+		Language:       job.Language,
+		Version:        job.Version,
+		Library:        job.Library,
+		Image:          runtime.DockerImage,
+		JobID:          job.ID,
+		Stdout:         job.Stdout,
+		Stderr:         job.Stderr,
+		ExitCode:       job.ExitCode,
+		Diagnostics:    job.Diagnostics,
+		Verdict:        job.Verdict,
+		VerdictMessage: job.VerdictMessage,
+		ExpiresAt:      time.Now().Add(config.ResultCacheTTL),
+	})
+}
@@ -0,0 +1,294 @@
+package services
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/anurag-327/neuron/config"
+	"github.com/anurag-327/neuron/conn"
+	"github.com/anurag-327/neuron/internal/models"
+	"github.com/anurag-327/neuron/internal/repository"
+	"github.com/anurag-327/neuron/pkg/storage"
+	"github.com/kamva/mgm/v3"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// retainedCollection is a collection data retention applies to.
+type retainedCollection struct {
+	model mgm.Model
+
+	// large are the fields removed after Retention.StripAfter
+	large []string
+
+	// stored is set when documents reference outputs and artifacts in
+	// the object store: outputs are deleted when documents are stripped,
+	// artifacts when they are deleted
+	stored bool
+
+	// exempt returns a filter of the documents that are kept when
+	// stripping or deleting; nil exempts none
+	exempt func(deleting bool) bson.M
+}
+
+var retainedCollections = []retainedCollection{
+	{
+		model: &models.Job{},
+		// Stripped jobs keep their outcome (status, error type, verdict
+		// and test results) for history and scoreboards, and their
+		// fingerprints for similarity checks
+		large: []string{
+			"code", "input", "stdout", "stderr", "stdoutBlob", "stderrBlob", "diagnostics",
+			"args", "env", "files", "expectedOutput",
+		},
+		stored: true,
+		exempt: exemptJobs,
+	},
+	{
+		model: &models.ApiLog{},
+		large: []string{"requestBody"},
+	},
+}
+
+// exemptJobs keeps the latest run of each snippet whole, since share
+// links show it, and never deletes problem and contest jobs, which
+// scoreboards, similarity checks and problem history are built from.
+// Latest runs are marked on the job (see repository.SetSnippetLastJob).
+func exemptJobs(deleting bool) bson.M {
+	exempt := bson.A{bson.M{"latestSnippetRun": true}}
+	if deleting {
+		exempt = append(exempt,
+			bson.M{"problemId": bson.M{"$exists": true}},
+			bson.M{"contestId": bson.M{"$exists": true}},
+		)
+	}
+	return bson.M{"$or": exempt}
+}
+
+// StartRetention runs RunRetention every config.RetentionInterval until
+// ctx is done. Only one process should run it.
+func StartRetention(ctx context.Context, archive storage.Store) {
+	ticker := time.NewTicker(config.RetentionInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := RunRetention(ctx, archive); err != nil && ctx.Err() == nil {
+			log.Printf("data retention failed: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunRetention applies the retention policy of each plan to jobs and
+// API logs once. Documents are written to archive as gzipped NDJSON
+// (MongoDB Extended JSON, one document per line) before they are
+// stripped or deleted; with a nil archive they are not archived.
+func RunRetention(ctx context.Context, archive storage.Store) error {
+	// Users on paid plans are few: everyone else is on the free plan
+	owners := map[models.PlanType]bson.M{}
+	var paid []primitive.ObjectID
+	for plan := range config.RetentionPolicy {
+		if plan == models.PlanTypeFree {
+			continue
+		}
+		ids, err := repository.GetUserIDsByPlan(ctx, plan)
+		if err != nil {
+			return err
+		}
+		owners[plan] = bson.M{"$in": ids}
+		paid = append(paid, ids...)
+	}
+	owners[models.PlanTypeFree] = bson.M{"$nin": paid}
+
+	now := time.Now()
+	for _, coll := range retainedCollections {
+		for plan, users := range owners {
+			retention := config.GetRetention(plan)
+			if retention.StripAfter > 0 {
+				if err := stripRetained(ctx, archive, coll, users, now.Add(-retention.StripAfter)); err != nil {
+					return err
+				}
+			}
+			if retention.DeleteAfter > 0 {
+				if err := deleteRetained(ctx, archive, coll, users, now.Add(-retention.DeleteAfter)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// stripRetained archives the documents of users created before cutoff
+// and removes their large fields.
+func stripRetained(ctx context.Context, archive storage.Store, coll retainedCollection, users bson.M, cutoff time.Time) error {
+	var exempt bson.M
+	if coll.exempt != nil {
+		exempt = coll.exempt(false)
+	}
+	for {
+		docs, err := repository.GetRetainedDocuments(ctx, coll.model, users, exempt, cutoff, true, config.RetentionBatchSize)
+		if err != nil || len(docs) == 0 {
+			return err
+		}
+		if err := archiveDocuments(ctx, archive, coll.model, docs); err != nil {
+			return err
+		}
+		if coll.stored {
+			if err := deleteStoredObjects(ctx, outputKeys(docs)); err != nil {
+				return err
+			}
+		}
+		if err := repository.StripDocuments(ctx, coll.model, documentIDs(docs), coll.large, time.Now()); err != nil {
+			return err
+		}
+		if len(docs) < config.RetentionBatchSize {
+			return nil
+		}
+	}
+}
+
+// deleteRetained deletes the documents of users created before cutoff,
+// archiving those not archived yet.
+func deleteRetained(ctx context.Context, archive storage.Store, coll retainedCollection, users bson.M, cutoff time.Time) error {
+	var exempt bson.M
+	if coll.exempt != nil {
+		exempt = coll.exempt(true)
+	}
+	for {
+		docs, err := repository.GetRetainedDocuments(ctx, coll.model, users, exempt, cutoff, false, config.RetentionBatchSize)
+		if err != nil || len(docs) == 0 {
+			return err
+		}
+
+		var unarchived []bson.Raw
+		for _, doc := range docs {
+			if _, err := doc.LookupErr("archivedAt"); err != nil {
+				unarchived = append(unarchived, doc)
+			}
+		}
+		if err := archiveDocuments(ctx, archive, coll.model, unarchived); err != nil {
+			return err
+		}
+		if coll.stored {
+			keys := append(outputKeys(docs), artifactKeys(docs)...)
+			if err := deleteStoredObjects(ctx, keys); err != nil {
+				return err
+			}
+		}
+		if err := repository.DeleteDocuments(ctx, coll.model, documentIDs(docs)); err != nil {
+			return err
+		}
+		if len(docs) < config.RetentionBatchSize {
+			return nil
+		}
+	}
+}
+
+// archiveDocuments writes docs to one archive file, named after the
+// collection, the day and the IDs of the first and last document, so
+// archiving a batch again replaces its file.
+func archiveDocuments(ctx context.Context, archive storage.Store, model mgm.Model, docs []bson.Raw) error {
+	if archive == nil || len(docs) == 0 {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	for _, doc := range docs {
+		line, err := bson.MarshalExtJSON(doc, true, false)
+		if err != nil {
+			return fmt.Errorf("failed to encode document: %w", err)
+		}
+		zw.Write(line)
+		zw.Write([]byte{'\n'})
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+
+	ids := documentIDs(docs)
+	key := storage.Join(
+		"archive",
+		mgm.CollName(model),
+		time.Now().UTC().Format("2006/01/02"),
+		ids[0].Hex()+"-"+ids[len(ids)-1].Hex()+".ndjson.gz",
+	)
+	if err := archive.Put(ctx, key, &buf); err != nil {
+		return fmt.Errorf("failed to archive %s: %w", key, err)
+	}
+	return nil
+}
+
+// outputKeys returns the object keys of the stored outputs of jobs.
+func outputKeys(docs []bson.Raw) []string {
+	var keys []string
+	for _, doc := range docs {
+		for _, field := range []string{"stdoutBlob", "stderrBlob"} {
+			if key, ok := doc.Lookup(field, "key").StringValueOK(); ok {
+				keys = append(keys, key)
+			}
+		}
+	}
+	return keys
+}
+
+// artifactKeys returns the object keys of the artifacts of jobs.
+func artifactKeys(docs []bson.Raw) []string {
+	var keys []string
+	for _, doc := range docs {
+		artifacts, ok := doc.Lookup("artifacts").ArrayOK()
+		if !ok {
+			continue
+		}
+		values, err := artifacts.Values()
+		if err != nil {
+			continue
+		}
+		for _, v := range values {
+			artifact, ok := v.DocumentOK()
+			if !ok {
+				continue
+			}
+			if key, ok := artifact.Lookup("key").StringValueOK(); ok {
+				keys = append(keys, key)
+			}
+		}
+	}
+	return keys
+}
+
+// deleteStoredObjects deletes objects of the object store, ignoring
+// those already gone.
+func deleteStoredObjects(ctx context.Context, keys []string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	store, err := conn.GetStorage()
+	if err != nil {
+		return err
+	}
+	for _, key := range keys {
+		if err := store.Delete(ctx, key); err != nil && !errors.Is(err, storage.ErrNotFound) {
+			return fmt.Errorf("failed to delete stored object %s: %w", key, err)
+		}
+	}
+	return nil
+}
+
+func documentIDs(docs []bson.Raw) []primitive.ObjectID {
+	ids := make([]primitive.ObjectID, len(docs))
+	for i, doc := range docs {
+		ids[i] = doc.Lookup("_id").ObjectID()
+	}
+	return ids
+}
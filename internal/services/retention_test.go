@@ -0,0 +1,59 @@
+package services
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// bsonFields returns the top-level BSON field names of a model struct.
+func bsonFields(t reflect.Type) map[string]bool {
+	fields := map[string]bool{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, opts, _ := strings.Cut(f.Tag.Get("bson"), ",")
+		if strings.Contains(opts, "inline") {
+			for n := range bsonFields(f.Type) {
+				fields[n] = true
+			}
+			continue
+		}
+		if name != "" && name != "-" {
+			fields[name] = true
+		}
+	}
+	return fields
+}
+
+func TestRetainedFieldsExist(t *testing.T) {
+	for _, coll := range retainedCollections {
+		model := reflect.TypeOf(coll.model).Elem()
+		fields := bsonFields(model)
+		for _, f := range coll.large {
+			if !fields[f] {
+				t.Errorf("%s has no field %q to strip", model.Name(), f)
+			}
+		}
+	}
+}
+
+func TestExemptJobs(t *testing.T) {
+	tests := []struct {
+		deleting bool
+		want     bson.M
+	}{
+		{false, bson.M{"$or": bson.A{bson.M{"latestSnippetRun": true}}}},
+		{true, bson.M{"$or": bson.A{
+			bson.M{"latestSnippetRun": true},
+			bson.M{"problemId": bson.M{"$exists": true}},
+			bson.M{"contestId": bson.M{"$exists": true}},
+		}}},
+	}
+	for _, tt := range tests {
+		if got := exemptJobs(tt.deleting); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("exemptJobs(%v) = %v, want %v", tt.deleting, got, tt.want)
+		}
+	}
+}
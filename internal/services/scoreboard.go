@@ -0,0 +1,222 @@
+package services
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/anurag-327/neuron/internal/models"
+	"github.com/anurag-327/neuron/internal/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Scoreboard is the standings of a contest.
+type Scoreboard struct {
+	ContestID primitive.ObjectID      `json:"contestId"`
+	Mode      models.ScoringMode      `json:"mode"`
+	Frozen    bool                    `json:"frozen"`
+	FreezeAt  *time.Time              `json:"freezeAt,omitempty"`
+	Problems  []models.ContestProblem `json:"problems"`
+	Rows      []ScoreboardRow         `json:"rows"`
+}
+
+// ScoreboardRow is the standing of one participant. Score is the number
+// of solved problems in ICPC mode and points in IOI mode; Penalty is in
+// minutes.
+type ScoreboardRow struct {
+	Rank     int                      `json:"rank"`
+	UserID   primitive.ObjectID       `json:"userId"`
+	Username string                   `json:"username"`
+	Score    int64                    `json:"score"`
+	Penalty  int64                    `json:"penalty"`
+	Problems map[string]*ProblemScore `json:"problems"`
+}
+
+// ProblemScore is the result of a participant on one problem. Attempts
+// counts judged submissions (rejected ones before the first accepted
+// one in ICPC mode); Pending counts submissions not judged yet or hidden
+// by the freeze. Minutes is when the problem was solved (ICPC) or its
+// best score reached (IOI).
+type ProblemScore struct {
+	Solved   bool  `json:"solved"`
+	Score    int64 `json:"score"`
+	Attempts int   `json:"attempts"`
+	Pending  int   `json:"pending"`
+	Minutes  int64 `json:"minutes"`
+}
+
+// GetScoreboard computes the standings of a contest from its jobs.
+// The author always sees the full standings; everyone else sees the
+// frozen ones while the contest is frozen.
+func GetScoreboard(ctx context.Context, user *models.User, contestID primitive.ObjectID) (*Scoreboard, error) {
+	contest, err := repository.GetContestByID(ctx, contestID)
+	if err != nil {
+		return nil, err
+	}
+	participants, err := repository.GetContestParticipants(ctx, contest.ID)
+	if err != nil {
+		return nil, err
+	}
+	jobs, err := repository.GetContestJobs(ctx, contest.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]primitive.ObjectID, 0, len(participants))
+	for _, p := range participants {
+		ids = append(ids, p.UserID)
+	}
+	users, err := repository.GetUsersByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	// IOI scores are shares of the tests of each problem
+	tests := map[primitive.ObjectID]int{}
+	if contest.Mode == models.ScoringIOI {
+		for _, p := range contest.Problems {
+			problem, err := repository.GetProblemByID(ctx, p.ProblemID)
+			if err != nil {
+				continue
+			}
+			tests[p.ProblemID] = len(problem.Samples) + len(problem.Tests)
+		}
+	}
+
+	frozen := contest.IsFrozen(time.Now()) && contest.AuthorID != user.ID
+	return BuildScoreboard(contest, participants, users, jobs, tests, frozen), nil
+}
+
+// BuildScoreboard ranks the participants of a contest from its jobs in
+// submission order. tests holds the number of tests of each problem
+// for IOI scoring. Compilation errors and failed runs are not counted.
+func BuildScoreboard(
+	contest *models.Contest,
+	participants []models.ContestParticipant,
+	users map[primitive.ObjectID]models.User,
+	jobs []models.Job,
+	tests map[primitive.ObjectID]int,
+	frozen bool,
+) *Scoreboard {
+	board := &Scoreboard{
+		ContestID: contest.ID,
+		Mode:      contest.Mode,
+		Frozen:    frozen,
+		Problems:  contest.Problems,
+		Rows:      []ScoreboardRow{},
+	}
+	if !contest.FreezeAt.IsZero() {
+		freezeAt := contest.FreezeAt
+		board.FreezeAt = &freezeAt
+	}
+
+	rows := map[primitive.ObjectID]*ScoreboardRow{}
+	for _, p := range participants {
+		row := &ScoreboardRow{
+			UserID:   p.UserID,
+			Username: users[p.UserID].Username,
+			Problems: map[string]*ProblemScore{},
+		}
+		for _, cp := range contest.Problems {
+			row.Problems[cp.Label] = &ProblemScore{}
+		}
+		rows[p.UserID] = row
+	}
+
+	for _, job := range jobs {
+		row, ok := rows[job.UserID]
+		if !ok || job.ProblemID == nil {
+			continue
+		}
+		cp, ok := contest.Problem(*job.ProblemID)
+		if !ok || job.QueuedAt.Before(contest.StartAt) || !job.QueuedAt.Before(contest.EndAt) {
+			continue
+		}
+		ps := row.Problems[cp.Label]
+
+		if frozen && !job.QueuedAt.Before(contest.FreezeAt) {
+			ps.Pending++
+			continue
+		}
+		if job.Status == models.StatusQueued || job.Status == models.StatusRunning {
+			ps.Pending++
+			continue
+		}
+		if job.SandboxErrorType != nil {
+			switch *job.SandboxErrorType {
+			case models.ErrCompilationError, models.ErrSandboxError, models.ErrInternalError:
+				continue
+			}
+		}
+
+		minutes := int64(job.QueuedAt.Sub(contest.StartAt) / time.Minute)
+		switch contest.Mode {
+		case models.ScoringICPC:
+			if ps.Solved {
+				continue
+			}
+			if job.Verdict == models.VerdictAccepted {
+				ps.Solved = true
+				ps.Score = 1
+				ps.Minutes = minutes
+			} else {
+				ps.Attempts++
+			}
+
+		case models.ScoringIOI:
+			ps.Attempts++
+			total := tests[cp.ProblemID]
+			if total == 0 {
+				continue
+			}
+			passed := 0
+			for _, tr := range job.TestResults {
+				if tr.Verdict == models.VerdictAccepted {
+					passed++
+				}
+			}
+			score := cp.Points * int64(passed) / int64(total)
+			if score > ps.Score {
+				ps.Score = score
+				ps.Minutes = minutes
+				ps.Solved = passed == total
+			}
+		}
+	}
+
+	for _, row := range rows {
+		for _, ps := range row.Problems {
+			switch contest.Mode {
+			case models.ScoringICPC:
+				if ps.Solved {
+					row.Score++
+					row.Penalty += ps.Minutes + int64(ps.Attempts)*contest.PenaltyMinutes
+				}
+			case models.ScoringIOI:
+				row.Score += ps.Score
+				if ps.Score > 0 && ps.Minutes > row.Penalty {
+					row.Penalty = ps.Minutes
+				}
+			}
+		}
+		board.Rows = append(board.Rows, *row)
+	}
+
+	sort.Slice(board.Rows, func(i, j int) bool {
+		a, b := board.Rows[i], board.Rows[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if a.Penalty != b.Penalty {
+			return a.Penalty < b.Penalty
+		}
+		return a.Username < b.Username
+	})
+	for i := range board.Rows {
+		board.Rows[i].Rank = i + 1
+		if i > 0 && board.Rows[i].Score == board.Rows[i-1].Score && board.Rows[i].Penalty == board.Rows[i-1].Penalty {
+			board.Rows[i].Rank = board.Rows[i-1].Rank
+		}
+	}
+	return board
+}
@@ -0,0 +1,234 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/anurag-327/neuron/internal/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestBuildScoreboard(t *testing.T) {
+	start := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	problemA, problemB := primitive.NewObjectID(), primitive.NewObjectID()
+	alice, bob, carol := primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID()
+
+	users := map[primitive.ObjectID]models.User{
+		alice: {Username: "alice"},
+		bob:   {Username: "bob"},
+		carol: {Username: "carol"},
+	}
+	participants := []models.ContestParticipant{{UserID: alice}, {UserID: bob}, {UserID: carol}}
+
+	contest := func(mode models.ScoringMode, freeze bool) *models.Contest {
+		c := &models.Contest{
+			Mode:           mode,
+			StartAt:        start,
+			EndAt:          start.Add(2 * time.Hour),
+			PenaltyMinutes: 20,
+			Problems: []models.ContestProblem{
+				{Label: "A", ProblemID: problemA, Points: 100},
+				{Label: "B", ProblemID: problemB, Points: 100},
+			},
+		}
+		if freeze {
+			c.FreezeAt = start.Add(time.Hour)
+		}
+		return c
+	}
+
+	compileError := models.ErrCompilationError
+	wrongAnswer := models.ErrWrongAnswer
+	// job is a judged submission at minute of the contest
+	job := func(user, problem primitive.ObjectID, minute int, verdict models.Verdict) models.Job {
+		return models.Job{
+			UserID:    user,
+			ProblemID: &problem,
+			Status:    models.StatusSuccess,
+			Verdict:   verdict,
+			QueuedAt:  start.Add(time.Duration(minute) * time.Minute),
+		}
+	}
+	// tests gives j results for total tests, the first passed of them accepted
+	tests := func(j models.Job, passed, total int) models.Job {
+		for i := range total {
+			tr := models.TestResult{Test: i + 1, Verdict: models.VerdictWrongAnswer}
+			if i < passed {
+				tr.Verdict = models.VerdictAccepted
+			}
+			j.TestResults = append(j.TestResults, tr)
+		}
+		return j
+	}
+	withError := func(j models.Job, errType *models.SandboxError) models.Job {
+		j.Verdict = ""
+		j.SandboxErrorType = errType
+		return j
+	}
+	withStatus := func(j models.Job, status models.RunStatus) models.Job {
+		j.Status = status
+		return j
+	}
+
+	type want struct {
+		user    primitive.ObjectID
+		rank    int
+		score   int64
+		penalty int64
+	}
+	cases := []struct {
+		name    string
+		contest *models.Contest
+		jobs    []models.Job
+		tests   map[primitive.ObjectID]int
+		frozen  bool
+		want    []want
+		check   func(t *testing.T, board *Scoreboard)
+	}{
+		{
+			name:    "icpc solved problems then penalty",
+			contest: contest(models.ScoringICPC, false),
+			jobs: []models.Job{
+				job(alice, problemA, 10, models.VerdictWrongAnswer),
+				job(alice, problemA, 15, models.VerdictAccepted),
+				job(bob, problemA, 30, models.VerdictAccepted),
+				job(bob, problemB, 40, models.VerdictAccepted),
+				job(carol, problemB, 5, models.VerdictWrongAnswer),
+			},
+			want: []want{
+				{bob, 1, 2, 70},
+				{alice, 2, 1, 35},
+				{carol, 3, 0, 0},
+			},
+		},
+		{
+			name:    "icpc compilation errors and submissions after solving are free",
+			contest: contest(models.ScoringICPC, false),
+			jobs: []models.Job{
+				withError(job(alice, problemA, 1, ""), &compileError),
+				job(alice, problemA, 12, models.VerdictAccepted),
+				job(alice, problemA, 20, models.VerdictWrongAnswer),
+				withError(job(bob, problemA, 2, ""), &wrongAnswer),
+				job(bob, problemA, 11, models.VerdictAccepted),
+			},
+			want: []want{
+				{alice, 1, 1, 12},
+				{bob, 2, 1, 31},
+				{carol, 3, 0, 0},
+			},
+		},
+		{
+			name:    "icpc ties share a rank",
+			contest: contest(models.ScoringICPC, false),
+			jobs: []models.Job{
+				job(alice, problemA, 25, models.VerdictAccepted),
+				job(bob, problemB, 25, models.VerdictAccepted),
+			},
+			want: []want{
+				{alice, 1, 1, 25},
+				{bob, 1, 1, 25},
+				{carol, 3, 0, 0},
+			},
+		},
+		{
+			name:    "submissions outside the contest are ignored",
+			contest: contest(models.ScoringICPC, false),
+			jobs: []models.Job{
+				job(alice, problemA, -5, models.VerdictAccepted),
+				job(bob, problemA, 120, models.VerdictAccepted),
+				job(carol, primitive.NewObjectID(), 10, models.VerdictAccepted),
+			},
+			want: []want{
+				{alice, 1, 0, 0},
+				{bob, 1, 0, 0},
+				{carol, 1, 0, 0},
+			},
+		},
+		{
+			name:    "frozen submissions are pending",
+			contest: contest(models.ScoringICPC, true),
+			frozen:  true,
+			jobs: []models.Job{
+				job(alice, problemA, 30, models.VerdictAccepted),
+				job(bob, problemA, 70, models.VerdictAccepted),
+				withStatus(job(carol, problemA, 20, ""), models.StatusQueued),
+			},
+			want: []want{
+				{alice, 1, 1, 30},
+				{bob, 2, 0, 0},
+				{carol, 2, 0, 0},
+			},
+			check: func(t *testing.T, board *Scoreboard) {
+				for _, row := range board.Rows {
+					if row.UserID != alice && row.Problems["A"].Pending != 1 {
+						t.Errorf("%s has %d pending on A, want 1", row.Username, row.Problems["A"].Pending)
+					}
+				}
+			},
+		},
+		{
+			name:    "unfrozen for the author",
+			contest: contest(models.ScoringICPC, true),
+			jobs: []models.Job{
+				job(alice, problemA, 30, models.VerdictAccepted),
+				job(bob, problemA, 70, models.VerdictAccepted),
+			},
+			want: []want{
+				{alice, 1, 1, 30},
+				{bob, 2, 1, 70},
+				{carol, 3, 0, 0},
+			},
+		},
+		{
+			name:    "ioi best score per problem",
+			contest: contest(models.ScoringIOI, false),
+			tests:   map[primitive.ObjectID]int{problemA: 4, problemB: 5},
+			jobs: []models.Job{
+				tests(job(alice, problemA, 10, models.VerdictWrongAnswer), 2, 4),
+				tests(job(alice, problemA, 20, models.VerdictWrongAnswer), 1, 4),
+				tests(job(alice, problemB, 30, models.VerdictAccepted), 5, 5),
+				tests(job(bob, problemA, 5, models.VerdictAccepted), 4, 4),
+				tests(job(bob, problemB, 50, models.VerdictWrongAnswer), 2, 5),
+			},
+			want: []want{
+				{alice, 1, 150, 30},
+				{bob, 2, 140, 50},
+				{carol, 3, 0, 0},
+			},
+		},
+		{
+			name:    "ioi ties go to the earlier final score",
+			contest: contest(models.ScoringIOI, false),
+			tests:   map[primitive.ObjectID]int{problemA: 2, problemB: 2},
+			jobs: []models.Job{
+				tests(job(alice, problemA, 40, models.VerdictAccepted), 2, 2),
+				tests(job(bob, problemB, 15, models.VerdictAccepted), 2, 2),
+			},
+			want: []want{
+				{bob, 1, 100, 15},
+				{alice, 2, 100, 40},
+				{carol, 3, 0, 0},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			board := BuildScoreboard(tc.contest, participants, users, tc.jobs, tc.tests, tc.frozen)
+			if len(board.Rows) != len(tc.want) {
+				t.Fatalf("got %d rows, want %d", len(board.Rows), len(tc.want))
+			}
+			for i, w := range tc.want {
+				row := board.Rows[i]
+				if row.UserID != w.user || row.Rank != w.rank || row.Score != w.score || row.Penalty != w.penalty {
+					t.Errorf("row %d = %s rank %d score %d penalty %d, want %s rank %d score %d penalty %d",
+						i, row.Username, row.Rank, row.Score, row.Penalty,
+						users[w.user].Username, w.rank, w.score, w.penalty)
+				}
+			}
+			if tc.check != nil {
+				tc.check(t, board)
+			}
+		})
+	}
+}
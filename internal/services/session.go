@@ -0,0 +1,152 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"time"
+
+	"github.com/anurag-327/neuron/config"
+	"github.com/anurag-327/neuron/internal/dto"
+	"github.com/anurag-327/neuron/internal/models"
+	"github.com/anurag-327/neuron/internal/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// AssertCanStartSession checks the user can pay for at least one
+// session minute.
+func AssertCanStartSession(ctx context.Context, userID primitive.ObjectID) error {
+	amount := config.GetCreditsForReason(models.CreditReasonSession)
+	return repository.HasSufficientCredits(ctx, userID, amount)
+}
+
+// CreateSession stores a pending session and returns it with the
+// one-time token the client joins it with. Only the token hash is kept.
+func CreateSession(
+	ctx context.Context,
+	user *models.User,
+	body dto.CreateSessionBody,
+	version string,
+	library string,
+	files []models.DataFile,
+) (*models.Session, string, error) {
+
+	raw := make([]byte, 24)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, "", err
+	}
+	token := hex.EncodeToString(raw)
+
+	session := &models.Session{
+		UserID:    user.ID,
+		Language:  body.Language,
+		Version:   version,
+		Library:   library,
+		Code:      body.Code,
+		Args:      body.Args,
+		Env:       body.Env,
+		Files:     files,
+		Status:    models.SessionPending,
+		TokenHash: hashSessionToken(token),
+		ExpiresAt: time.Now().Add(config.SessionJoinWindow),
+	}
+
+	session, err := repository.SaveSession(ctx, session)
+	if err != nil {
+		return nil, "", err
+	}
+	return session, token, nil
+}
+
+// JoinSession activates a pending session with its token.
+func JoinSession(ctx context.Context, sessionID primitive.ObjectID, token string) (*models.Session, error) {
+	return repository.ActivateSession(ctx, sessionID, hashSessionToken(token))
+}
+
+// ReserveSession deducts the credits of a session's whole run up front
+// and returns how long it may run: config.SessionWallLimit, or less
+// when the balance pays for fewer minutes. The amount is recorded in
+// session.CreditsReserved; SettleSession refunds what is not used.
+// repository.ErrInsufficientCredits is returned when not even one
+// minute is affordable.
+func ReserveSession(ctx context.Context, session *models.Session) (time.Duration, error) {
+	price := config.GetCreditsForReason(models.CreditReasonSession)
+	if price <= 0 {
+		return config.SessionWallLimit, nil
+	}
+	user, err := repository.GetUserByID(ctx, session.UserID)
+	if err != nil {
+		return 0, err
+	}
+
+	minutes := min(int64(config.SessionWallLimit/time.Minute), user.Credits/price)
+	if minutes < 1 {
+		return 0, repository.ErrInsufficientCredits
+	}
+	amount := minutes * price
+
+	err = DeductCreditsAndLog(
+		ctx,
+		session.UserID,
+		amount,
+		models.CreditReasonSession,
+		&session.ID,
+		map[string]interface{}{
+			"language": session.Language,
+			"version":  session.Version,
+			"library":  session.Library,
+			"reserved": true,
+		},
+	)
+	if err != nil {
+		return 0, err
+	}
+	session.CreditsReserved = amount
+	return time.Duration(minutes) * time.Minute, nil
+}
+
+// SettleSession bills a finished session per started minute of its run
+// (nothing when it did not run), capped by its reservation, and refunds
+// the rest of the reservation. The amount billed is recorded on it.
+func SettleSession(ctx context.Context, session *models.Session, ran bool) {
+	if session.CreditsReserved <= 0 {
+		return
+	}
+	price := config.GetCreditsForReason(models.CreditReasonSession)
+	duration := time.Duration(session.DurationMs) * time.Millisecond
+
+	var amount int64
+	if ran && duration > 0 {
+		minutes := int64((duration + time.Minute - 1) / time.Minute)
+		amount = min(minutes*price, session.CreditsReserved)
+	}
+	session.CreditsCharged = amount
+
+	refund := session.CreditsReserved - amount
+	if refund <= 0 {
+		return
+	}
+	err := CreditUserAndLog(
+		ctx,
+		session.UserID,
+		refund,
+		models.CreditReasonRefund,
+		&session.ID,
+		map[string]interface{}{
+			"reserved":    session.CreditsReserved,
+			"charged":     amount,
+			"durationMs":  session.DurationMs,
+			"closeReason": session.CloseReason,
+		},
+	)
+	if err != nil {
+		log.Printf("credit refund failed for session %s: %v", session.ID.Hex(), err)
+	}
+}
+
+func hashSessionToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,241 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/anurag-327/neuron/config"
+	"github.com/anurag-327/neuron/internal/dto"
+	"github.com/anurag-327/neuron/internal/models"
+	"github.com/anurag-327/neuron/internal/registry"
+	"github.com/anurag-327/neuron/internal/repository"
+	"github.com/anurag-327/neuron/pkg/similarity"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	ErrInvalidSimilarity = errors.New("invalid similarity request")
+	ErrJobNotComparable  = errors.New("only your own jobs and submissions to your problems or contests can be compared")
+)
+
+// SimilarJob is one side of a SimilarPair. Share is the share of its
+// code found in the other job.
+type SimilarJob struct {
+	JobID    primitive.ObjectID `json:"jobId"`
+	UserID   primitive.ObjectID `json:"userId"`
+	Username string             `json:"username"`
+	Share    float64            `json:"share"`
+}
+
+// SimilarPair is two jobs of different users with code in common.
+// Similarity is the larger of the two shares, so copying a small
+// solution into a larger one still ranks high; Matches are the regions
+// of code found in both.
+type SimilarPair struct {
+	A          SimilarJob         `json:"a"`
+	B          SimilarJob         `json:"b"`
+	Language   string             `json:"language"`
+	Similarity float64            `json:"similarity"`
+	Matches    []similarity.Match `json:"matches"`
+}
+
+// FindSimilarPairs compares the jobs selected by body and returns the
+// pairs of different users whose code is at least body.MinShare alike,
+// most similar first. Only jobs in the same language are compared, and
+// code shared by most of the jobs is ignored as boilerplate.
+func FindSimilarPairs(ctx context.Context, user *models.User, body dto.SimilarityBody) ([]SimilarPair, error) {
+	invalid := func(format string, args ...any) error {
+		return fmt.Errorf("%w: %s", ErrInvalidSimilarity, fmt.Sprintf(format, args...))
+	}
+
+	minShare := config.SimilarityMinShare
+	if body.MinShare != nil {
+		minShare = *body.MinShare
+		if minShare < 0 || minShare > 1 {
+			return nil, invalid("minShare must be between 0 and 1")
+		}
+	}
+	limit := body.Limit
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	var jobs []models.Job
+	switch {
+	case body.ProblemID != "" && len(body.JobIDs) > 0:
+		return nil, invalid("set either jobIds or problemId")
+
+	case body.ProblemID != "":
+		problemID, err := primitive.ObjectIDFromHex(body.ProblemID)
+		if err != nil {
+			return nil, invalid("invalid problemId")
+		}
+		if _, err := GetAuthoredProblem(ctx, user, problemID); err != nil {
+			return nil, err
+		}
+		if jobs, err = repository.GetLatestProblemJobs(ctx, problemID, config.SimilarityMaxJobs); err != nil {
+			return nil, err
+		}
+
+	default:
+		if len(body.JobIDs) < 2 || len(body.JobIDs) > config.SimilarityMaxJobs {
+			return nil, invalid("jobIds must list 2 to %d jobs", config.SimilarityMaxJobs)
+		}
+		ids := make([]primitive.ObjectID, 0, len(body.JobIDs))
+		seen := map[primitive.ObjectID]bool{}
+		for _, id := range body.JobIDs {
+			objID, err := primitive.ObjectIDFromHex(id)
+			if err != nil {
+				return nil, invalid("invalid job ID %q", id)
+			}
+			if !seen[objID] {
+				seen[objID] = true
+				ids = append(ids, objID)
+			}
+		}
+		var err error
+		if jobs, err = repository.GetJobsForSimilarity(ctx, ids); err != nil {
+			return nil, err
+		}
+		if len(jobs) < len(ids) {
+			return nil, repository.ErrJobNotFound
+		}
+		if err := assertComparable(ctx, user, jobs); err != nil {
+			return nil, err
+		}
+	}
+
+	// Jobs created before fingerprints were stored get them now
+	sets := make([][]models.Fingerprint, len(jobs))
+	for i, job := range jobs {
+		sets[i] = job.Fingerprints
+		if sets[i] == nil {
+			sets[i] = codeFingerprints(job.Language, job.Code)
+		}
+	}
+	common := similarity.Common(sets, config.SimilarityCommonShare)
+
+	// Only pairs sharing a fingerprint are compared
+	index := map[int64][]int{}
+	for i, fps := range sets {
+		for _, fp := range fps {
+			list := index[fp.Hash]
+			if !common[fp.Hash] && (len(list) == 0 || list[len(list)-1] != i) {
+				index[fp.Hash] = append(list, i)
+			}
+		}
+	}
+	candidates := map[[2]int]bool{}
+	for _, list := range index {
+		for x := 0; x < len(list); x++ {
+			for y := x + 1; y < len(list); y++ {
+				a, b := jobs[list[x]], jobs[list[y]]
+				if a.UserID != b.UserID && a.Language == b.Language {
+					candidates[[2]int{list[x], list[y]}] = true
+				}
+			}
+		}
+	}
+
+	var pairs []SimilarPair
+	for c := range candidates {
+		a, b := jobs[c[0]], jobs[c[1]]
+		shareA, shareB, matches := similarity.Compare(sets[c[0]], sets[c[1]], common)
+		score := max(shareA, shareB)
+		if score == 0 || score < minShare {
+			continue
+		}
+		pairs = append(pairs, SimilarPair{
+			A:          SimilarJob{JobID: a.ID, UserID: a.UserID, Share: shareA},
+			B:          SimilarJob{JobID: b.ID, UserID: b.UserID, Share: shareB},
+			Language:   a.Language,
+			Similarity: score,
+			Matches:    matches,
+		})
+	}
+
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i].Similarity != pairs[j].Similarity {
+			return pairs[i].Similarity > pairs[j].Similarity
+		}
+		if pairs[i].A.JobID != pairs[j].A.JobID {
+			return pairs[i].A.JobID.Hex() < pairs[j].A.JobID.Hex()
+		}
+		return pairs[i].B.JobID.Hex() < pairs[j].B.JobID.Hex()
+	})
+	if len(pairs) > limit {
+		pairs = pairs[:limit]
+	}
+
+	ids := make([]primitive.ObjectID, 0, 2*len(pairs))
+	for _, p := range pairs {
+		ids = append(ids, p.A.UserID, p.B.UserID)
+	}
+	users, err := repository.GetUsersByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	for i := range pairs {
+		pairs[i].A.Username = users[pairs[i].A.UserID].Username
+		pairs[i].B.Username = users[pairs[i].B.UserID].Username
+	}
+	return pairs, nil
+}
+
+// assertComparable checks that user owns each job or authored the
+// problem or contest it was submitted to.
+func assertComparable(ctx context.Context, user *models.User, jobs []models.Job) error {
+	problems := map[primitive.ObjectID]bool{}
+	contests := map[primitive.ObjectID]bool{}
+
+	for _, job := range jobs {
+		if job.UserID == user.ID {
+			continue
+		}
+		if job.ContestID != nil {
+			authored, ok := contests[*job.ContestID]
+			if !ok {
+				contest, err := repository.GetContestByID(ctx, *job.ContestID)
+				if err != nil && !errors.Is(err, repository.ErrContestNotFound) {
+					return err
+				}
+				authored = err == nil && contest.AuthorID == user.ID
+				contests[*job.ContestID] = authored
+			}
+			if authored {
+				continue
+			}
+		}
+		if job.ProblemID != nil {
+			authored, ok := problems[*job.ProblemID]
+			if !ok {
+				problem, err := repository.GetProblemByID(ctx, *job.ProblemID)
+				if err != nil && !errors.Is(err, repository.ErrProblemNotFound) {
+					return err
+				}
+				authored = err == nil && problem.AuthorID == user.ID
+				problems[*job.ProblemID] = authored
+			}
+			if authored {
+				continue
+			}
+		}
+		return ErrJobNotComparable
+	}
+	return nil
+}
+
+// codeFingerprints returns the similarity fingerprints of code, lexed
+// with the syntax of language.
+func codeFingerprints(language, code string) []models.Fingerprint {
+	syntax := language
+	if cfg, ok := registry.LanguageRegistry[language]; ok {
+		syntax = cfg.Syntax
+	}
+	return similarity.Fingerprints(syntax, code)
+}
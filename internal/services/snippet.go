@@ -0,0 +1,257 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/anurag-327/neuron/config"
+	"github.com/anurag-327/neuron/internal/dto"
+	"github.com/anurag-327/neuron/internal/models"
+	"github.com/anurag-327/neuron/internal/registry"
+	"github.com/anurag-327/neuron/internal/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	ErrInvalidSnippet    = errors.New("invalid snippet")
+	ErrNotSnippetAuthor  = errors.New("only the author can change this snippet")
+	ErrShareLinkExpired  = errors.New("share link has expired")
+	ErrSnippetNotVisible = errors.New("snippet is not shared")
+)
+
+// CreateSnippet validates body and stores it as a new snippet of user,
+// at revision 1.
+func CreateSnippet(ctx context.Context, user *models.User, body dto.SnippetBody) (*models.Snippet, error) {
+	snippet := &models.Snippet{AuthorID: user.ID}
+	if err := applySnippetBody(snippet, body); err != nil {
+		return nil, err
+	}
+	return saveSnippetRevision(ctx, snippet)
+}
+
+// UpdateSnippet replaces a snippet of user with body, recording a new
+// revision when anything changed.
+func UpdateSnippet(ctx context.Context, user *models.User, snippetID primitive.ObjectID, body dto.SnippetBody) (*models.Snippet, error) {
+	snippet, err := GetAuthoredSnippet(ctx, user, snippetID)
+	if err != nil {
+		return nil, err
+	}
+	before := *snippet
+	if err := applySnippetBody(snippet, body); err != nil {
+		return nil, err
+	}
+	if snippet.Name == before.Name && snippet.Language == before.Language && snippet.Version == before.Version &&
+		snippet.Code == before.Code && snippet.Input == before.Input {
+		return snippet, nil
+	}
+	return saveSnippetRevision(ctx, snippet)
+}
+
+// DeleteSnippet deletes a snippet of user with its revisions.
+func DeleteSnippet(ctx context.Context, user *models.User, snippetID primitive.ObjectID) error {
+	snippet, err := GetAuthoredSnippet(ctx, user, snippetID)
+	if err != nil {
+		return err
+	}
+	return repository.DeleteSnippet(ctx, snippet)
+}
+
+// GetAuthoredSnippet returns a snippet if user is its author.
+func GetAuthoredSnippet(ctx context.Context, user *models.User, snippetID primitive.ObjectID) (*models.Snippet, error) {
+	snippet, err := repository.GetSnippetByID(ctx, snippetID)
+	if err != nil {
+		return nil, err
+	}
+	if snippet.AuthorID != user.ID {
+		return nil, ErrNotSnippetAuthor
+	}
+	return snippet, nil
+}
+
+// ForkSnippet copies a snippet into a new snippet of user. Anyone may
+// fork the latest revision of a shared snippet; older revisions can
+// only be forked by the author.
+func ForkSnippet(ctx context.Context, user *models.User, snippetID primitive.ObjectID, body dto.ForkSnippetBody) (*models.Snippet, error) {
+	source, err := repository.GetSnippetByID(ctx, snippetID)
+	if err != nil {
+		return nil, err
+	}
+	author := source.AuthorID == user.ID
+	if !author && !source.IsShared(time.Now()) {
+		return nil, ErrSnippetNotVisible
+	}
+
+	content := dto.SnippetBody{
+		Name:     source.Name,
+		Language: source.Language,
+		Version:  source.Version,
+		Code:     source.Code,
+		Input:    source.Input,
+	}
+	revision := source.Revision
+	if body.Revision != 0 && body.Revision != source.Revision {
+		if !author {
+			return nil, ErrNotSnippetAuthor
+		}
+		rev, err := repository.GetSnippetRevision(ctx, source.ID, body.Revision)
+		if err != nil {
+			return nil, err
+		}
+		content = dto.SnippetBody{
+			Name:     rev.Name,
+			Language: rev.Language,
+			Version:  rev.Version,
+			Code:     rev.Code,
+			Input:    rev.Input,
+		}
+		revision = rev.Revision
+	}
+	if body.Name != "" {
+		content.Name = body.Name
+	}
+
+	fork := &models.Snippet{
+		AuthorID:       user.ID,
+		ForkedFrom:     &source.ID,
+		ForkedRevision: revision,
+	}
+	if err := applySnippetBody(fork, content); err != nil {
+		return nil, err
+	}
+	return saveSnippetRevision(ctx, fork)
+}
+
+// ShareSnippet creates the public read-only link of a snippet of user,
+// replacing any previous one, and returns the token of the link. Only
+// the token hash is kept.
+func ShareSnippet(ctx context.Context, user *models.User, snippetID primitive.ObjectID, body dto.ShareSnippetBody) (*models.Snippet, string, error) {
+	snippet, err := GetAuthoredSnippet(ctx, user, snippetID)
+	if err != nil {
+		return nil, "", err
+	}
+	if body.ExpiresAt != nil && !body.ExpiresAt.After(time.Now()) {
+		return nil, "", fmt.Errorf("%w: expiresAt must be in the future", ErrInvalidSnippet)
+	}
+
+	raw := make([]byte, 24)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, "", err
+	}
+	token := hex.EncodeToString(raw)
+
+	if err := repository.UpdateSnippetShare(ctx, snippet, hashShareToken(token), body.ExpiresAt); err != nil {
+		return nil, "", err
+	}
+	return snippet, token, nil
+}
+
+// UnshareSnippet removes the share link of a snippet of user.
+func UnshareSnippet(ctx context.Context, user *models.User, snippetID primitive.ObjectID) (*models.Snippet, error) {
+	snippet, err := GetAuthoredSnippet(ctx, user, snippetID)
+	if err != nil {
+		return nil, err
+	}
+	if err := repository.UpdateSnippetShare(ctx, snippet, "", nil); err != nil {
+		return nil, err
+	}
+	return snippet, nil
+}
+
+// GetSharedSnippet returns the snippet shared under token with its
+// latest run, which is nil when it never ran.
+func GetSharedSnippet(ctx context.Context, token string) (*models.Snippet, *models.Job, error) {
+	snippet, err := repository.GetSnippetByShareTokenHash(ctx, hashShareToken(token))
+	if err != nil {
+		return nil, nil, err
+	}
+	if !snippet.IsShared(time.Now()) {
+		return nil, nil, ErrShareLinkExpired
+	}
+	if snippet.LastJobID == nil {
+		return snippet, nil, nil
+	}
+
+	job, err := repository.GetJobByID(ctx, snippet.LastJobID.Hex())
+	if errors.Is(err, repository.ErrJobNotFound) {
+		return snippet, nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return snippet, job, nil
+}
+
+// SnippetSubmission is the submission that runs a snippet.
+func SnippetSubmission(snippet *models.Snippet) dto.SubmitCodeBody {
+	return dto.SubmitCodeBody{
+		Code:     snippet.Code,
+		Language: snippet.Language,
+		Version:  snippet.Version,
+		Input:    snippet.Input,
+	}
+}
+
+// saveSnippetRevision stores snippet at its next revision and records
+// that revision.
+func saveSnippetRevision(ctx context.Context, snippet *models.Snippet) (*models.Snippet, error) {
+	snippet.Revision++
+	snippet, err := repository.SaveSnippet(ctx, snippet)
+	if err != nil {
+		return nil, err
+	}
+	_, err = repository.SaveSnippetRevision(ctx, &models.SnippetRevision{
+		SnippetID: snippet.ID,
+		Revision:  snippet.Revision,
+		Name:      snippet.Name,
+		Language:  snippet.Language,
+		Version:   snippet.Version,
+		Code:      snippet.Code,
+		Input:     snippet.Input,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return snippet, nil
+}
+
+func applySnippetBody(snippet *models.Snippet, body dto.SnippetBody) error {
+	invalid := func(format string, args ...any) error {
+		return fmt.Errorf("%w: %s", ErrInvalidSnippet, fmt.Sprintf(format, args...))
+	}
+
+	name := strings.TrimSpace(body.Name)
+	if name == "" || len(name) > config.SnippetMaxNameLength {
+		return invalid("name must be 1 to %d characters", config.SnippetMaxNameLength)
+	}
+	langCfg, ok := registry.LanguageRegistry[body.Language]
+	if !ok {
+		return invalid("language not supported")
+	}
+	if _, err := langCfg.ResolveVersion(body.Version); err != nil {
+		return invalid("%v", err)
+	}
+	if len(body.Code) > config.SnippetMaxCodeSize {
+		return invalid("code is larger than %d bytes", config.SnippetMaxCodeSize)
+	}
+	if len(body.Input) > config.SnippetMaxInputSize {
+		return invalid("input is larger than %d bytes", config.SnippetMaxInputSize)
+	}
+
+	snippet.Name = name
+	snippet.Language = body.Language
+	snippet.Version = body.Version
+	snippet.Code = body.Code
+	snippet.Input = body.Input
+	return nil
+}
+
+func hashShareToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -2,6 +2,8 @@ package services
 
 import (
 	"context"
+	"encoding/base64"
+	"fmt"
 	"time"
 
 	"github.com/anurag-327/neuron/internal/dto"
@@ -13,18 +15,69 @@ func CreateSubmission(
 	ctx context.Context,
 	user *models.User,
 	body dto.SubmitCodeBody,
+	version string,
+	library string,
+	files []models.DataFile,
+	checker *models.Checker,
+	problem *models.Problem,
+	contest *models.Contest,
+	cacheKey string,
 ) (*models.Job, error) {
 
 	now := time.Now()
 
 	job := &models.Job{
 		Language: body.Language,
+		Version:  version,
+		Library:  library,
 		Code:     body.Code,
 		Input:    body.Input,
 		Status:   models.StatusQueued,
 		QueuedAt: now,
 		UserID:   user.ID,
+
+		ArtifactGlobs: body.Artifacts,
+
+		Args:  body.Args,
+		Env:   body.Env,
+		Files: files,
+
+		ExpectedOutput: body.ExpectedOutput,
+		Checker:        checker,
+
+		Fingerprints: codeFingerprints(body.Language, body.Code),
+
+		CacheKey: cacheKey,
+	}
+	if problem != nil {
+		job.ProblemID = &problem.ID
+	}
+	if contest != nil {
+		job.ContestID = &contest.ID
+		job.RunAllTests = contest.Mode == models.ScoringIOI
 	}
 
 	return repository.SaveJob(ctx, job)
 }
+
+// DecodeDataFiles decodes the content of submitted data files.
+func DecodeDataFiles(files []dto.DataFile) ([]models.DataFile, error) {
+	decoded := make([]models.DataFile, 0, len(files))
+	for _, f := range files {
+		var content []byte
+		switch f.Encoding {
+		case "", "utf8":
+			content = []byte(f.Content)
+		case "base64":
+			b, err := base64.StdEncoding.DecodeString(f.Content)
+			if err != nil {
+				return nil, fmt.Errorf("data file %q is not valid base64", f.Name)
+			}
+			content = b
+		default:
+			return nil, fmt.Errorf("data file %q: unknown encoding %q", f.Name, f.Encoding)
+		}
+		decoded = append(decoded, models.DataFile{Name: f.Name, Content: content})
+	}
+	return decoded, nil
+}
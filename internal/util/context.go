@@ -20,6 +20,17 @@ func GetUserFromContext(c *gin.Context) (*models.User, error) {
 	return user, nil
 }
 
+// GetCredentialFromContext returns the API credential the request was
+// authenticated with, if any.
+func GetCredentialFromContext(c *gin.Context) (*models.Credential, bool) {
+	credValue, exists := c.Get("credential")
+	if !exists {
+		return nil, false
+	}
+	cred, ok := credValue.(*models.Credential)
+	return cred, ok
+}
+
 func GetPageFromQuery(c *gin.Context) int64 {
 	pageStr := c.Query("page")
 	if pageStr == "" {
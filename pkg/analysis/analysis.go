@@ -0,0 +1,281 @@
+// Package analysis statically inspects submitted code before it is queued.
+//
+// An Analyzer parses code into the imports, calls and names it references,
+// resolving aliases (e.g. `import os as o` makes `o.system` a reference to
+// os.system). Check matches those references against the rules of a
+// language under a Policy and reports Findings with their position.
+package analysis
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Policy selects which rules are enforced for a submission.
+type Policy string
+
+const (
+	// PolicyStrict enforces standard and strict rules.
+	PolicyStrict Policy = "strict"
+
+	// PolicyStandard enforces standard rules only.
+	PolicyStandard Policy = "standard"
+
+	// PolicyOff skips rule checks; the sandbox is the only barrier.
+	PolicyOff Policy = "off"
+)
+
+// Level is the policy level from which a rule is enforced.
+type Level string
+
+const (
+	// LevelStandard rules cover code that escapes or attacks the sandbox.
+	LevelStandard Level = "standard"
+
+	// LevelStrict rules cover code that is legitimate but abusive on
+	// shared runners, e.g. sleeping or spawning thread pools.
+	LevelStrict Level = "strict"
+
+	// LevelFiles rules cover reading and writing files. They are enforced
+	// like standard rules unless the submission declares files or
+	// artifacts, whose access the sandbox confines to the job directory.
+	LevelFiles Level = "files"
+)
+
+// Enforces reports whether rules of level l apply under the policy.
+func (p Policy) Enforces(l Level) bool {
+	switch p {
+	case PolicyStrict:
+		return true
+	case PolicyStandard:
+		return l == LevelStandard || l == LevelFiles
+	default:
+		return false
+	}
+}
+
+// ParsePolicy converts a policy name into a Policy.
+func ParsePolicy(s string) (Policy, error) {
+	switch p := Policy(s); p {
+	case PolicyStrict, PolicyStandard, PolicyOff:
+		return p, nil
+	}
+	return "", fmt.Errorf("unknown analysis policy %q", s)
+}
+
+// Kind is the kind of reference a rule matches.
+type Kind string
+
+const (
+	// KindImport matches imported modules, packages and headers.
+	KindImport Kind = "import"
+
+	// KindCall matches call targets.
+	KindCall Kind = "call"
+
+	// KindName matches any reference: imports, calls, attribute access
+	// and bare identifiers.
+	KindName Kind = "name"
+
+	// KindDynamicImport is reported for imports whose target is not a
+	// literal, e.g. require(name). It has no patterns.
+	KindDynamicImport Kind = "dynamic_import"
+
+	// KindDynamicAttribute is reported for reflective attribute access
+	// whose name is not a literal, e.g. getattr(obj, name). It has no
+	// patterns.
+	KindDynamicAttribute Kind = "dynamic_attribute"
+
+	// KindLibrary is reported for imports of a library set that was not
+	// selected. It is enforced under every policy.
+	KindLibrary Kind = "library"
+)
+
+// Ref is a resolved reference found in code, e.g. "os.system".
+//
+// Qualified names use "." between segments whatever the language
+// syntax ("::" in Rust and C++). A leading "." marks a member of an
+// unknown receiver, e.g. ".setAccessible" in obj.getClass().setAccessible.
+type Ref struct {
+	Name   string
+	Line   int
+	Column int
+}
+
+// Source holds the references an Analyzer found in code.
+type Source struct {
+	Imports           []Ref
+	Calls             []Ref
+	Names             []Ref
+	DynamicImports    []Ref
+	DynamicAttributes []Ref
+}
+
+// Rule forbids references matching Pattern from the given Level on.
+//
+// Patterns match a reference equal to them or nested below them, so
+// "os" matches "os.system" and "net" matches "net/http". A pattern
+// starting with "." matches a member of that name on any receiver.
+type Rule struct {
+	Kind    Kind
+	Pattern string
+	Level   Level
+}
+
+// Finding is a rule violation at a position in the submitted code.
+type Finding struct {
+	Kind    Kind   `json:"kind"`
+	Level   Level  `json:"level"`
+	Target  string `json:"target"`
+	Message string `json:"message"`
+	Line    int    `json:"line"`
+	Column  int    `json:"column"`
+}
+
+// FindingsError rejects code that has findings.
+type FindingsError struct {
+	Findings []Finding
+}
+
+func (e *FindingsError) Error() string {
+	if len(e.Findings) == 0 {
+		return "code rejected by static analysis"
+	}
+	f := e.Findings[0]
+	msg := fmt.Sprintf("line %d:%d: %s", f.Line, f.Column, f.Message)
+	if n := len(e.Findings) - 1; n > 0 {
+		msg += fmt.Sprintf(" (and %d more)", n)
+	}
+	return msg
+}
+
+// Analyzer extracts the references of a source file. Analyzers are
+// best-effort on code that does not compile: whatever can be parsed
+// is returned and compile errors are left to the compiler.
+type Analyzer func(code string) Source
+
+var analyzers = map[string]Analyzer{
+	"c":          analyzeC,
+	"cpp":        analyzeCpp,
+	"go":         analyzeGo,
+	"java":       analyzeJava,
+	"javascript": analyzeJavaScript,
+	"kotlin":     analyzeKotlin,
+	"python":     analyzePython,
+	"ruby":       analyzeRuby,
+	"rust":       analyzeRust,
+	"typescript": analyzeJavaScript,
+}
+
+// Get returns the analyzer registered under name.
+func Get(name string) (Analyzer, bool) {
+	a, ok := analyzers[name]
+	return a, ok
+}
+
+// Names returns the registered analyzer names in sorted order.
+func Names() []string {
+	names := make([]string, 0, len(analyzers))
+	for name := range analyzers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Check matches the references in src against rules and returns the
+// findings enforced under policy, ordered by position.
+func Check(src Source, rules []Rule, policy Policy) []Finding {
+	var findings []Finding
+	seen := map[string]bool{}
+
+	add := func(kind Kind, level Level, ref Ref, message string) {
+		key := fmt.Sprintf("%d:%d:%s", ref.Line, ref.Column, message)
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+		findings = append(findings, Finding{
+			Kind:    kind,
+			Level:   level,
+			Target:  ref.Name,
+			Message: message,
+			Line:    ref.Line,
+			Column:  ref.Column,
+		})
+	}
+
+	if policy.Enforces(LevelStandard) {
+		for _, ref := range src.DynamicImports {
+			add(KindDynamicImport, LevelStandard, ref, "dynamic import is not allowed")
+		}
+		for _, ref := range src.DynamicAttributes {
+			add(KindDynamicAttribute, LevelStandard, ref, "dynamic attribute access is not allowed")
+		}
+	}
+
+	for _, rule := range rules {
+		if !policy.Enforces(rule.Level) {
+			continue
+		}
+		switch rule.Kind {
+		case KindImport:
+			for _, ref := range src.Imports {
+				if Matches(rule.Pattern, ref.Name) {
+					add(rule.Kind, rule.Level, ref, fmt.Sprintf("import of %s is not allowed", ref.Name))
+				}
+			}
+		case KindCall:
+			for _, ref := range src.Calls {
+				if Matches(rule.Pattern, ref.Name) {
+					add(rule.Kind, rule.Level, ref, fmt.Sprintf("call to %s is not allowed", displayName(ref.Name)))
+				}
+			}
+		case KindName:
+			for _, refs := range [][]Ref{src.Imports, src.Calls, src.Names} {
+				for _, ref := range refs {
+					if Matches(rule.Pattern, ref.Name) {
+						add(rule.Kind, rule.Level, ref, fmt.Sprintf("use of %s is not allowed", displayName(ref.Name)))
+					}
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].Line != findings[j].Line {
+			return findings[i].Line < findings[j].Line
+		}
+		return findings[i].Column < findings[j].Column
+	})
+	return findings
+}
+
+// Matches reports whether pattern matches the reference name.
+func Matches(pattern, name string) bool {
+	pattern = normalize(pattern)
+	name = normalize(name)
+
+	if member, ok := strings.CutPrefix(pattern, "."); ok {
+		segments := strings.Split(name, ".")
+		for _, s := range segments[1:] {
+			if s == member {
+				return true
+			}
+		}
+		return false
+	}
+
+	return name == pattern ||
+		strings.HasPrefix(name, pattern+".") ||
+		strings.HasPrefix(name, pattern+"/")
+}
+
+func normalize(name string) string {
+	return strings.ReplaceAll(name, "::", ".")
+}
+
+func displayName(name string) string {
+	return strings.TrimPrefix(name, ".")
+}
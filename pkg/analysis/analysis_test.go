@@ -0,0 +1,305 @@
+package analysis
+
+import (
+	"slices"
+	"testing"
+)
+
+// rules builds rules of one kind and level.
+func rules(kind Kind, level Level, patterns ...string) []Rule {
+	out := make([]Rule, 0, len(patterns))
+	for _, p := range patterns {
+		out = append(out, Rule{Kind: kind, Pattern: p, Level: level})
+	}
+	return out
+}
+
+var (
+	pythonRules = slices.Concat(
+		rules(KindImport, LevelStandard, "os", "subprocess", "importlib", "builtins"),
+		rules(KindImport, LevelStrict, "threading"),
+		rules(KindName, LevelStandard, "eval", "exec", "__import__", "__builtins__", ".__subclasses__", ".__globals__",
+			"globals", "vars", ".__dict__", ".__base__", "operator.attrgetter"),
+		rules(KindName, LevelStrict, "getattr"),
+		rules(KindName, LevelFiles, "open", "io.open"),
+	)
+	javaRules = slices.Concat(
+		rules(KindImport, LevelStandard, "java.net", "java.lang.reflect"),
+		rules(KindName, LevelStandard, "java.lang.Runtime", "java.lang.Class.forName",
+			".getDeclaredMethod", ".setAccessible", ".getMethod", ".newInstance"),
+		rules(KindName, LevelStrict, "java.lang.Thread.sleep"),
+	)
+)
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name     string
+		language string
+		code     string
+		rules    []Rule
+		policy   Policy
+		want     []string // targets of the findings, in order
+	}{
+		{
+			name:     "python reopen is not open",
+			language: "python",
+			code:     "f = stream.reopen('x')\nprint(reopen(f))\n",
+			rules:    pythonRules,
+			policy:   PolicyStrict,
+		},
+		{
+			name:     "python open in a string or comment",
+			language: "python",
+			code:     "print('open(')  # open('/etc/passwd')\n",
+			rules:    pythonRules,
+			policy:   PolicyStrict,
+		},
+		{
+			name:     "python open",
+			language: "python",
+			code:     "data = open('/etc/passwd').read()\n",
+			rules:    pythonRules,
+			policy:   PolicyStandard,
+			want:     []string{"open"},
+		},
+		{
+			name:     "python getattr on builtins with a built name",
+			language: "python",
+			code:     "f = getattr(__builtins__, 'ev' + 'al')\nf('1')\n",
+			rules:    pythonRules,
+			policy:   PolicyStandard,
+			want:     []string{"getattr", "__builtins__"},
+		},
+		{
+			name:     "python getattr with a built name",
+			language: "python",
+			code:     "import sys\nf = getattr(sys, 'mod' + 'ules')\n",
+			rules:    pythonRules,
+			policy:   PolicyStandard,
+			want:     []string{"getattr"},
+		},
+		{
+			name:     "python setattr with a variable name",
+			language: "python",
+			code:     "name = 'system'\nsetattr(obj, name, 1)\n",
+			rules:    pythonRules,
+			policy:   PolicyStandard,
+			want:     []string{"setattr"},
+		},
+		{
+			name:     "python getattr with a literal name",
+			language: "python",
+			code:     "import os as o\nf = getattr(o, 'system')\n",
+			rules:    rules(KindName, LevelStandard, "os.system"),
+			policy:   PolicyStandard,
+			want:     []string{"os.system"},
+		},
+		{
+			name:     "python getattr with a literal dunder on an expression",
+			language: "python",
+			code:     "s = getattr(type(()), '__base__', None)\n",
+			rules:    pythonRules,
+			policy:   PolicyStandard,
+			want:     []string{".__base__"},
+		},
+		{
+			name:     "python builtins dict",
+			language: "python",
+			code:     "f = __builtins__.__dict__['ev' + 'al']\n",
+			rules:    pythonRules,
+			policy:   PolicyStandard,
+			want:     []string{"__builtins__.__dict__"},
+		},
+		{
+			name:     "python class dict",
+			language: "python",
+			code:     "s = object.__dict__['__subclasses__']\n",
+			rules:    pythonRules,
+			policy:   PolicyStandard,
+			want:     []string{"object.__dict__"},
+		},
+		{
+			name:     "python globals by name",
+			language: "python",
+			code:     "b = globals()['__built' + 'ins__']\n",
+			rules:    pythonRules,
+			policy:   PolicyStandard,
+			want:     []string{"globals"},
+		},
+		{
+			name:     "python base chain",
+			language: "python",
+			code:     "o = ''.__class__.__base__\nsubs = vars(o)\n",
+			rules:    pythonRules,
+			policy:   PolicyStandard,
+			want:     []string{".__class__.__base__", "vars"},
+		},
+		{
+			name:     "python attrgetter with a built name",
+			language: "python",
+			code:     "from operator import attrgetter\nf = attrgetter('sys' + 'tem')\n",
+			rules:    pythonRules,
+			policy:   PolicyStandard,
+			want:     []string{"operator.attrgetter"},
+		},
+		{
+			name:     "python getattr under strict",
+			language: "python",
+			code:     "x = getattr(obj, 'name')\n",
+			rules:    pythonRules,
+			policy:   PolicyStrict,
+			want:     []string{"getattr"},
+		},
+		{
+			name:     "python getattr under standard",
+			language: "python",
+			code:     "x = getattr(obj, 'name')\n",
+			rules:    pythonRules,
+			policy:   PolicyStandard,
+		},
+		{
+			name:     "python import alias",
+			language: "python",
+			code:     "import io as i\ni.open('x')\n",
+			rules:    pythonRules,
+			policy:   PolicyStandard,
+			want:     []string{"io.open"},
+		},
+		{
+			name:     "python from import alias",
+			language: "python",
+			code:     "from io import open as o\no('x')\n",
+			rules:    pythonRules,
+			policy:   PolicyStandard,
+			want:     []string{"io.open"},
+		},
+		{
+			name:     "python subclasses walk",
+			language: "python",
+			code:     "for c in ().__class__.__base__.__subclasses__():\n    pass\n",
+			rules:    pythonRules,
+			policy:   PolicyStandard,
+			want:     []string{".__class__.__base__.__subclasses__"},
+		},
+		{
+			name:     "python strict import under standard",
+			language: "python",
+			code:     "import threading\n",
+			rules:    pythonRules,
+			policy:   PolicyStandard,
+		},
+		{
+			name:     "python everything under off",
+			language: "python",
+			code:     "import os\neval('1')\n",
+			rules:    pythonRules,
+			policy:   PolicyOff,
+		},
+		{
+			name:     "java reflection on a class literal",
+			language: "java",
+			code: `public class Main {
+    public static void main(String[] args) throws Exception {
+        var m = String.class.getDeclaredMethod("value");
+        m.setAccessible(true);
+    }
+}`,
+			rules:  javaRules,
+			policy: PolicyStandard,
+			want:   []string{"String.class.getDeclaredMethod", "m.setAccessible"},
+		},
+		{
+			name:     "java Class.forName without import",
+			language: "java",
+			code: `public class Main {
+    public static void main(String[] args) throws Exception {
+        Class.forName("java.lang.Runtime").getMethod("getRuntime").invoke(null);
+    }
+}`,
+			rules:  javaRules,
+			policy: PolicyStandard,
+			want:   []string{"java.lang.Class.forName", ".getMethod"},
+		},
+		{
+			name:     "java reflect import",
+			language: "java",
+			code:     "import java.lang.reflect.Method;\npublic class Main {}\n",
+			rules:    javaRules,
+			policy:   PolicyStandard,
+			want:     []string{"java.lang.reflect.Method"},
+		},
+		{
+			name:     "java Thread.sleep under standard",
+			language: "java",
+			code:     "public class Main { public static void main(String[] a) throws Exception { Thread.sleep(10); } }",
+			rules:    javaRules,
+			policy:   PolicyStandard,
+		},
+		{
+			name:     "java Thread.sleep under strict",
+			language: "java",
+			code:     "public class Main { public static void main(String[] a) throws Exception { Thread.sleep(10); } }",
+			rules:    javaRules,
+			policy:   PolicyStrict,
+			want:     []string{"java.lang.Thread.sleep"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			analyze, ok := Get(tt.language)
+			if !ok {
+				t.Fatalf("no analyzer for %s", tt.language)
+			}
+			var got []string
+			for _, f := range Check(analyze(tt.code), tt.rules, tt.policy) {
+				got = append(got, f.Target)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("findings = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatches(t *testing.T) {
+	tests := []struct {
+		pattern, name string
+		want          bool
+	}{
+		{"os", "os", true},
+		{"os", "os.system", true},
+		{"os", "osx", false},
+		{"net", "net/http", true},
+		{"std::fs", "std.fs.write", true},
+		{".setAccessible", "m.setAccessible", true},
+		{".setAccessible", "setAccessible", false},
+	}
+	for _, tt := range tests {
+		if got := Matches(tt.pattern, tt.name); got != tt.want {
+			t.Errorf("Matches(%q, %q) = %v, want %v", tt.pattern, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestPolicyEnforces(t *testing.T) {
+	tests := []struct {
+		policy Policy
+		level  Level
+		want   bool
+	}{
+		{PolicyStrict, LevelStandard, true},
+		{PolicyStrict, LevelStrict, true},
+		{PolicyStrict, LevelFiles, true},
+		{PolicyStandard, LevelStandard, true},
+		{PolicyStandard, LevelStrict, false},
+		{PolicyStandard, LevelFiles, true},
+		{PolicyOff, LevelStandard, false},
+		{PolicyOff, LevelFiles, false},
+	}
+	for _, tt := range tests {
+		if got := tt.policy.Enforces(tt.level); got != tt.want {
+			t.Errorf("%s.Enforces(%s) = %v, want %v", tt.policy, tt.level, got, tt.want)
+		}
+	}
+}
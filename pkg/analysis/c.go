@@ -0,0 +1,61 @@
+package analysis
+
+var cSyntax = syntax{
+	lineComments:  []string{"//"},
+	blockComments: [][2]string{{"/*", "*/"}},
+	quotes:        `"'`,
+	include:       true,
+}
+
+func analyzeC(code string) Source {
+	return analyzeCFamily(code, false)
+}
+
+func analyzeCpp(code string) Source {
+	return analyzeCFamily(code, true)
+}
+
+// analyzeCFamily reports #include paths as imports. Macro bodies are
+// scanned like any other code, so `#define run system` is caught at the
+// definition, and token pasting (##) is reported as a name.
+//
+// In C++ the std:: qualifier is dropped, making std::system and system
+// (after `using namespace std`) the same reference.
+func analyzeCFamily(code string, cpp bool) Source {
+	toks := lex(code, cSyntax)
+	skip := map[int]bool{}
+	var src Source
+
+	for i := 0; i < len(toks); i++ {
+		switch {
+		case isPunct(toks, i, "#") && isIdent(toks, i+1, "include"):
+			if i+2 < len(toks) && toks[i+2].kind == tokString {
+				src.addImport(toks[i+2].text, toks[i])
+			}
+			skip[i+1] = true
+			i += 2
+		case isPunct(toks, i, "##"):
+			src.Names = append(src.Names, Ref{Name: "##", Line: toks[i].line, Column: toks[i].col})
+		}
+	}
+
+	seps := []string{"."}
+	if cpp {
+		seps = append(seps, "::")
+	}
+	for _, c := range scanChains(toks, skip, seps...) {
+		// ::system is the global system, not a member
+		if c.member && isPunct(toks, c.start-1, "::") {
+			c.member = false
+		}
+		// x->y is a member of a pointer
+		if isPunct(toks, c.start-1, "->") {
+			c.member = true
+		}
+		if cpp && !c.member && len(c.parts) > 1 && c.parts[0] == "std" {
+			c.parts = c.parts[1:]
+		}
+		src.addRef(c, c.name(nil))
+	}
+	return src
+}
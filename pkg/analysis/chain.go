@@ -0,0 +1,116 @@
+package analysis
+
+import "strings"
+
+// chain is a qualified name in a token stream, e.g. os.path.join or
+// std::fs::write.
+type chain struct {
+	parts []string
+	line  int
+	col   int
+
+	// member is set when the chain continues an expression that is
+	// not a name, e.g. .getMethod in x.getClass().getMethod(..).
+	member bool
+	// call is set when the chain is followed by "(".
+	call bool
+
+	// start and end are the token indices the chain spans.
+	start int
+	end   int
+}
+
+// scanChains groups identifiers joined by any of seps into chains,
+// skipping tokens marked in skip.
+func scanChains(toks []token, skip map[int]bool, seps ...string) []chain {
+	isSep := func(t token) bool {
+		return t.kind == tokPunct && containsString(seps, t.text)
+	}
+
+	var chains []chain
+	for i := 0; i < len(toks); i++ {
+		if skip[i] || toks[i].kind != tokIdent {
+			continue
+		}
+		c := chain{
+			parts:  []string{toks[i].text},
+			line:   toks[i].line,
+			col:    toks[i].col,
+			member: i > 0 && isSep(toks[i-1]),
+			start:  i,
+		}
+		j := i + 1
+		for j+1 < len(toks) && isSep(toks[j]) && toks[j+1].kind == tokIdent && !skip[j+1] {
+			c.parts = append(c.parts, toks[j+1].text)
+			j += 2
+		}
+		c.end = j
+		c.call = j < len(toks) && toks[j].kind == tokPunct && toks[j].text == "("
+		chains = append(chains, c)
+		i = j - 1
+	}
+	return chains
+}
+
+// name returns the qualified name of c, substituting its first part
+// with aliases (e.g. np → numpy).
+func (c chain) name(aliases map[string]string) string {
+	if c.member {
+		return "." + strings.Join(c.parts, ".")
+	}
+	parts := c.parts
+	if full, ok := aliases[parts[0]]; ok {
+		parts = append([]string{full}, parts[1:]...)
+	}
+	return strings.Join(parts, ".")
+}
+
+// addRef records a chain as a call or a name.
+func (s *Source) addRef(c chain, name string) {
+	ref := Ref{Name: name, Line: c.line, Column: c.col}
+	if c.call {
+		s.Calls = append(s.Calls, ref)
+	} else {
+		s.Names = append(s.Names, ref)
+	}
+}
+
+// addImport records an import at the position of t.
+func (s *Source) addImport(name string, t token) {
+	s.Imports = append(s.Imports, Ref{Name: name, Line: t.line, Column: t.col})
+}
+
+// addDynamicImport records an import whose target is computed at run time.
+func (s *Source) addDynamicImport(t token) {
+	s.DynamicImports = append(s.DynamicImports, Ref{Name: t.text, Line: t.line, Column: t.col})
+}
+
+// addDynamicAttribute records attribute access whose name is computed
+// at run time.
+func (s *Source) addDynamicAttribute(t token) {
+	s.DynamicAttributes = append(s.DynamicAttributes, Ref{Name: t.text, Line: t.line, Column: t.col})
+}
+
+// isPunct reports whether toks[i] is the punctuation p.
+func isPunct(toks []token, i int, p string) bool {
+	return i >= 0 && i < len(toks) && toks[i].kind == tokPunct && toks[i].text == p
+}
+
+// isIdent reports whether toks[i] is the identifier or keyword s.
+func isIdent(toks []token, i int, s string) bool {
+	return i >= 0 && i < len(toks) && toks[i].kind == tokIdent && toks[i].text == s
+}
+
+// dottedName reads a name such as a.b.c starting at toks[i] and
+// returns it with the index after it.
+func dottedName(toks []token, i int, sep string) (string, int) {
+	var parts []string
+	for i < len(toks) && toks[i].kind == tokIdent {
+		parts = append(parts, toks[i].text)
+		if !isPunct(toks, i+1, sep) {
+			return strings.Join(parts, "."), i + 1
+		}
+		i += 2
+	}
+	return strings.Join(parts, "."), i
+}
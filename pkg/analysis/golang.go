@@ -0,0 +1,112 @@
+package analysis
+
+import (
+	"go/ast"
+	"go/parser"
+	gotoken "go/token"
+	"path"
+	"strconv"
+	"strings"
+)
+
+var goSyntax = syntax{
+	lineComments:  []string{"//"},
+	blockComments: [][2]string{{"/*", "*/"}},
+	quotes:        "\"'`",
+	multiline:     "`",
+}
+
+// analyzeGo parses code with go/parser. Selectors on imported packages
+// resolve to the import path (x.Command with `x "os/exec"` becomes
+// os/exec.Command), and compiler directives such as //go:linkname are
+// reported as names ("go:linkname").
+func analyzeGo(code string) Source {
+	fset := gotoken.NewFileSet()
+	// The partial AST of code with syntax errors is still analyzed; the
+	// compiler reports the errors.
+	file, _ := parser.ParseFile(fset, "main.go", code, parser.ParseComments|parser.AllErrors)
+	var src Source
+	if file == nil {
+		return src
+	}
+
+	ref := func(name string, pos gotoken.Pos) Ref {
+		p := fset.Position(pos)
+		return Ref{Name: name, Line: p.Line, Column: p.Column}
+	}
+
+	packages := map[string]string{}
+	var dotImports []string
+	for _, imp := range file.Imports {
+		importPath, err := strconv.Unquote(imp.Path.Value)
+		if err != nil {
+			continue
+		}
+		src.Imports = append(src.Imports, ref(importPath, imp.Pos()))
+
+		name := goPackageName(importPath)
+		if imp.Name != nil {
+			name = imp.Name.Name
+		}
+		switch name {
+		case "_":
+		case ".":
+			dotImports = append(dotImports, importPath)
+		default:
+			packages[name] = importPath
+		}
+	}
+
+	for _, group := range file.Comments {
+		for _, c := range group.List {
+			if directive, ok := strings.CutPrefix(c.Text, "//go:"); ok {
+				name := "go:" + strings.Fields(directive + " ")[0]
+				src.Names = append(src.Names, ref(name, c.Pos()))
+			}
+		}
+	}
+
+	calls := map[ast.Expr]bool{}
+	ast.Inspect(file, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.CallExpr:
+			calls[ast.Unparen(n.Fun)] = true
+		case *ast.SelectorExpr:
+			if x, ok := n.X.(*ast.Ident); ok && x.Obj == nil {
+				if importPath, ok := packages[x.Name]; ok {
+					r := ref(importPath+"."+n.Sel.Name, n.Pos())
+					if calls[n] {
+						src.Calls = append(src.Calls, r)
+					} else {
+						src.Names = append(src.Names, r)
+					}
+					return false
+				}
+			}
+		case *ast.Ident:
+			if n.Obj != nil {
+				break
+			}
+			for _, importPath := range dotImports {
+				r := ref(importPath+"."+n.Name, n.Pos())
+				if calls[n] {
+					src.Calls = append(src.Calls, r)
+				} else {
+					src.Names = append(src.Names, r)
+				}
+			}
+		}
+		return true
+	})
+	return src
+}
+
+// goPackageName guesses the package name of an import path from its
+// last element, skipping major version suffixes such as /v2.
+func goPackageName(importPath string) string {
+	name := path.Base(importPath)
+	if len(name) > 1 && name[0] == 'v' && strings.Trim(name[1:], "0123456789") == "" {
+		name = path.Base(path.Dir(importPath))
+	}
+	return strings.TrimPrefix(name, "go-")
+}
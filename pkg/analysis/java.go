@@ -0,0 +1,113 @@
+package analysis
+
+import "strings"
+
+var javaSyntax = syntax{
+	lineComments:  []string{"//"},
+	blockComments: [][2]string{{"/*", "*/"}},
+	quotes:        `"'`,
+	triple:        true,
+}
+
+var kotlinSyntax = syntax{
+	lineComments:  []string{"//"},
+	blockComments: [][2]string{{"/*", "*/"}},
+	quotes:        `"'`,
+	triple:        true,
+	interp: func(quote, _ string) string {
+		if quote != "'" {
+			return "${"
+		}
+		return ""
+	},
+	dollarNames: true,
+}
+
+// javaLang holds the java.lang classes that matter to the rules. They
+// are visible without an import in Java and Kotlin.
+var javaLang = []string{"Class", "ClassLoader", "Process", "ProcessBuilder", "Runtime", "System", "Thread"}
+
+// packageRoots are the top-level packages whose fully qualified names,
+// e.g. java.net.Socket, count as imports where they are used.
+var packageRoots = []string{"com", "java", "javax", "jdk", "kotlin", "sun"}
+
+func analyzeJava(code string) Source {
+	return analyzeJVM(lex(code, javaSyntax))
+}
+
+func analyzeKotlin(code string) Source {
+	return analyzeJVM(lex(code, kotlinSyntax))
+}
+
+// analyzeJVM resolves simple class names through single-type imports
+// (and Kotlin `import a.B as C`) to qualified names, so Runtime.getRuntime
+// becomes java.lang.Runtime.getRuntime. Names from wildcard imports are
+// resolved against every wildcard package.
+func analyzeJVM(toks []token) Source {
+	aliases := map[string]string{}
+	for _, name := range javaLang {
+		aliases[name] = "java.lang." + name
+	}
+	skip := map[int]bool{}
+	var wildcards []string
+	var src Source
+
+	for i := 0; i < len(toks); i++ {
+		if !isIdent(toks, i, "import") && !isIdent(toks, i, "package") {
+			continue
+		}
+		keyword := toks[i]
+		skip[i] = true
+		j := i + 1
+		if isIdent(toks, j, "static") {
+			skip[j] = true
+			j++
+		}
+		name, next := dottedName(toks, j, ".")
+		for k := j; k < next; k++ {
+			skip[k] = true
+		}
+		i = next - 1
+		if keyword.text == "package" || name == "" {
+			continue
+		}
+
+		src.addImport(name, keyword)
+		switch {
+		case isPunct(toks, next, "*"):
+			skip[next] = true
+			wildcards = append(wildcards, name)
+			i = next
+		case isIdent(toks, next, "as") && next+1 < len(toks):
+			aliases[toks[next+1].text] = name
+			skip[next], skip[next+1] = true, true
+			i = next + 1
+		default:
+			aliases[name[strings.LastIndex(name, ".")+1:]] = name
+		}
+	}
+
+	for _, c := range scanChains(toks, skip, ".", "::") {
+		src.addRef(c, c.name(aliases))
+		if c.member {
+			continue
+		}
+		if _, ok := aliases[c.parts[0]]; !ok && containsString(packageRoots, c.parts[0]) && len(c.parts) > 1 {
+			src.Imports = append(src.Imports, Ref{Name: strings.Join(c.parts, "."), Line: c.line, Column: c.col})
+		}
+		if len(wildcards) == 0 {
+			continue
+		}
+		if _, ok := aliases[c.parts[0]]; ok || !isUpper(c.parts[0]) {
+			continue
+		}
+		for _, pkg := range wildcards {
+			src.addRef(c, pkg+"."+strings.Join(c.parts, "."))
+		}
+	}
+	return src
+}
+
+func isUpper(s string) bool {
+	return s != "" && s[0] >= 'A' && s[0] <= 'Z'
+}
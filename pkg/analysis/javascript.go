@@ -0,0 +1,164 @@
+package analysis
+
+import "strings"
+
+var javascriptSyntax = syntax{
+	lineComments:  []string{"//"},
+	blockComments: [][2]string{{"/*", "*/"}},
+	quotes:        "\"'`",
+	multiline:     "`",
+	interp: func(quote, _ string) string {
+		if quote == "`" {
+			return "${"
+		}
+		return ""
+	},
+	identStart: "$",
+	regex:      true,
+}
+
+// analyzeJavaScript handles JavaScript and TypeScript. It resolves
+// ES module imports and CommonJS require() bindings, including
+// destructuring: `const { exec: run } = require("child_process")`
+// makes run refer to child_process.exec.
+func analyzeJavaScript(code string) Source {
+	toks := lex(code, javascriptSyntax)
+	aliases := map[string]string{}
+	skip := map[int]bool{}
+	var src Source
+
+	for i := 0; i < len(toks); i++ {
+		switch {
+		case isIdent(toks, i, "require") && isPunct(toks, i+1, "(") && !isPunct(toks, i-1, "."):
+			jsRequire(toks, i, aliases, skip, &src)
+		case isIdent(toks, i, "import") && isPunct(toks, i+1, "("):
+			// import("x")
+			if i+2 < len(toks) && toks[i+2].kind == tokString && !toks[i+2].interp && isPunct(toks, i+3, ")") {
+				src.addImport(jsModule(toks[i+2].text), toks[i])
+			} else {
+				src.addDynamicImport(toks[i])
+			}
+			skip[i] = true
+		case (isIdent(toks, i, "import") || isIdent(toks, i, "export")) && !isPunct(toks, i-1, "."):
+			i = jsImport(toks, i, aliases, skip, &src)
+		}
+	}
+
+	for _, c := range scanChains(toks, skip, ".", "?.") {
+		src.addRef(c, c.name(aliases))
+	}
+	return src
+}
+
+// jsModule strips the node: scheme from built-in module names.
+func jsModule(spec string) string {
+	return strings.TrimPrefix(spec, "node:")
+}
+
+// jsRequire handles require(..) at toks[i] and the binding it is
+// assigned to, if any.
+func jsRequire(toks []token, i int, aliases map[string]string, skip map[int]bool, src *Source) {
+	skip[i] = true
+	arg := i + 2
+	if arg >= len(toks) || toks[arg].kind != tokString || toks[arg].interp || !isPunct(toks, arg+1, ")") {
+		src.addDynamicImport(toks[i])
+		return
+	}
+	module := jsModule(toks[arg].text)
+	src.addImport(module, toks[i])
+
+	// require("x").y
+	target := module
+	if isPunct(toks, arg+2, ".") && arg+3 < len(toks) && toks[arg+3].kind == tokIdent {
+		target += "." + toks[arg+3].text
+	}
+
+	// Walk back over `name =`, `{ a, b: c } =` or TypeScript `import name =`
+	if !isPunct(toks, i-1, "=") {
+		return
+	}
+	switch j := i - 2; {
+	case j >= 0 && toks[j].kind == tokIdent:
+		aliases[toks[j].text] = target
+		skip[j] = true
+	case isPunct(toks, j, "}"):
+		k := j
+		for k >= 0 && !isPunct(toks, k, "{") {
+			k--
+		}
+		jsBindings(toks, k+1, j, target, aliases, skip)
+	}
+}
+
+// jsBindings binds the names of a destructuring pattern or named
+// import list, toks[from:to], as members of module.
+func jsBindings(toks []token, from, to int, module string, aliases map[string]string, skip map[int]bool) {
+	for k := from; k < to; k++ {
+		if toks[k].kind != tokIdent {
+			continue
+		}
+		name := toks[k].text
+		local := name
+		// { a: b } in require, { a as b } in import
+		if (isPunct(toks, k+1, ":") || isIdent(toks, k+1, "as")) && k+2 < to && toks[k+2].kind == tokIdent {
+			local = toks[k+2].text
+			skip[k+2] = true
+		}
+		skip[k] = true
+		aliases[local] = module + "." + name
+		if local != name {
+			k += 2
+		}
+	}
+}
+
+// jsImport parses an ES import or re-export at toks[i]:
+//
+//	import x from "m"; import * as x from "m"; import { a as b } from "m"
+//	import "m"; export { a } from "m"; export * from "m"
+func jsImport(toks []token, i int, aliases map[string]string, skip map[int]bool, src *Source) int {
+	// Find the module specifier that ends the statement
+	end := i + 1
+	for end < len(toks) && end-i < 256 {
+		if toks[end].kind == tokString {
+			break
+		}
+		if isPunct(toks, end, ";") || isPunct(toks, end, "=") {
+			return i
+		}
+		end++
+	}
+	if end >= len(toks) || toks[end].kind != tokString {
+		return i
+	}
+	if end != i+1 && !isIdent(toks, end-1, "from") {
+		return i
+	}
+	module := jsModule(toks[end].text)
+	src.addImport(module, toks[i])
+	for k := i; k <= end; k++ {
+		skip[k] = true
+	}
+	if isIdent(toks, i, "export") {
+		return end
+	}
+
+	for k := i + 1; k < end-1; k++ {
+		switch {
+		case isPunct(toks, k, "{"):
+			close := k
+			for close < end && !isPunct(toks, close, "}") {
+				close++
+			}
+			jsBindings(toks, k+1, close, module, aliases, skip)
+			k = close
+		case isPunct(toks, k, "*") && isIdent(toks, k+1, "as") && k+2 < end:
+			aliases[toks[k+2].text] = module
+			k += 2
+		case toks[k].kind == tokIdent && toks[k].text != "type":
+			// Default import
+			aliases[toks[k].text] = module
+		}
+	}
+	return end
+}
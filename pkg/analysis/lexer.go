@@ -0,0 +1,512 @@
+package analysis
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type tokenKind int
+
+const (
+	tokIdent tokenKind = iota
+	tokString
+	tokNumber
+	tokPunct
+)
+
+type token struct {
+	kind tokenKind
+	text string // identifier, punctuation, or string contents
+	line int
+	col  int
+
+	// interp is set on strings with interpolated expressions, which
+	// are lexed into the surrounding token stream.
+	interp bool
+	// shell is set on Ruby backtick and %x strings.
+	shell bool
+}
+
+// syntax describes the lexical rules of a language family.
+type syntax struct {
+	lineComments  []string
+	blockComments [][2]string
+
+	// quotes are the string delimiters; multiline lists those that may
+	// span lines.
+	quotes    string
+	multiline string
+
+	// triple enables """ and ''' strings (Python, Java, Kotlin).
+	triple bool
+	// stringPrefixes are identifiers that prefix a string, e.g. f"..".
+	stringPrefixes []string
+	// interp returns the opener of interpolated expressions in a string
+	// with the given delimiter and prefix, or "" when it has none.
+	interp func(quote, prefix string) string
+
+	// identStart lists extra characters that may start an identifier
+	// (e.g. "$"); identSuffix those that may end one (Ruby "?" and "!").
+	identStart  string
+	identSuffix string
+
+	// dollarNames enables Kotlin "$name" string templates.
+	dollarNames bool
+
+	// rust enables raw strings (r#".."#) and lifetimes ('a).
+	rust bool
+	// ruby enables =begin/=end comments and %x/%q/%w literals.
+	ruby bool
+	// regex enables JavaScript regular expression literals.
+	regex bool
+	// include lexes `#include <path>` paths as strings.
+	include bool
+
+	puncts []string
+}
+
+var defaultPuncts = []string{"...", "::", "->", "=>", "?.", "&.", "##", "==", "!=", "<=", ">=", "&&", "||"}
+
+type lexer struct {
+	syn  syntax
+	src  string
+	pos  int
+	line int
+	col  int
+	toks []token
+}
+
+// lex splits src into tokens, dropping comments and whitespace.
+func lex(src string, syn syntax) []token {
+	if syn.puncts == nil {
+		syn.puncts = defaultPuncts
+	}
+	l := &lexer{syn: syn, src: src, line: 1, col: 1}
+	l.run()
+	return l.toks
+}
+
+func (l *lexer) run() {
+	for l.pos < len(l.src) {
+		c := l.src[l.pos]
+		switch {
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n' || c == '\f' || c == '\v':
+			l.advance(1)
+		case l.skipComment():
+		case l.lexString():
+		case l.isIdentStart():
+			l.lexIdent()
+		case c >= '0' && c <= '9':
+			l.lexNumber()
+		default:
+			l.lexPunct()
+		}
+	}
+}
+
+// advance moves n bytes forward, tracking line and column.
+func (l *lexer) advance(n int) {
+	for i := 0; i < n && l.pos < len(l.src); i++ {
+		c := l.src[l.pos]
+		l.pos++
+		switch {
+		case c == '\n':
+			l.line++
+			l.col = 1
+		case c&0xC0 != 0x80:
+			l.col++
+		}
+	}
+}
+
+func (l *lexer) atLineStart() bool {
+	i := l.pos - 1
+	for i >= 0 && (l.src[i] == ' ' || l.src[i] == '\t') {
+		i--
+	}
+	return i < 0 || l.src[i] == '\n'
+}
+
+func (l *lexer) skipComment() bool {
+	rest := l.src[l.pos:]
+	for _, lc := range l.syn.lineComments {
+		if strings.HasPrefix(rest, lc) {
+			end := strings.IndexByte(rest, '\n')
+			if end < 0 {
+				end = len(rest)
+			}
+			l.advance(end)
+			return true
+		}
+	}
+	for _, bc := range l.syn.blockComments {
+		if strings.HasPrefix(rest, bc[0]) {
+			end := strings.Index(rest[len(bc[0]):], bc[1])
+			if end < 0 {
+				l.advance(len(rest))
+			} else {
+				l.advance(len(bc[0]) + end + len(bc[1]))
+			}
+			return true
+		}
+	}
+	if l.syn.ruby && strings.HasPrefix(rest, "=begin") && l.atLineStart() {
+		end := strings.Index(rest, "\n=end")
+		if end < 0 {
+			l.advance(len(rest))
+		} else {
+			l.advance(end + len("\n=end"))
+		}
+		return true
+	}
+	return false
+}
+
+func (l *lexer) isIdentStart() bool {
+	r, _ := utf8.DecodeRuneInString(l.src[l.pos:])
+	return r == '_' || unicode.IsLetter(r) || strings.ContainsRune(l.syn.identStart, r)
+}
+
+func isIdentPart(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+func (l *lexer) lexIdent() {
+	line, col := l.line, l.col
+	start := l.pos
+	_, size := utf8.DecodeRuneInString(l.src[l.pos:])
+	end := l.pos + size
+	for end < len(l.src) {
+		r, size := utf8.DecodeRuneInString(l.src[end:])
+		if !isIdentPart(r) {
+			break
+		}
+		end += size
+	}
+	// Ruby method names may end in ? or !, but not in != or ?=
+	if end < len(l.src) && strings.IndexByte(l.syn.identSuffix, l.src[end]) >= 0 &&
+		(end+1 >= len(l.src) || l.src[end+1] != '=') {
+		end++
+	}
+	text := l.src[start:end]
+
+	if end < len(l.src) {
+		next := l.src[end]
+		if l.syn.rust && (text == "r" || text == "br") && (next == '"' || next == '#') {
+			if l.lexRawString(end, line, col) {
+				return
+			}
+		}
+		if strings.IndexByte(l.syn.quotes, next) >= 0 && containsString(l.syn.stringPrefixes, strings.ToLower(text)) {
+			l.advance(end - start)
+			l.lexStringAt(line, col, strings.ToLower(text))
+			return
+		}
+	}
+
+	l.advance(end - start)
+	l.emit(token{kind: tokIdent, text: text, line: line, col: col})
+
+	if l.syn.include && text == "include" {
+		l.lexIncludePath()
+	}
+}
+
+// lexIncludePath reads the <path> of an #include directive.
+func (l *lexer) lexIncludePath() {
+	for l.pos < len(l.src) && (l.src[l.pos] == ' ' || l.src[l.pos] == '\t') {
+		l.advance(1)
+	}
+	if l.pos >= len(l.src) || l.src[l.pos] != '<' {
+		return
+	}
+	end := strings.IndexAny(l.src[l.pos:], ">\n")
+	if end < 0 || l.src[l.pos+end] != '>' {
+		return
+	}
+	line, col := l.line, l.col
+	text := l.src[l.pos+1 : l.pos+end]
+	l.advance(end + 1)
+	l.emit(token{kind: tokString, text: text, line: line, col: col})
+}
+
+func (l *lexer) lexNumber() {
+	line, col := l.line, l.col
+	end := l.pos
+	for end < len(l.src) {
+		c := l.src[end]
+		if !(c == '_' || c == '.' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z') {
+			break
+		}
+		// Keep ranges like 0..10 apart
+		if c == '.' && end+1 < len(l.src) && l.src[end+1] == '.' {
+			break
+		}
+		end++
+	}
+	text := l.src[l.pos:end]
+	l.advance(end - l.pos)
+	l.emit(token{kind: tokNumber, text: text, line: line, col: col})
+}
+
+func (l *lexer) lexPunct() {
+	line, col := l.line, l.col
+	rest := l.src[l.pos:]
+
+	if l.syn.regex && rest[0] == '/' && l.regexAllowed() {
+		if end := regexEnd(rest); end > 0 {
+			l.advance(end)
+			l.emit(token{kind: tokString, text: rest[:end], line: line, col: col})
+			return
+		}
+	}
+	if l.syn.ruby && rest[0] == '%' && l.lexPercentLiteral() {
+		return
+	}
+
+	for _, p := range l.syn.puncts {
+		if strings.HasPrefix(rest, p) {
+			l.advance(len(p))
+			l.emit(token{kind: tokPunct, text: p, line: line, col: col})
+			return
+		}
+	}
+	_, size := utf8.DecodeRuneInString(rest)
+	l.advance(size)
+	l.emit(token{kind: tokPunct, text: rest[:size], line: line, col: col})
+}
+
+// regexAllowed reports whether a "/" starts a regular expression rather
+// than a division, judged by the previous token.
+func (l *lexer) regexAllowed() bool {
+	if len(l.toks) == 0 {
+		return true
+	}
+	prev := l.toks[len(l.toks)-1]
+	switch prev.kind {
+	case tokIdent:
+		switch prev.text {
+		case "return", "typeof", "case", "do", "else", "in", "of", "new", "delete", "void", "throw", "yield", "await":
+			return true
+		}
+		return false
+	case tokPunct:
+		return prev.text != ")" && prev.text != "]" && prev.text != "}"
+	}
+	return false
+}
+
+func regexEnd(s string) int {
+	inClass := false
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '[':
+			inClass = true
+		case ']':
+			inClass = false
+		case '/':
+			if !inClass {
+				i++
+				for i < len(s) && (s[i] >= 'a' && s[i] <= 'z') {
+					i++
+				}
+				return i
+			}
+		case '\n':
+			return -1
+		}
+	}
+	return -1
+}
+
+// lexPercentLiteral reads Ruby %x(..), %q(..), %w[..] and similar.
+func (l *lexer) lexPercentLiteral() bool {
+	rest := l.src[l.pos:]
+	if len(rest) < 3 || strings.IndexByte("qQwWiIx", rest[1]) < 0 {
+		return false
+	}
+	open := rest[2]
+	closers := map[byte]byte{'(': ')', '[': ']', '{': '}', '<': '>'}
+	closer, paired := closers[open]
+	if !paired {
+		if isIdentPart(rune(open)) || open == ' ' {
+			return false
+		}
+		closer = open
+	}
+
+	depth := 1
+	end := 3
+	for ; end < len(rest) && depth > 0; end++ {
+		switch rest[end] {
+		case '\\':
+			end++
+		case closer:
+			depth--
+		case open:
+			if paired {
+				depth++
+			}
+		}
+	}
+
+	line, col := l.line, l.col
+	body := rest[3:max(3, end-1)]
+	l.advance(end)
+	l.emit(token{kind: tokString, text: body, line: line, col: col, shell: rest[1] == 'x'})
+	return true
+}
+
+func (l *lexer) lexString() bool {
+	c := l.src[l.pos]
+	if strings.IndexByte(l.syn.quotes, c) < 0 {
+		return false
+	}
+	if l.syn.rust && c == '\'' && !l.isCharLiteral() {
+		// A lifetime such as 'a
+		l.advance(1)
+		return true
+	}
+	l.lexStringAt(l.line, l.col, "")
+	return true
+}
+
+// isCharLiteral tells a Rust char literal from a lifetime.
+func (l *lexer) isCharLiteral() bool {
+	rest := l.src[l.pos+1:]
+	if strings.HasPrefix(rest, "\\") {
+		return true
+	}
+	_, size := utf8.DecodeRuneInString(rest)
+	return size < len(rest) && rest[size] == '\''
+}
+
+// lexStringAt reads the string starting at l.pos, whose token starts at
+// line:col (before any prefix).
+func (l *lexer) lexStringAt(line, col int, prefix string) {
+	quote := l.src[l.pos : l.pos+1]
+	if l.syn.triple && strings.HasPrefix(l.src[l.pos:], strings.Repeat(quote, 3)) {
+		quote = strings.Repeat(quote, 3)
+	}
+	multiline := len(quote) == 3 || strings.Contains(l.syn.multiline, quote)
+	raw := strings.Contains(prefix, "r")
+
+	var opener string
+	if l.syn.interp != nil {
+		opener = l.syn.interp(quote, prefix)
+	}
+
+	tok := token{kind: tokString, line: line, col: col, shell: quote == "`" && l.syn.ruby}
+	var body strings.Builder
+	var inner []token
+
+	l.advance(len(quote))
+	for l.pos < len(l.src) {
+		rest := l.src[l.pos:]
+		switch {
+		case strings.HasPrefix(rest, quote):
+			l.advance(len(quote))
+			tok.text = body.String()
+			l.emit(tok)
+			l.toks = append(l.toks, inner...)
+			return
+		case rest[0] == '\\' && !raw && len(rest) > 1:
+			body.WriteString(rest[:2])
+			l.advance(2)
+		case rest[0] == '\n' && !multiline:
+			tok.text = body.String()
+			l.emit(tok)
+			l.toks = append(l.toks, inner...)
+			return
+		case opener == "{" && strings.HasPrefix(rest, "{{"):
+			// Escaped brace in a Python f-string
+			body.WriteString("{{")
+			l.advance(2)
+		case opener != "" && strings.HasPrefix(rest, opener):
+			tok.interp = true
+			inner = append(inner, l.lexInterpolation(opener)...)
+		case opener != "" && l.syn.dollarNames && rest[0] == '$' && len(rest) > 1 && isIdentPart(rune(rest[1])):
+			tok.interp = true
+			end := 1
+			for end < len(rest) && isIdentPart(rune(rest[end])) {
+				end++
+			}
+			inner = append(inner, token{kind: tokIdent, text: rest[1:end], line: l.line, col: l.col + 1})
+			l.advance(end)
+		default:
+			_, size := utf8.DecodeRuneInString(rest)
+			body.WriteString(rest[:size])
+			l.advance(size)
+		}
+	}
+	tok.text = body.String()
+	l.emit(tok)
+	l.toks = append(l.toks, inner...)
+}
+
+// lexInterpolation lexes the expression of an interpolation such as
+// ${expr} and returns its tokens, leaving l after the closing brace.
+func (l *lexer) lexInterpolation(opener string) []token {
+	l.advance(len(opener))
+	start, line, col := l.pos, l.line, l.col
+
+	depth := 1
+	end := l.pos
+	for ; end < len(l.src) && depth > 0; end++ {
+		switch l.src[end] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+		}
+	}
+	exprEnd := end
+	if depth == 0 {
+		exprEnd--
+	}
+
+	sub := &lexer{syn: l.syn, src: l.src[:exprEnd], pos: start, line: line, col: col}
+	sub.run()
+	l.advance(end - l.pos)
+	return sub.toks
+}
+
+// lexRawString reads a Rust raw string whose r/br prefix ends at end.
+func (l *lexer) lexRawString(end, line, col int) bool {
+	hashes := 0
+	for end+hashes < len(l.src) && l.src[end+hashes] == '#' {
+		hashes++
+	}
+	if end+hashes >= len(l.src) || l.src[end+hashes] != '"' {
+		return false
+	}
+	bodyStart := end + hashes + 1
+	closer := "\"" + strings.Repeat("#", hashes)
+	stop := strings.Index(l.src[bodyStart:], closer)
+	bodyEnd := len(l.src)
+	next := len(l.src)
+	if stop >= 0 {
+		bodyEnd = bodyStart + stop
+		next = bodyEnd + len(closer)
+	}
+	text := l.src[bodyStart:bodyEnd]
+	l.advance(next - l.pos)
+	l.emit(token{kind: tokString, text: text, line: line, col: col})
+	return true
+}
+
+func (l *lexer) emit(t token) {
+	l.toks = append(l.toks, t)
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
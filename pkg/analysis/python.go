@@ -0,0 +1,195 @@
+package analysis
+
+import "strings"
+
+var pythonSyntax = syntax{
+	lineComments:   []string{"#"},
+	quotes:         `"'`,
+	triple:         true,
+	stringPrefixes: []string{"r", "b", "u", "f", "rb", "br", "fr", "rf"},
+	interp: func(_, prefix string) string {
+		if len(prefix) > 0 && (prefix[0] == 'f' || prefix[len(prefix)-1] == 'f') {
+			return "{"
+		}
+		return ""
+	},
+}
+
+// pythonReflection are the builtins that access an attribute by name.
+var pythonReflection = []string{"getattr", "setattr", "delattr"}
+
+// analyzePython resolves `import a.b as c` and `from a import b as c`
+// aliases, so c.x refers to a.b.x and c refers to a.b. Reflective
+// access with a literal name, getattr(os, "system"), refers to os.system;
+// with any other name it is a dynamic attribute.
+func analyzePython(code string) Source {
+	toks := lex(code, pythonSyntax)
+	aliases := map[string]string{}
+	skip := map[int]bool{}
+	var src Source
+
+	for i := 0; i < len(toks); i++ {
+		switch {
+		case isIdent(toks, i, "import"):
+			i = pythonImport(toks, i, aliases, skip, &src)
+		case isIdent(toks, i, "from"):
+			i = pythonFromImport(toks, i, aliases, skip, &src)
+		}
+	}
+
+	for i := range toks {
+		if toks[i].kind == tokIdent && containsString(pythonReflection, toks[i].text) &&
+			isPunct(toks, i+1, "(") && !isPunct(toks, i-1, ".") {
+			pythonReflect(toks, i, aliases, &src)
+		}
+	}
+
+	for _, c := range scanChains(toks, skip, ".") {
+		src.addRef(c, c.name(aliases))
+	}
+	return src
+}
+
+// pythonReflect handles getattr(obj, name, ..) at toks[i]. A literal
+// name is recorded as a member of obj, qualified when obj is a name.
+func pythonReflect(toks []token, i int, aliases map[string]string, src *Source) {
+	// Find the comma ending the first argument
+	comma, depth := -1, 0
+	for k := i + 2; k < len(toks) && comma < 0; k++ {
+		switch {
+		case isPunct(toks, k, "(") || isPunct(toks, k, "[") || isPunct(toks, k, "{"):
+			depth++
+		case isPunct(toks, k, ")") || isPunct(toks, k, "]") || isPunct(toks, k, "}"):
+			if depth == 0 {
+				// A single argument is not an attribute access
+				return
+			}
+			depth--
+		case isPunct(toks, k, ",") && depth == 0:
+			comma = k
+		}
+	}
+	arg := comma + 1
+	if comma < 0 || arg >= len(toks) || toks[arg].kind != tokString || toks[arg].interp ||
+		!(isPunct(toks, arg+1, ",") || isPunct(toks, arg+1, ")")) {
+		src.addDynamicAttribute(toks[i])
+		return
+	}
+
+	name := "." + toks[arg].text
+	if obj, next := dottedName(toks, i+2, "."); obj != "" && next == comma {
+		c := chain{parts: strings.Split(obj, ".")}
+		name = c.name(aliases) + name
+	}
+	src.Names = append(src.Names, Ref{Name: name, Line: toks[i].line, Column: toks[i].col})
+}
+
+// pythonImport parses `import a.b [as c], d` at toks[i].
+func pythonImport(toks []token, i int, aliases map[string]string, skip map[int]bool, src *Source) int {
+	skip[i] = true
+	i++
+	for i < len(toks) {
+		start := i
+		module, next := dottedName(toks, i, ".")
+		if module == "" {
+			return i - 1
+		}
+		for k := start; k < next; k++ {
+			skip[k] = true
+		}
+		src.addImport(module, toks[start])
+		i = next
+
+		if isIdent(toks, i, "as") && i+1 < len(toks) {
+			aliases[toks[i+1].text] = module
+			skip[i], skip[i+1] = true, true
+			i += 2
+		} else {
+			// import a.b binds a
+			root := toks[start].text
+			aliases[root] = root
+		}
+
+		if !isPunct(toks, i, ",") {
+			return i - 1
+		}
+		i++
+	}
+	return i
+}
+
+// pythonFromImport parses `from a.b import c [as d], e` at toks[i].
+func pythonFromImport(toks []token, i int, aliases map[string]string, skip map[int]bool, src *Source) int {
+	fromTok := toks[i]
+	skip[i] = true
+	i++
+
+	// Relative imports (from . import x) stay inside the job directory
+	relative := false
+	for isPunct(toks, i, ".") || isPunct(toks, i, "...") {
+		relative = true
+		skip[i] = true
+		i++
+	}
+	start := i
+	module, next := dottedName(toks, i, ".")
+	for k := start; k < next; k++ {
+		skip[k] = true
+	}
+	i = next
+	if !isIdent(toks, i, "import") {
+		return i - 1
+	}
+	skip[i] = true
+	i++
+	if !relative && module != "" {
+		src.addImport(module, fromTok)
+	}
+
+	if isPunct(toks, i, "(") {
+		skip[i] = true
+		i++
+	}
+	for i < len(toks) {
+		switch {
+		case isPunct(toks, i, "*"):
+			skip[i] = true
+			return i
+		case toks[i].kind != tokIdent:
+			if isPunct(toks, i, ")") {
+				skip[i] = true
+				return i
+			}
+			return i - 1
+		}
+
+		name := toks[i].text
+		skip[i] = true
+		target := name
+		if !relative && module != "" {
+			target = module + "." + name
+		}
+		i++
+
+		local := name
+		if isIdent(toks, i, "as") && i+1 < len(toks) {
+			local = toks[i+1].text
+			skip[i], skip[i+1] = true, true
+			i += 2
+		}
+		if !relative {
+			aliases[local] = target
+		}
+
+		if !isPunct(toks, i, ",") {
+			if isPunct(toks, i, ")") {
+				skip[i] = true
+				return i
+			}
+			return i - 1
+		}
+		skip[i] = true
+		i++
+	}
+	return i
+}
@@ -0,0 +1,52 @@
+package analysis
+
+var rubySyntax = syntax{
+	lineComments: []string{"#"},
+	quotes:       "\"'`",
+	multiline:    "\"'`",
+	interp: func(quote, _ string) string {
+		if quote != "'" {
+			return "#{"
+		}
+		return ""
+	},
+	identStart:  "$@",
+	identSuffix: "?!",
+	ruby:        true,
+}
+
+// analyzeRuby reports require / require_relative / load targets as
+// imports and shell strings (`cmd` and %x(cmd)) as calls to "`".
+// Ruby calls need no parentheses, so rules on methods should use names.
+func analyzeRuby(code string) Source {
+	toks := lex(code, rubySyntax)
+	skip := map[int]bool{}
+	var src Source
+
+	for i := 0; i < len(toks); i++ {
+		t := toks[i]
+		switch {
+		case t.kind == tokString && t.shell:
+			src.Calls = append(src.Calls, Ref{Name: "`", Line: t.line, Column: t.col})
+		case t.kind == tokIdent && (t.text == "require" || t.text == "require_relative" || t.text == "load") &&
+			!isPunct(toks, i-1, "."):
+			skip[i] = true
+			arg := i + 1
+			if isPunct(toks, arg, "(") {
+				arg++
+			}
+			if arg < len(toks) && toks[arg].kind == tokString && !toks[arg].interp {
+				if t.text == "require" {
+					src.addImport(toks[arg].text, t)
+				}
+			} else {
+				src.addDynamicImport(t)
+			}
+		}
+	}
+
+	for _, c := range scanChains(toks, skip, ".", "&.", "::") {
+		src.addRef(c, c.name(nil))
+	}
+	return src
+}
@@ -0,0 +1,101 @@
+package analysis
+
+var rustSyntax = syntax{
+	lineComments:  []string{"//"},
+	blockComments: [][2]string{{"/*", "*/"}},
+	quotes:        `"'`,
+	multiline:     `"`,
+	rust:          true,
+}
+
+// analyzeRust expands `use` trees such as use std::{fs, io::{self, Read}}
+// into imports and aliases, reports `extern crate` as an import, and
+// reports macros with their "!" (e.g. include_str!) as names.
+func analyzeRust(code string) Source {
+	toks := lex(code, rustSyntax)
+	aliases := map[string]string{}
+	skip := map[int]bool{}
+	var src Source
+
+	for i := 0; i < len(toks); i++ {
+		switch {
+		case isIdent(toks, i, "use"):
+			skip[i] = true
+			i = rustUseTree(toks, i+1, "", aliases, skip, &src)
+		case isIdent(toks, i, "extern") && isIdent(toks, i+1, "crate") && i+2 < len(toks):
+			src.addImport(toks[i+2].text, toks[i])
+			aliases[toks[i+2].text] = toks[i+2].text
+		}
+	}
+
+	for _, c := range scanChains(toks, skip, "::", ".") {
+		name := c.name(aliases)
+		if isPunct(toks, c.end, "!") && !isPunct(toks, c.end+1, "=") {
+			name += "!"
+		}
+		src.addRef(c, name)
+	}
+	return src
+}
+
+// rustUseTree parses a use tree at toks[i] below prefix and returns the
+// index of its last token.
+func rustUseTree(toks []token, i int, prefix string, aliases map[string]string, skip map[int]bool, src *Source) int {
+	path := prefix
+	for i < len(toks) {
+		skip[i] = true
+		t := toks[i]
+		switch {
+		case t.kind == tokIdent && t.text == "as" && i+1 < len(toks):
+			skip[i+1] = true
+			aliases[toks[i+1].text] = path
+			src.addImport(path, toks[i+1])
+			return i + 1
+		case t.kind == tokIdent && t.text == "self":
+			// {self, ..} names the prefix itself
+			aliases[lastSegment(path)] = path
+			src.addImport(path, t)
+		case t.kind == tokIdent:
+			path = join(path, t.text)
+			if !isPunct(toks, i+1, "::") && !isIdent(toks, i+1, "as") {
+				aliases[t.text] = path
+				src.addImport(path, t)
+				return i
+			}
+		case t.kind == tokPunct && t.text == "::":
+		case t.kind == tokPunct && t.text == "*":
+			src.addImport(path, t)
+			return i
+		case t.kind == tokPunct && t.text == "{":
+			for i++; i < len(toks) && !isPunct(toks, i, "}"); i++ {
+				if isPunct(toks, i, ",") {
+					skip[i] = true
+					continue
+				}
+				i = rustUseTree(toks, i, path, aliases, skip, src)
+			}
+			skip[i] = true
+			return i
+		default:
+			return i
+		}
+		i++
+	}
+	return i
+}
+
+func join(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
+
+func lastSegment(path string) string {
+	for i := len(path) - 1; i >= 0; i-- {
+		if path[i] == '.' {
+			return path[i+1:]
+		}
+	}
+	return path
+}
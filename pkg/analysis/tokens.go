@@ -0,0 +1,49 @@
+package analysis
+
+// TokenKind classifies a Token.
+type TokenKind int
+
+const (
+	TokenIdent  TokenKind = TokenKind(tokIdent)
+	TokenString           = TokenKind(tokString)
+	TokenNumber           = TokenKind(tokNumber)
+	TokenPunct            = TokenKind(tokPunct)
+)
+
+// Token is a lexical token of source code. Text is the identifier,
+// number or punctuation, or the contents of a string.
+type Token struct {
+	Kind TokenKind
+	Text string
+	Line int
+	Col  int
+}
+
+var syntaxes = map[string]syntax{
+	"c":          cSyntax,
+	"cpp":        cSyntax,
+	"go":         goSyntax,
+	"java":       javaSyntax,
+	"javascript": javascriptSyntax,
+	"kotlin":     kotlinSyntax,
+	"python":     pythonSyntax,
+	"ruby":       rubySyntax,
+	"rust":       rustSyntax,
+	"typescript": javascriptSyntax,
+}
+
+// Tokenize splits code into tokens with the lexer of the analyzer
+// registered under name, dropping comments and whitespace. Unknown names
+// get the C lexer, which is close enough for most curly-brace languages.
+func Tokenize(name, code string) []Token {
+	syn, ok := syntaxes[name]
+	if !ok {
+		syn = cSyntax
+	}
+	toks := lex(code, syn)
+	out := make([]Token, len(toks))
+	for i, t := range toks {
+		out[i] = Token{Kind: TokenKind(t.kind), Text: t.text, Line: t.line, Col: t.col}
+	}
+	return out
+}
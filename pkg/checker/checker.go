@@ -0,0 +1,187 @@
+// Package checker implements the built-in output checkers of Neuron.
+//
+// A checker compares the stdout of a job with the expected output and
+// returns a verdict with a short message in the style of testlib
+// ("token 3 differs: expected '4', found '5'"). Custom checkers are
+// programs and run in the sandbox instead (see sandbox.CheckOutput).
+package checker
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/anurag-327/neuron/internal/models"
+)
+
+// DefaultEpsilon is the tolerance of the float checker when none is set.
+const DefaultEpsilon = 1e-6
+
+// maxQuoted bounds the tokens and lines quoted in verdict messages.
+const maxQuoted = 64
+
+// Validate checks the type and parameters of a checker. The program of
+// a custom checker is validated by the caller like submitted code.
+func Validate(c models.Checker) error {
+	switch c.Type {
+	case models.CheckerExact, models.CheckerTokens, models.CheckerUnorderedLines:
+	case models.CheckerFloat:
+		if c.Epsilon < 0 || math.IsNaN(c.Epsilon) || math.IsInf(c.Epsilon, 0) {
+			return errors.New("checker epsilon must be a non-negative number")
+		}
+	case models.CheckerCustom:
+		if strings.TrimSpace(c.Code) == "" {
+			return errors.New("custom checker code is required")
+		}
+	default:
+		return fmt.Errorf("unknown checker type %q", c.Type)
+	}
+	return nil
+}
+
+// Compare judges output against expected with a built-in checker.
+func Compare(c models.Checker, output, expected string) (models.Verdict, string) {
+	switch c.Type {
+	case models.CheckerExact:
+		return compareExact(output, expected)
+	case models.CheckerTokens:
+		return compareTokens(output, expected, nil)
+	case models.CheckerFloat:
+		eps := c.Epsilon
+		if eps == 0 {
+			eps = DefaultEpsilon
+		}
+		return compareTokens(output, expected, func(found, want string) bool {
+			return floatsEqual(found, want, eps)
+		})
+	case models.CheckerUnorderedLines:
+		return compareUnorderedLines(output, expected)
+	}
+	return models.VerdictCheckerFailed, fmt.Sprintf("checker %q is not built in", c.Type)
+}
+
+// compareExact compares line by line, ignoring trailing whitespace on
+// each line and trailing blank lines. Output with the right tokens in
+// the wrong layout is a presentation error.
+func compareExact(output, expected string) (models.Verdict, string) {
+	found, want := lines(output), lines(expected)
+	for i := 0; i < len(found) && i < len(want); i++ {
+		if found[i] != want[i] {
+			return layoutVerdict(output, expected, fmt.Sprintf("line %d differs: expected %s, found %s", i+1, quote(want[i]), quote(found[i])))
+		}
+	}
+	if len(found) != len(want) {
+		return layoutVerdict(output, expected, fmt.Sprintf("expected %d lines, found %d", len(want), len(found)))
+	}
+	return models.VerdictAccepted, fmt.Sprintf("%d line(s)", len(want))
+}
+
+func layoutVerdict(output, expected, message string) (models.Verdict, string) {
+	if v, _ := compareTokens(output, expected, nil); v == models.VerdictAccepted {
+		return models.VerdictPresentationError, message
+	}
+	return models.VerdictWrongAnswer, message
+}
+
+// compareTokens compares whitespace-separated tokens, with equal
+// deciding whether two tokens match (string equality when nil).
+func compareTokens(output, expected string, equal func(found, want string) bool) (models.Verdict, string) {
+	found, want := strings.Fields(output), strings.Fields(expected)
+	for i := 0; i < len(found) && i < len(want); i++ {
+		ok := found[i] == want[i]
+		if !ok && equal != nil {
+			ok = equal(found[i], want[i])
+		}
+		if !ok {
+			return models.VerdictWrongAnswer, fmt.Sprintf("token %d differs: expected %s, found %s", i+1, quote(want[i]), quote(found[i]))
+		}
+	}
+	if len(found) != len(want) {
+		return models.VerdictWrongAnswer, fmt.Sprintf("expected %d tokens, found %d", len(want), len(found))
+	}
+	return models.VerdictAccepted, fmt.Sprintf("%d token(s)", len(want))
+}
+
+// floatsEqual reports whether two tokens are numbers within eps of each
+// other, absolutely or relative to the expected value.
+func floatsEqual(found, want string, eps float64) bool {
+	f, err := strconv.ParseFloat(found, 64)
+	if err != nil {
+		return false
+	}
+	w, err := strconv.ParseFloat(want, 64)
+	if err != nil {
+		return false
+	}
+	if math.IsNaN(w) || math.IsInf(w, 0) {
+		return math.IsNaN(f) == math.IsNaN(w) && (math.IsNaN(w) || f == w)
+	}
+	diff := math.Abs(f - w)
+	return diff <= eps || diff <= eps*math.Abs(w)
+}
+
+// compareUnorderedLines accepts the expected lines in any order.
+func compareUnorderedLines(output, expected string) (models.Verdict, string) {
+	found, want := nonEmpty(lines(output)), nonEmpty(lines(expected))
+
+	counts := make(map[string]int, len(want))
+	for _, l := range want {
+		counts[l]++
+	}
+	for _, l := range found {
+		counts[l]--
+	}
+
+	var missing, extra []string
+	for l, n := range counts {
+		switch {
+		case n > 0:
+			missing = append(missing, l)
+		case n < 0:
+			extra = append(extra, l)
+		}
+	}
+	sort.Strings(missing)
+	sort.Strings(extra)
+
+	switch {
+	case len(missing) > 0:
+		return models.VerdictWrongAnswer, fmt.Sprintf("expected line %s not found", quote(missing[0]))
+	case len(extra) > 0:
+		return models.VerdictWrongAnswer, fmt.Sprintf("unexpected line %s", quote(extra[0]))
+	}
+	return models.VerdictAccepted, fmt.Sprintf("%d line(s)", len(want))
+}
+
+// lines splits s into lines without trailing whitespace, dropping
+// trailing blank lines.
+func lines(s string) []string {
+	ls := strings.Split(s, "\n")
+	for i, l := range ls {
+		ls[i] = strings.TrimRight(l, " \t\r")
+	}
+	for len(ls) > 0 && ls[len(ls)-1] == "" {
+		ls = ls[:len(ls)-1]
+	}
+	return ls
+}
+
+func nonEmpty(ls []string) []string {
+	out := ls[:0:0]
+	for _, l := range ls {
+		if l != "" {
+			out = append(out, l)
+		}
+	}
+	return out
+}
+
+func quote(s string) string {
+	if len(s) > maxQuoted {
+		s = s[:maxQuoted] + "..."
+	}
+	return "'" + s + "'"
+}
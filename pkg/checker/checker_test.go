@@ -0,0 +1,98 @@
+package checker
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/anurag-327/neuron/internal/models"
+)
+
+func TestCompare(t *testing.T) {
+	exact := models.Checker{Type: models.CheckerExact}
+	tokens := models.Checker{Type: models.CheckerTokens}
+	float := models.Checker{Type: models.CheckerFloat}
+	unordered := models.Checker{Type: models.CheckerUnorderedLines}
+
+	tests := []struct {
+		name     string
+		checker  models.Checker
+		output   string
+		expected string
+		want     models.Verdict
+		message  string // substring of the message
+	}{
+		{"exact equal", exact, "1 2\n3\n", "1 2\n3\n", models.VerdictAccepted, "2 line(s)"},
+		{"exact trailing whitespace", exact, "1 2  \r\n3\t\n\n\n", "1 2\n3", models.VerdictAccepted, ""},
+		{"exact different line", exact, "1 2\n4\n", "1 2\n3\n", models.VerdictWrongAnswer, "line 2 differs: expected '3', found '4'"},
+		{"exact missing line", exact, "1 2\n", "1 2\n3\n", models.VerdictWrongAnswer, "expected 2 lines, found 1"},
+		{"exact other layout", exact, "1\n2\n3\n", "1 2\n3\n", models.VerdictPresentationError, "line 1 differs"},
+		{"exact leading space", exact, " 1 2\n", "1 2\n", models.VerdictPresentationError, ""},
+		{"exact empty", exact, "", "\n", models.VerdictAccepted, "0 line(s)"},
+
+		{"tokens any layout", tokens, "1\n\n2   3", "1 2 3\n", models.VerdictAccepted, "3 token(s)"},
+		{"tokens differ", tokens, "1 2 4", "1 2 3", models.VerdictWrongAnswer, "token 3 differs: expected '3', found '4'"},
+		{"tokens extra", tokens, "1 2 3 4", "1 2 3", models.VerdictWrongAnswer, "expected 3 tokens, found 4"},
+		{"tokens are not numbers", tokens, "1.0", "1", models.VerdictWrongAnswer, ""},
+
+		{"float within absolute epsilon", float, "0.3000001", "0.3", models.VerdictAccepted, ""},
+		{"float within relative epsilon", float, "1000000.5", "1000000", models.VerdictAccepted, ""},
+		{"float outside epsilon", float, "0.31", "0.3", models.VerdictWrongAnswer, "token 1 differs"},
+		{"float custom epsilon", models.Checker{Type: models.CheckerFloat, Epsilon: 0.1}, "0.35", "0.3", models.VerdictAccepted, ""},
+		{"float words must be equal", float, "yes 1.0", "YES 1", models.VerdictWrongAnswer, "expected 'YES', found 'yes'"},
+		{"float nan", float, "nan", "NaN", models.VerdictAccepted, ""},
+		{"float infinity", float, "+Infinity", "inf", models.VerdictAccepted, ""},
+		{"float not a number", float, "x", "1", models.VerdictWrongAnswer, ""},
+
+		{"unordered any order", unordered, "b\na\n\nc\n", "a\nb\nc\n", models.VerdictAccepted, "3 line(s)"},
+		{"unordered missing", unordered, "a\nb\n", "a\nb\nc\n", models.VerdictWrongAnswer, "expected line 'c' not found"},
+		{"unordered extra", unordered, "a\nb\nb\n", "a\nb\n", models.VerdictWrongAnswer, "unexpected line 'b'"},
+
+		{"custom is not built in", models.Checker{Type: models.CheckerCustom, Code: "x"}, "", "", models.VerdictCheckerFailed, "not built in"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, message := Compare(tt.checker, tt.output, tt.expected)
+			if got != tt.want {
+				t.Errorf("verdict = %s (%s), want %s", got, message, tt.want)
+			}
+			if !strings.Contains(message, tt.message) {
+				t.Errorf("message = %q, want it to contain %q", message, tt.message)
+			}
+		})
+	}
+}
+
+func TestCompareQuotesLongTokens(t *testing.T) {
+	long := strings.Repeat("x", 1000)
+	_, message := Compare(models.Checker{Type: models.CheckerTokens}, long, "y")
+	if len(message) > 2*maxQuoted+64 {
+		t.Errorf("message of %d bytes quotes the whole token", len(message))
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		checker models.Checker
+		wantErr bool
+	}{
+		{"exact", models.Checker{Type: models.CheckerExact}, false},
+		{"tokens", models.Checker{Type: models.CheckerTokens}, false},
+		{"unordered lines", models.Checker{Type: models.CheckerUnorderedLines}, false},
+		{"float", models.Checker{Type: models.CheckerFloat, Epsilon: 1e-3}, false},
+		{"float negative epsilon", models.Checker{Type: models.CheckerFloat, Epsilon: -1}, true},
+		{"float nan epsilon", models.Checker{Type: models.CheckerFloat, Epsilon: math.NaN()}, true},
+		{"custom", models.Checker{Type: models.CheckerCustom, Code: "int main() {}"}, false},
+		{"custom without code", models.Checker{Type: models.CheckerCustom, Code: "  \n"}, true},
+		{"unknown", models.Checker{Type: "regex"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Validate(tt.checker); (err != nil) != tt.wantErr {
+				t.Errorf("Validate = %v, want error: %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+// Package diagnostics turns compiler and runtime output into structured
+// diagnostics (file, line, column, severity, message and stack frames).
+//
+// Each parser understands one output format and ignores lines it does
+// not recognise, so several parsers can read the same stderr, e.g. tsc
+// for compile errors and node for uncaught exceptions.
+package diagnostics
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/anurag-327/neuron/internal/models"
+)
+
+// MaxDiagnostics bounds the diagnostics kept from a single run.
+const MaxDiagnostics = 100
+
+// Parser extracts diagnostics from the output of a compiler or runtime.
+type Parser func(output string) []models.Diagnostic
+
+// parsers maps format names used in language definitions to parsers.
+var parsers = map[string]Parser{
+	"gnu":    parseGNU,
+	"go":     parseGo,
+	"javac":  parseJavac,
+	"jvm":    parseJVM,
+	"node":   parseNode,
+	"python": parsePython,
+	"tsc":    parseTSC,
+}
+
+// Get returns the parser for a format name.
+func Get(name string) (Parser, bool) {
+	p, ok := parsers[name]
+	return p, ok
+}
+
+// Names returns the known format names, sorted.
+func Names() []string {
+	names := make([]string, 0, len(parsers))
+	for name := range parsers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Parse runs every parser over output and returns at most
+// MaxDiagnostics diagnostics, in parser order.
+func Parse(output string, ps []Parser) []models.Diagnostic {
+	if strings.TrimSpace(output) == "" {
+		return nil
+	}
+
+	var diags []models.Diagnostic
+	for _, p := range ps {
+		diags = append(diags, p(output)...)
+		if len(diags) >= MaxDiagnostics {
+			return diags[:MaxDiagnostics]
+		}
+	}
+	return diags
+}
+
+// lines splits output into lines without trailing carriage returns.
+func lines(output string) []string {
+	ls := strings.Split(output, "\n")
+	for i, l := range ls {
+		ls[i] = strings.TrimRight(l, "\r")
+	}
+	return ls
+}
+
+// relFile makes paths of the job directory relative ("./main.go" and
+// "main.go" both become "main.go").
+func relFile(file string) string {
+	return strings.TrimPrefix(file, "./")
+}
+
+func atoi(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
+
+// caretColumn returns the 1-based column of the first "^" when line is
+// a caret marker (spaces, "^" and "~" only), and 0 otherwise.
+func caretColumn(line string) int {
+	if strings.Trim(line, " \t^~") != "" {
+		return 0
+	}
+	return strings.Index(line, "^") + 1
+}
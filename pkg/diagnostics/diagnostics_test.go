@@ -0,0 +1,289 @@
+package diagnostics
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/anurag-327/neuron/internal/models"
+)
+
+func TestParsers(t *testing.T) {
+	tests := []struct {
+		name   string
+		parser string
+		output string
+		want   []models.Diagnostic
+	}{
+		{
+			name:   "gcc error with column",
+			parser: "gnu",
+			output: `./main.c: In function 'main':
+./main.c:4:5: error: 'x' undeclared (first use in this function)
+    4 |     x = 1;
+      |     ^
+./main.c:2:1: warning: return type defaults to 'int'
+`,
+			want: []models.Diagnostic{
+				{Severity: models.SeverityError, Message: "'x' undeclared (first use in this function)", File: "main.c", Line: 4, Column: 5},
+				{Severity: models.SeverityWarning, Message: "return type defaults to 'int'", File: "main.c", Line: 2, Column: 1},
+			},
+		},
+		{
+			name:   "kotlinc error without column",
+			parser: "gnu",
+			output: "Main.kt:3: error: unresolved reference: foo\n",
+			want: []models.Diagnostic{
+				{Severity: models.SeverityError, Message: "unresolved reference: foo", File: "Main.kt", Line: 3},
+			},
+		},
+		{
+			name:   "linker undefined reference",
+			parser: "gnu",
+			output: "main.c:(.text+0x5): undefined reference to `solve'\ncollect2: error: ld returned 1 exit status\n",
+			want: []models.Diagnostic{
+				{Severity: models.SeverityError, Message: "undefined reference to `solve'", File: "main.c"},
+			},
+		},
+		{
+			name:   "go build error",
+			parser: "go",
+			output: "# command-line-arguments\n./main.go:5:2: undefined: fmt.Printn\n",
+			want: []models.Diagnostic{
+				{Severity: models.SeverityError, Message: "undefined: fmt.Printn", File: "main.go", Line: 5, Column: 2},
+			},
+		},
+		{
+			name:   "go panic",
+			parser: "go",
+			output: `panic: runtime error: integer divide by zero
+
+goroutine 1 [running]:
+main.divide(...)
+	/sandbox/job_1/main.go:6
+main.main()
+	/sandbox/job_1/main.go:10 +0x1d
+exit status 2
+`,
+			want: []models.Diagnostic{{
+				Severity: models.SeverityError,
+				Code:     "panic",
+				Message:  "runtime error: integer divide by zero",
+				File:     "/sandbox/job_1/main.go",
+				Line:     6,
+				Frames: []models.StackFrame{
+					{Function: "main.divide", File: "/sandbox/job_1/main.go", Line: 6},
+					{Function: "main.main", File: "/sandbox/job_1/main.go", Line: 10},
+				},
+			}},
+		},
+		{
+			name:   "javac error with caret and details",
+			parser: "javac",
+			output: `Main.java:3: error: cannot find symbol
+        System.out.printn("hi");
+                  ^
+  symbol:   method printn(String)
+  location: variable out of type PrintStream
+1 error
+`,
+			want: []models.Diagnostic{{
+				Severity: models.SeverityError,
+				Message:  "cannot find symbol\nsymbol:   method printn(String)\nlocation: variable out of type PrintStream",
+				File:     "Main.java",
+				Line:     3,
+				Column:   19,
+			}},
+		},
+		{
+			name:   "jvm exception",
+			parser: "jvm",
+			output: `Exception in thread "main" java.lang.ArithmeticException: / by zero
+	at Main.divide(Main.java:4)
+	at Main.main(Main.java:8)
+`,
+			want: []models.Diagnostic{{
+				Severity: models.SeverityError,
+				Code:     "java.lang.ArithmeticException",
+				Message:  "/ by zero",
+				File:     "Main.java",
+				Line:     4,
+				Frames: []models.StackFrame{
+					{Function: "Main.divide", File: "Main.java", Line: 4},
+					{Function: "Main.main", File: "Main.java", Line: 8},
+				},
+			}},
+		},
+		{
+			name:   "jvm exception thrown in the jdk",
+			parser: "jvm",
+			output: `Exception in thread "main" java.lang.NullPointerException
+	at java.base/java.util.Objects.requireNonNull(Objects.java:233)
+	at Main.main(Main.java:5)
+`,
+			want: []models.Diagnostic{{
+				Severity: models.SeverityError,
+				Code:     "java.lang.NullPointerException",
+				Message:  "java.lang.NullPointerException",
+				File:     "Main.java",
+				Line:     5,
+				Frames: []models.StackFrame{
+					{Function: "java.base/java.util.Objects.requireNonNull", File: "Objects.java", Line: 233},
+					{Function: "Main.main", File: "Main.java", Line: 5},
+				},
+			}},
+		},
+		{
+			name:   "node uncaught error",
+			parser: "node",
+			output: `/sandbox/job_1/main.js:2
+  throw new TypeError("bad input");
+  ^
+
+TypeError: bad input
+    at parse (/sandbox/job_1/main.js:2:9)
+    at Object.<anonymous> (/sandbox/job_1/main.js:5:1)
+    at node:internal/main/run_main_module:23:47
+`,
+			want: []models.Diagnostic{{
+				Severity: models.SeverityError,
+				Code:     "TypeError",
+				Message:  "bad input",
+				File:     "/sandbox/job_1/main.js",
+				Line:     2,
+				Column:   3,
+				Frames: []models.StackFrame{
+					{Function: "parse", File: "/sandbox/job_1/main.js", Line: 2, Column: 9},
+					{Function: "Object.<anonymous>", File: "/sandbox/job_1/main.js", Line: 5, Column: 1},
+					{File: "node:internal/main/run_main_module", Line: 23, Column: 47},
+				},
+			}},
+		},
+		{
+			name:   "node output that looks like an error",
+			parser: "node",
+			output: "Error: not really\n",
+		},
+		{
+			name:   "python traceback",
+			parser: "python",
+			output: `Traceback (most recent call last):
+  File "/sandbox/job_1/main.py", line 5, in <module>
+    print(divide(1, 0))
+  File "/sandbox/job_1/main.py", line 2, in divide
+    return a / b
+ZeroDivisionError: division by zero
+`,
+			want: []models.Diagnostic{{
+				Severity: models.SeverityError,
+				Code:     "ZeroDivisionError",
+				Message:  "division by zero",
+				File:     "/sandbox/job_1/main.py",
+				Line:     2,
+				Frames: []models.StackFrame{
+					{Function: "divide", File: "/sandbox/job_1/main.py", Line: 2},
+					{Function: "<module>", File: "/sandbox/job_1/main.py", Line: 5},
+				},
+			}},
+		},
+		{
+			name:   "python chained exceptions report the last",
+			parser: "python",
+			output: `Traceback (most recent call last):
+  File "main.py", line 2, in <module>
+    int("x")
+ValueError: invalid literal for int() with base 10: 'x'
+
+During handling of the above exception, another exception occurred:
+
+Traceback (most recent call last):
+  File "main.py", line 4, in <module>
+    raise KeyError("k")
+KeyError: 'k'
+`,
+			want: []models.Diagnostic{{
+				Severity: models.SeverityError,
+				Code:     "KeyError",
+				Message:  "'k'",
+				File:     "main.py",
+				Line:     4,
+				Frames:   []models.StackFrame{{Function: "<module>", File: "main.py", Line: 4}},
+			}},
+		},
+		{
+			name:   "python syntax error",
+			parser: "python",
+			output: `  File "/sandbox/job_1/main.py", line 1
+    print("hi"
+         ^
+SyntaxError: '(' was never closed
+`,
+			want: []models.Diagnostic{{
+				Severity: models.SeverityError,
+				Code:     "SyntaxError",
+				Message:  "'(' was never closed",
+				File:     "/sandbox/job_1/main.py",
+				Line:     1,
+			}},
+		},
+		{
+			name:   "tsc errors with elaboration",
+			parser: "tsc",
+			output: `main.ts(3,7): error TS2322: Type 'string' is not assignable to type 'number'.
+main.ts(9,1): error TS2345: Argument of type '{}' is not assignable to parameter of type 'Opts'.
+  Property 'n' is missing in type '{}' but required in type 'Opts'.
+`,
+			want: []models.Diagnostic{
+				{Severity: models.SeverityError, Code: "TS2322", Message: "Type 'string' is not assignable to type 'number'.", File: "main.ts", Line: 3, Column: 7},
+				{
+					Severity: models.SeverityError,
+					Code:     "TS2345",
+					Message:  "Argument of type '{}' is not assignable to parameter of type 'Opts'.\nProperty 'n' is missing in type '{}' but required in type 'Opts'.",
+					File:     "main.ts",
+					Line:     9,
+					Column:   1,
+				},
+			},
+		},
+		{
+			name:   "no diagnostics in program output",
+			parser: "gnu",
+			output: "hello: world\n42\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parse, ok := Get(tt.parser)
+			if !ok {
+				t.Fatalf("no parser %q", tt.parser)
+			}
+			got := parse(tt.output)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got  %+v\nwant %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseLimits(t *testing.T) {
+	gnu, _ := Get("gnu")
+	output := strings.Repeat("main.c:1:1: error: x\n", MaxDiagnostics+10)
+	if got := Parse(output, []Parser{gnu, gnu}); len(got) != MaxDiagnostics {
+		t.Errorf("got %d diagnostics, want %d", len(got), MaxDiagnostics)
+	}
+	if got := Parse(" \n", []Parser{gnu}); got != nil {
+		t.Errorf("blank output has diagnostics: %+v", got)
+	}
+
+	jvm, _ := Get("jvm")
+	output = "Exception in thread \"main\" java.lang.StackOverflowError\n" +
+		strings.Repeat("\tat Main.f(Main.java:3)\n", MaxFrames*4)
+	got := jvm(output)
+	if len(got) != 1 {
+		t.Fatalf("got %d diagnostics, want 1", len(got))
+	}
+	if len(got[0].Frames) != MaxFrames {
+		t.Errorf("stack overflow keeps %d frames, want %d", len(got[0].Frames), MaxFrames)
+	}
+}
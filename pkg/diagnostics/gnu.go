@@ -0,0 +1,50 @@
+package diagnostics
+
+import (
+	"regexp"
+
+	"github.com/anurag-327/neuron/internal/models"
+)
+
+// gnuRe matches the GNU message format used by gcc, g++ and kotlinc:
+// file:line[:column]: severity: message.
+var gnuRe = regexp.MustCompile(`^(.+?):(\d+):(?:(\d+):)? (fatal error|error|warning|note): (.*)$`)
+
+// linkRe matches ld errors such as main.c:(.text+0x5): undefined reference to `f'.
+var linkRe = regexp.MustCompile(`^(?:(.+?):\(\S+\): )?(undefined reference to .*)$`)
+
+// parseGNU reads compiler messages in the GNU format and undefined
+// references reported by the linker.
+func parseGNU(output string) []models.Diagnostic {
+	var diags []models.Diagnostic
+	for _, l := range lines(output) {
+		if m := gnuRe.FindStringSubmatch(l); m != nil {
+			diags = append(diags, models.Diagnostic{
+				Severity: gnuSeverity(m[4]),
+				Message:  m[5],
+				File:     relFile(m[1]),
+				Line:     atoi(m[2]),
+				Column:   atoi(m[3]),
+			})
+			continue
+		}
+		if m := linkRe.FindStringSubmatch(l); m != nil {
+			diags = append(diags, models.Diagnostic{
+				Severity: models.SeverityError,
+				Message:  m[2],
+				File:     relFile(m[1]),
+			})
+		}
+	}
+	return diags
+}
+
+func gnuSeverity(s string) models.DiagnosticSeverity {
+	switch s {
+	case "warning":
+		return models.SeverityWarning
+	case "note":
+		return models.SeverityNote
+	}
+	return models.SeverityError
+}
@@ -0,0 +1,83 @@
+package diagnostics
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/anurag-327/neuron/internal/models"
+)
+
+var (
+	goCompileRe = regexp.MustCompile(`^(.+\.go):(\d+):(\d+): (.*)$`)
+	goPanicRe   = regexp.MustCompile(`^(panic|fatal error): (.*)$`)
+	goFileRe    = regexp.MustCompile(`^\t(.+?):(\d+)(?: \+0x[0-9a-f]+)?$`)
+)
+
+// parseGo reads go build errors and panics. The stack of the first
+// goroutine in a panic is reported, and its location is the innermost
+// frame outside the runtime.
+func parseGo(output string) []models.Diagnostic {
+	var diags []models.Diagnostic
+	ls := lines(output)
+
+	for i := 0; i < len(ls); i++ {
+		if m := goCompileRe.FindStringSubmatch(ls[i]); m != nil {
+			diags = append(diags, models.Diagnostic{
+				Severity: models.SeverityError,
+				Message:  m[4],
+				File:     relFile(m[1]),
+				Line:     atoi(m[2]),
+				Column:   atoi(m[3]),
+			})
+			continue
+		}
+
+		m := goPanicRe.FindStringSubmatch(ls[i])
+		if m == nil {
+			continue
+		}
+		d := models.Diagnostic{
+			Severity: models.SeverityError,
+			Code:     m[1],
+			Message:  strings.TrimSuffix(m[2], " [recovered]"),
+		}
+
+		// Skip to the first goroutine, then read function / file pairs
+		// until the blank line closing it.
+		i++
+		for i < len(ls) && !strings.HasPrefix(ls[i], "goroutine ") {
+			i++
+		}
+		for i++; i+1 < len(ls) && ls[i] != ""; i += 2 {
+			f := goFileRe.FindStringSubmatch(ls[i+1])
+			if f == nil {
+				break
+			}
+			frame := models.StackFrame{
+				Function: goFunction(ls[i]),
+				File:     relFile(f[1]),
+				Line:     atoi(f[2]),
+			}
+			if d.File == "" && !strings.HasPrefix(frame.Function, "runtime.") && frame.Function != "panic" {
+				d.File, d.Line = frame.File, frame.Line
+			}
+			if len(d.Frames) < MaxFrames {
+				d.Frames = append(d.Frames, frame)
+			}
+		}
+
+		diags = append(diags, d)
+	}
+	return diags
+}
+
+// goFunction drops the argument list of a traceback function line,
+// e.g. "main.divide(0x1, 0x0)" becomes "main.divide".
+func goFunction(line string) string {
+	if strings.HasSuffix(line, ")") {
+		if i := strings.LastIndex(line, "("); i > 0 {
+			return line[:i]
+		}
+	}
+	return line
+}
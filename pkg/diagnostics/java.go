@@ -0,0 +1,101 @@
+package diagnostics
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/anurag-327/neuron/internal/models"
+)
+
+// MaxFrames bounds the stack frames kept per runtime error; a stack
+// overflow prints thousands.
+const MaxFrames = 50
+
+var (
+	javacRe     = regexp.MustCompile(`^(.+\.java):(\d+): (error|warning): (.*)$`)
+	jvmHeaderRe = regexp.MustCompile(`^Exception in thread "[^"]*" ([\w$.]+)(?:: (.*))?$`)
+	jvmFrameRe  = regexp.MustCompile(`^\s+at (\S+?)\((.*)\)$`)
+)
+
+// parseJavac reads javac errors. javac prints the source line and a
+// caret below the message, then indented details (symbol, location)
+// that are appended to the message.
+func parseJavac(output string) []models.Diagnostic {
+	var diags []models.Diagnostic
+	ls := lines(output)
+
+	for i := 0; i < len(ls); i++ {
+		m := javacRe.FindStringSubmatch(ls[i])
+		if m == nil {
+			continue
+		}
+		d := models.Diagnostic{
+			Severity: gnuSeverity(m[3]),
+			Message:  m[4],
+			File:     relFile(m[1]),
+			Line:     atoi(m[2]),
+		}
+
+		j := i + 1
+		if j+1 < len(ls) {
+			if col := caretColumn(ls[j+1]); col > 0 {
+				d.Column = col
+				j += 2
+			}
+		}
+		for ; j < len(ls) && strings.HasPrefix(ls[j], "  ") && !javacRe.MatchString(ls[j]); j++ {
+			d.Message += "\n" + strings.TrimSpace(ls[j])
+		}
+		i = j - 1
+
+		diags = append(diags, d)
+	}
+	return diags
+}
+
+// parseJVM reads uncaught exceptions of Java and Kotlin programs. The
+// location is the innermost frame of user code; JDK frames carry a
+// module prefix such as java.base/.
+func parseJVM(output string) []models.Diagnostic {
+	var diags []models.Diagnostic
+	ls := lines(output)
+
+	for i := 0; i < len(ls); i++ {
+		m := jvmHeaderRe.FindStringSubmatch(ls[i])
+		if m == nil {
+			continue
+		}
+		d := models.Diagnostic{
+			Severity: models.SeverityError,
+			Code:     m[1],
+			Message:  m[2],
+		}
+
+		for i++; i < len(ls); i++ {
+			f := jvmFrameRe.FindStringSubmatch(ls[i])
+			if f == nil {
+				break
+			}
+			frame := models.StackFrame{Function: f[1]}
+			if file, line, ok := strings.Cut(f[2], ":"); ok {
+				frame.File, frame.Line = file, atoi(line)
+			} else if !strings.Contains(f[2], " ") {
+				// "Native Method" and "Unknown Source" name no file
+				frame.File = f[2]
+			}
+			if d.File == "" && frame.File != "" && !strings.Contains(frame.Function, "/") {
+				d.File, d.Line = frame.File, frame.Line
+			}
+			if len(d.Frames) < MaxFrames {
+				d.Frames = append(d.Frames, frame)
+			}
+		}
+		i--
+
+		if d.Message == "" {
+			d.Message = d.Code
+		}
+		diags = append(diags, d)
+	}
+	return diags
+}
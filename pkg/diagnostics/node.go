@@ -0,0 +1,79 @@
+package diagnostics
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/anurag-327/neuron/internal/models"
+)
+
+var (
+	// nodeLocRe matches the "file:line" node prints above the source
+	// line and caret of the throwing statement.
+	nodeLocRe    = regexp.MustCompile(`^(.+\.[cm]?js):(\d+)$`)
+	nodeHeaderRe = regexp.MustCompile(`^([A-Z][\w$]*(?:Error|Exception)|Error)(?: \[\w+\])?(?:: (.*))?$`)
+	nodeFrameRe  = regexp.MustCompile(`^\s+at (?:(.+?) \()?(.+?):(\d+):(\d+)\)?$`)
+)
+
+// parseNode reads uncaught errors of node. The location is taken from
+// the source excerpt node prints first, or else from the innermost
+// frame outside node's own modules.
+func parseNode(output string) []models.Diagnostic {
+	var diags []models.Diagnostic
+	var loc models.Diagnostic
+	ls := lines(output)
+
+	for i := 0; i < len(ls); i++ {
+		if m := nodeLocRe.FindStringSubmatch(ls[i]); m != nil {
+			loc = models.Diagnostic{File: relFile(m[1]), Line: atoi(m[2])}
+			if i+2 < len(ls) {
+				loc.Column = caretColumn(ls[i+2])
+			}
+			continue
+		}
+
+		m := nodeHeaderRe.FindStringSubmatch(ls[i])
+		if m == nil {
+			continue
+		}
+		d := models.Diagnostic{
+			Severity: models.SeverityError,
+			Code:     m[1],
+			Message:  m[2],
+			File:     loc.File,
+			Line:     loc.Line,
+			Column:   loc.Column,
+		}
+		loc = models.Diagnostic{}
+
+		for i++; i < len(ls); i++ {
+			f := nodeFrameRe.FindStringSubmatch(ls[i])
+			if f == nil {
+				break
+			}
+			frame := models.StackFrame{
+				Function: f[1],
+				File:     relFile(f[2]),
+				Line:     atoi(f[3]),
+				Column:   atoi(f[4]),
+			}
+			if d.File == "" && !strings.HasPrefix(frame.File, "node:") {
+				d.File, d.Line, d.Column = frame.File, frame.Line, frame.Column
+			}
+			if len(d.Frames) < MaxFrames {
+				d.Frames = append(d.Frames, frame)
+			}
+		}
+		i--
+
+		if d.File == "" && len(d.Frames) == 0 {
+			// A line that merely looks like an error, e.g. program output
+			continue
+		}
+		if d.Message == "" {
+			d.Message = d.Code
+		}
+		diags = append(diags, d)
+	}
+	return diags
+}
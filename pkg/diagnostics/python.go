@@ -0,0 +1,72 @@
+package diagnostics
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/anurag-327/neuron/internal/models"
+)
+
+var (
+	pyFrameRe = regexp.MustCompile(`^\s*File "(.+?)", line (\d+)(?:, in (.+))?$`)
+	pyErrorRe = regexp.MustCompile(`^([A-Za-z_][\w.]*)(?:: (.*))?$`)
+)
+
+// parsePython reads Python tracebacks and syntax errors. Only the last
+// exception is reported: earlier tracebacks of a chain ("During
+// handling of the above exception ...") lead up to it. Frames are
+// reversed so that the innermost call comes first.
+func parsePython(output string) []models.Diagnostic {
+	var frames []models.StackFrame
+	var last *models.Diagnostic
+	ls := lines(output)
+
+	for i := 0; i < len(ls); i++ {
+		l := ls[i]
+		if strings.HasPrefix(l, "Traceback (most recent call last):") {
+			frames = nil
+			continue
+		}
+		if m := pyFrameRe.FindStringSubmatch(l); m != nil {
+			frames = append(frames, models.StackFrame{
+				Function: m[3],
+				File:     relFile(m[1]),
+				Line:     atoi(m[2]),
+			})
+			continue
+		}
+		if len(frames) == 0 || strings.HasPrefix(l, " ") || l == "" {
+			continue
+		}
+
+		m := pyErrorRe.FindStringSubmatch(l)
+		if m == nil {
+			frames = nil
+			continue
+		}
+		d := models.Diagnostic{
+			Severity: models.SeverityError,
+			Code:     m[1],
+			Message:  m[2],
+		}
+		if d.Message == "" {
+			d.Message = d.Code
+		}
+		for j := len(frames) - 1; j >= 0 && len(d.Frames) < MaxFrames; j-- {
+			d.Frames = append(d.Frames, frames[j])
+		}
+		inner := frames[len(frames)-1]
+		d.File, d.Line = inner.File, inner.Line
+		if inner.Function == "" {
+			// A syntax error: the frame is the offending line, not a call
+			d.Frames = nil
+		}
+		last = &d
+		frames = nil
+	}
+
+	if last == nil {
+		return nil
+	}
+	return []models.Diagnostic{*last}
+}
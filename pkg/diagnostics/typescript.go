@@ -0,0 +1,45 @@
+package diagnostics
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/anurag-327/neuron/internal/models"
+)
+
+// tscRe matches tsc output with --pretty false, file(line,column):
+// severity TSnnnn: message. Elaborations follow on indented lines.
+var tscRe = regexp.MustCompile(`^(.+?)\((\d+),(\d+)\): (error|warning|message) (TS\d+): (.*)$`)
+
+// parseTSC reads TypeScript compiler errors.
+func parseTSC(output string) []models.Diagnostic {
+	var diags []models.Diagnostic
+
+	for _, l := range lines(output) {
+		if m := tscRe.FindStringSubmatch(l); m != nil {
+			diags = append(diags, models.Diagnostic{
+				Severity: tscSeverity(m[4]),
+				Code:     m[5],
+				Message:  m[6],
+				File:     relFile(m[1]),
+				Line:     atoi(m[2]),
+				Column:   atoi(m[3]),
+			})
+			continue
+		}
+		if n := len(diags); n > 0 && strings.HasPrefix(l, "  ") && strings.TrimSpace(l) != "" {
+			diags[n-1].Message += "\n" + strings.TrimSpace(l)
+		}
+	}
+	return diags
+}
+
+func tscSeverity(s string) models.DiagnosticSeverity {
+	switch s {
+	case "warning":
+		return models.SeverityWarning
+	case "message":
+		return models.SeverityNote
+	}
+	return models.SeverityError
+}
@@ -0,0 +1,92 @@
+package sandbox
+
+import (
+	"fmt"
+	"io"
+	"path"
+	"strings"
+)
+
+// Artifact limits. Files over MaxArtifactSize, or past the count or
+// total size limits, are skipped and counted in RunResult.ArtifactsSkipped.
+const (
+	MaxArtifactGlobs            = 10
+	MaxArtifacts                = 20
+	MaxArtifactSize       int64 = 5 * 1024 * 1024  // 5MB
+	MaxArtifactsTotalSize int64 = 20 * 1024 * 1024 // 20MB
+)
+
+// Artifact is a file a run left in its job directory that matched one
+// of the artifact globs of the submission.
+type Artifact struct {
+	// Name is the path relative to the job directory, e.g. "out/plot.png".
+	Name    string
+	Content []byte
+}
+
+// ValidateArtifactGlobs checks the artifact globs of a submission.
+// Globs use path.Match syntax and are matched against paths relative to
+// the job directory ("*.png" does not match "out/plot.png").
+func ValidateArtifactGlobs(globs []string) error {
+	if len(globs) > MaxArtifactGlobs {
+		return fmt.Errorf("at most %d artifact globs are allowed", MaxArtifactGlobs)
+	}
+	for _, g := range globs {
+		switch {
+		case g == "" || len(g) > 256:
+			return fmt.Errorf("artifact glob must be 1-256 characters")
+		case strings.HasPrefix(g, "/"):
+			return fmt.Errorf("artifact glob %q must be relative to the working directory", g)
+		case g == ".." || strings.HasPrefix(g, "../") || strings.Contains(g, "/../") || strings.HasSuffix(g, "/.."):
+			return fmt.Errorf("artifact glob %q must not leave the working directory", g)
+		}
+		if _, err := path.Match(g, ""); err != nil {
+			return fmt.Errorf("invalid artifact glob %q: %w", g, err)
+		}
+	}
+	return nil
+}
+
+// ArtifactCollector gathers the files of a finished run that match the
+// artifact globs, within the artifact limits. Backends feed it every
+// regular file of the job directory.
+type ArtifactCollector struct {
+	globs []string
+	total int64
+
+	Artifacts []Artifact
+	Skipped   int
+}
+
+func NewArtifactCollector(globs []string) *ArtifactCollector {
+	return &ArtifactCollector{globs: globs}
+}
+
+// Collect reads the file name (relative to the job directory, with
+// forward slashes) from r when it matches a glob and fits the limits.
+func (c *ArtifactCollector) Collect(name string, size int64, r io.Reader) error {
+	if !c.matches(name) {
+		return nil
+	}
+	if size > MaxArtifactSize || len(c.Artifacts) >= MaxArtifacts || c.total+size > MaxArtifactsTotalSize {
+		c.Skipped++
+		return nil
+	}
+
+	content, err := io.ReadAll(io.LimitReader(r, size))
+	if err != nil {
+		return err
+	}
+	c.total += int64(len(content))
+	c.Artifacts = append(c.Artifacts, Artifact{Name: name, Content: content})
+	return nil
+}
+
+func (c *ArtifactCollector) matches(name string) bool {
+	for _, g := range c.globs {
+		if ok, _ := path.Match(g, name); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,101 @@
+package sandbox
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/anurag-327/neuron/internal/models"
+	"github.com/anurag-327/neuron/internal/registry"
+)
+
+var (
+	runnersMu sync.RWMutex
+	runners   = map[string]Runner{}
+)
+
+// RegisterRunner makes a backend available to ExecuteCode under its
+// name. Registering a name twice replaces the previous backend.
+func RegisterRunner(r Runner) {
+	runnersMu.Lock()
+	defer runnersMu.Unlock()
+	runners[r.Name()] = r
+}
+
+// GetRunner returns the registered backend with the given name.
+func GetRunner(name string) (Runner, bool) {
+	runnersMu.RLock()
+	defer runnersMu.RUnlock()
+	r, ok := runners[name]
+	return r, ok
+}
+
+// ShellCommand builds the command every backend executes: run cmd in
+// dir under an in-sandbox `timeout`, which is the authoritative TLE
+// decision (exit code 137). The limit keeps millisecond precision, so
+// sub-second limits are enforced rather than rounded down to 0 (which
+// disables timeout). cmd is quoted for the inner shell, so it
+// may contain quoted program arguments.
+func ShellCommand(dir, cmd string, limit time.Duration) []string {
+	return []string{
+		"sh", "-c",
+		fmt.Sprintf(
+			"cd %s && timeout -s KILL %.3fs sh -c %s",
+			dir,
+			limit.Seconds(),
+			ShellQuote(cmd),
+		),
+	}
+}
+
+// InteractiveCommand is ShellCommand for programs fed from the stdin
+// of the exec, i.e. sessions and interactor runs. input.txt is created
+// as a FIFO fed from stdin, so run commands that redirect from it read
+// the input as it arrives.
+//
+// tee opens the FIFO itself: a shell redirect would block in the
+// forked shell while it still holds the exec's stdout open. When the
+// program never opened input.txt, opening it read-write at the end
+// releases tee, which then exits once stdin is closed.
+func InteractiveCommand(dir, cmd string, limit time.Duration) []string {
+	return []string{
+		"sh", "-c",
+		fmt.Sprintf(
+			"cd %s && mkfifo %s && exec 3<&0 && { tee %s <&3 >/dev/null 2>&1 & } && timeout -s KILL %.3fs sh -c %s; s=$?; exec 4<>%s; exit $s",
+			dir,
+			InputFileName,
+			InputFileName,
+			limit.Seconds(),
+			ShellQuote(cmd),
+			InputFileName,
+		),
+	}
+}
+
+// StageCommand returns the command that performs the stage of req for
+// the program described by names, and its time limit. Compiling runs
+// under the runtime's own limit, running under the one of req. The
+// command is "" when the stage has nothing to execute, i.e. compiling
+// an interpreted program.
+func StageCommand(runtime registry.VersionConfig, names registry.FileNames, req Request) (string, time.Duration, error) {
+	switch req.Stage {
+	case StageCompile:
+		cmd, err := runtime.CompileCommand(names)
+		return cmd, runtime.GetTimeLimit(), err
+	case StageRun:
+		cmd, err := runtime.RunCommand(names)
+		return cmd, req.RunLimit(runtime.GetTimeLimit()), err
+	}
+	cmd, err := runtime.Command(names)
+	return cmd, req.RunLimit(runtime.GetTimeLimit()), err
+}
+
+// CompileResult classifies the result of a StageCompile run: a failure
+// of the compiler other than a limit is a compilation error, whether or
+// not its output matches the language's compile patterns.
+func CompileResult(r RunResult) RunResult {
+	if r.ErrType == models.ErrRuntimeError {
+		r.ErrType, r.ErrMsg = models.ErrCompilationError, models.MsgCompilationError
+	}
+	return r
+}
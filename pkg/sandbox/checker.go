@@ -0,0 +1,78 @@
+package sandbox
+
+import (
+	"context"
+	"log"
+
+	"github.com/anurag-327/neuron/internal/models"
+	"github.com/anurag-327/neuron/internal/registry"
+	"github.com/anurag-327/neuron/pkg/checker"
+)
+
+// Files of a custom checker run, passed to it as its arguments in the
+// order testlib expects: input, participant output, jury answer. The
+// input is also its stdin.
+const (
+	CheckerOutputFile = "output.txt"
+	CheckerAnswerFile = "answer.txt"
+)
+
+// CheckOutput judges the stdout of a job with its checker. Built-in
+// checkers compare in process; a custom checker runs in a sandbox of
+// its own runtime and decides with its exit code, as in testlib.
+func CheckOutput(ctx context.Context, job *models.Job, stdout string) (models.Verdict, string) {
+	c := *job.Checker
+	if c.Type != models.CheckerCustom {
+		return checker.Compare(c, stdout, job.ExpectedOutput)
+	}
+
+	langCfg, ok := registry.LanguageRegistry[c.Language]
+	if !ok {
+		return models.VerdictCheckerFailed, "unsupported checker language"
+	}
+	versionCfg, err := langCfg.ResolveRuntime(c.Version, "")
+	if err != nil {
+		return models.VerdictCheckerFailed, "unsupported checker language"
+	}
+	r, ok := GetRunner(versionCfg.Sandbox.Backend)
+	if !ok {
+		return models.VerdictCheckerFailed, "failed to initiate sandbox"
+	}
+
+	req := Request{
+		JobID:    job.ID.Hex() + "_checker",
+		Code:     c.Code,
+		Input:    job.Input,
+		Language: c.Language,
+		Version:  c.Version,
+		Args:     []string{InputFileName, CheckerOutputFile, CheckerAnswerFile},
+		Files: []models.DataFile{
+			{Name: CheckerOutputFile, Content: []byte(stdout)},
+			{Name: CheckerAnswerFile, Content: []byte(job.ExpectedOutput)},
+		},
+	}
+
+	slot, err := r.Acquire(ctx, req)
+	if err != nil {
+		log.Printf("[CHECK] no sandbox for checker of job %s: %v", job.ID.Hex(), err)
+		return models.VerdictCheckerFailed, "failed to acquire a sandbox for the checker"
+	}
+	result := slot.Run(ctx, req)
+	slot.Release(result.Dirty)
+
+	switch result.ErrType {
+	case models.ErrCompilationError, models.ErrTLE, models.ErrMLE, models.ErrSandboxError, models.ErrInternalError:
+		return models.VerdictCheckerFailed, withDetail("checker did not run:", result.ErrMsg)
+	}
+
+	message := firstLine(result.Stderr)
+	switch result.ExitCode {
+	case TestlibAccepted:
+		return models.VerdictAccepted, message
+	case TestlibWrongAnswer:
+		return models.VerdictWrongAnswer, message
+	case TestlibPresentationError:
+		return models.VerdictPresentationError, message
+	}
+	return models.VerdictCheckerFailed, message
+}
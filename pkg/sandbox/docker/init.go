@@ -2,19 +2,23 @@ package docker
 
 import (
 	"context"
+	"fmt"
 	"log"
+	"os"
 
 	"github.com/anurag-327/neuron/config"
 	"github.com/anurag-327/neuron/conn"
+	"github.com/anurag-327/neuron/internal/registry"
 	"github.com/anurag-327/neuron/pkg/sandbox/docker/pool"
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/filters"
 )
 
-// InitDockerPool registers and warms up all supported language pools.
+// Init registers and warms up the pools of every runtime served by the
+// Docker backend.
 //
 // This function should be invoked once during application startup.
-func InitDockerPool(ctx context.Context) error {
+func (d *Runner) Init(ctx context.Context) error {
 	log.Println("Initializing sandbox container pools...")
 
 	// Clean up any orphaned containers from previous runs
@@ -22,17 +26,50 @@ func InitDockerPool(ctx context.Context) error {
 		log.Printf(" Warning: Failed to cleanup orphaned containers: %v", err)
 	}
 
-	for _, cfg := range config.DockerPools() {
-		pool.Manager.Register(cfg.Language, pool.PoolConfig{
-			Image:          cfg.Image,
-			InitSize:       cfg.InitSize,
-			MaxSize:        cfg.MaxSize,
-			HealthCmd:      cfg.HealthCmd,
-			HealthInterval: cfg.HealthInterval,
+	owner, err := jobOwnerFromEnv()
+	if err != nil {
+		return err
+	}
+	d.owner = owner
+
+	remapped, err := usernsRemapped(ctx, d.client)
+	if err != nil {
+		return fmt.Errorf("failed to inspect Docker daemon: %w", err)
+	}
+	switch {
+	case remapped:
+		log.Printf("Docker userns-remap active: sandbox user %s maps to an unprivileged host user", owner.user())
+	case os.Getenv("SANDBOX_REQUIRE_USERNS") == "true":
+		return fmt.Errorf("SANDBOX_REQUIRE_USERNS is set but the Docker daemon does not use userns-remap")
+	default:
+		log.Printf("Docker userns-remap is off: sandbox user %s is the same UID on the host", owner.user())
+	}
+
+	profiles := newProfileLoader()
+
+	for _, cfg := range config.SandboxPools(registry.BackendDocker) {
+		securityOpt, err := profiles.securityOpt(cfg.Seccomp, cfg.AppArmor)
+		if err != nil {
+			return fmt.Errorf("%s: %w", registry.PoolKey(cfg.Language, cfg.Version, cfg.Library), err)
+		}
+
+		d.pools.Register(registry.PoolKey(cfg.Language, cfg.Version, cfg.Library), pool.PoolConfig{
+			Image:           cfg.Image,
+			Runtime:         cfg.Runtime,
+			SecurityOpt:     securityOpt,
+			InitSize:        cfg.InitSize,
+			MaxSize:         cfg.MaxSize,
+			MemoryLimit:     cfg.MemoryLimit,
+			TmpfsSize:       cfg.TmpfsSize,
+			User:            d.owner.pool().user(),
+			IdleMemoryLimit: cfg.IdleMemoryLimit,
+			HealthCmd:       cfg.HealthCmd,
+			HealthInterval:  cfg.HealthInterval,
+			WarmupCmd:       cfg.WarmupCmd,
 		})
 	}
 
-	if err := pool.Manager.InitAll(ctx); err != nil {
+	if err := d.pools.InitAll(ctx); err != nil {
 		return err
 	}
 
@@ -50,7 +87,7 @@ func cleanupOrphanedContainers(ctx context.Context) error {
 
 	// Get all language images we use
 	images := make(map[string]bool)
-	for _, cfg := range config.DockerPools() {
+	for _, cfg := range config.SandboxPools(registry.BackendDocker) {
 		images[cfg.Image] = true
 	}
 
@@ -79,7 +116,8 @@ func cleanupOrphanedContainers(ctx context.Context) error {
 	removed := 0
 	for _, c := range containers {
 		err := client.ContainerRemove(ctx, c.ID, container.RemoveOptions{
-			Force: true, // Force remove even if running
+			Force:         true, // Force remove even if running
+			RemoveVolumes: true, // Drop the private /sandbox volume
 		})
 		if err != nil {
 			log.Printf("Failed to remove container %s: %v", c.ID[:12], err)
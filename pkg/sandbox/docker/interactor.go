@@ -0,0 +1,301 @@
+package docker
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"time"
+
+	"github.com/anurag-327/neuron/internal/models"
+	"github.com/anurag-327/neuron/internal/registry"
+	"github.com/anurag-327/neuron/pkg/sandbox"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/pkg/stdcopy"
+)
+
+// runWithInteractor runs the program and req.Interactor side by side in
+// the same container, each reading the other's stdout as its stdin.
+//
+// The program's job directory holds its code and data files; the
+// interactor gets a directory of its own, owned by the interactor user,
+// with the test input in test.txt so the program cannot read it. Both
+// sides run under InteractiveCommand with the time limit of req.
+//
+// Staged requests compile both sides with StageCompile, then write
+// test.txt and run both sides with StageRun.
+func (d *Runner) runWithInteractor(
+	ctx context.Context,
+	containerID string,
+	req sandbox.Request,
+	langCfg registry.LanguageConfig,
+	versionCfg registry.VersionConfig,
+) (result sandbox.RunResult) {
+	log := func(format string, args ...any) {
+		fmt.Printf("[INTERACTOR] "+format+"\n", args...)
+	}
+
+	result.ExitCode = 1
+
+	interactorOwner := d.owner.interactor()
+
+	jobDir := "job_" + req.JobID
+	interactorDir := jobDir + "_interactor"
+	containerJobPath := path.Join(sandboxRoot, jobDir)
+	containerInteractorPath := path.Join(sandboxRoot, interactorDir)
+
+	names := sandbox.BuildFileNames(containerJobPath, langCfg, req.Args)
+	interactorNames := sandbox.BuildFileNames(containerInteractorPath, langCfg, []string{sandbox.InteractorTestFile})
+
+	jobs := []jobCleanup{{containerJobPath, d.owner}, {containerInteractorPath, interactorOwner}}
+	if req.Stage == sandbox.StageCleanup {
+		return d.cleanup(containerID, jobs...)
+	}
+
+	// 1 Copy both sides into the container; input.txt is created by
+	// InteractiveCommand as a FIFO. A compiled job only gets its next
+	// test input.
+	var archives []*bytes.Buffer
+	if req.Stage != sandbox.StageRun {
+		files := []jobFile{{name: names.FullName, content: []byte(req.Code)}}
+		for _, f := range req.Files {
+			files = append(files, jobFile{name: f.Name, content: f.Content, mode: 0400})
+		}
+		archive, err := jobArchive(jobDir, d.owner, files...)
+		if err != nil {
+			result.ErrType = models.ErrInternalError
+			result.ErrMsg = "Failed to write code"
+			return result
+		}
+		archives = append(archives, archive)
+	}
+	var interactorFiles []jobFile
+	if req.Stage != sandbox.StageRun {
+		interactorFiles = append(interactorFiles, jobFile{name: interactorNames.FullName, content: []byte(req.Interactor)})
+	}
+	if req.Stage != sandbox.StageCompile {
+		interactorFiles = append(interactorFiles, jobFile{name: sandbox.InteractorTestFile, content: []byte(req.Input), mode: 0400})
+	}
+	interactorArchive, err := jobArchive(interactorDir, interactorOwner, interactorFiles...)
+	if err != nil {
+		result.ErrType = models.ErrInternalError
+		result.ErrMsg = "Failed to write interactor"
+		return result
+	}
+	archives = append(archives, interactorArchive)
+
+	for _, a := range archives {
+		if err := d.client.Client.CopyToContainer(ctx, containerID, sandboxRoot, a, container.CopyToContainerOptions{}); err != nil {
+			log("ERROR copying job files: container=%s err=%v", containerID, err)
+			result.ErrType = models.ErrSandboxError
+			result.ErrMsg = "Failed to write code"
+			result.Dirty = true
+			return result
+		}
+	}
+
+	// 2 Remove both job directories after a full run
+	defer func() {
+		if result.Dirty || req.Stage != sandbox.StageAll {
+			return
+		}
+		if r := d.cleanup(containerID, jobs...); r.Dirty {
+			result.Dirty = true
+		}
+	}()
+
+	// 3 Start both sides with stdin attached
+	runCmd, limit, err := sandbox.StageCommand(versionCfg, names, req)
+	if err != nil {
+		result.ErrType = models.ErrInternalError
+		result.ErrMsg = "Failed to build run command"
+		return result
+	}
+	interactorCmd, _, err := sandbox.StageCommand(versionCfg, interactorNames, req)
+	if err != nil {
+		result.ErrType = models.ErrInternalError
+		result.ErrMsg = "Failed to build interactor command"
+		return result
+	}
+
+	if req.Stage == sandbox.StageCompile {
+		return d.compileWithInteractor(ctx, containerID, req, runCmd, interactorCmd, limit, containerJobPath, containerInteractorPath)
+	}
+
+	started := time.Now()
+	program, err := d.attachExec(ctx, containerID, container.ExecOptions{
+		Cmd:  sandbox.InteractiveCommand(containerJobPath, runCmd, limit),
+		User: d.owner.user(),
+		Env:  sandbox.EnvList(req.Env),
+	})
+	if err != nil {
+		log("ERROR starting program: container=%s err=%v", containerID, err)
+		result.ErrType = models.ErrSandboxError
+		result.ErrMsg = "Exec create failed"
+		result.Dirty = true
+		return result
+	}
+	defer program.attach.Close()
+
+	interactor, err := d.attachExec(ctx, containerID, container.ExecOptions{
+		Cmd:  sandbox.InteractiveCommand(containerInteractorPath, interactorCmd, limit),
+		User: interactorOwner.user(),
+	})
+	if err != nil {
+		log("ERROR starting interactor: container=%s err=%v", containerID, err)
+		result.ErrType = models.ErrSandboxError
+		result.ErrMsg = "Exec create failed"
+		result.Dirty = true
+		return result
+	}
+	defer interactor.attach.Close()
+
+	log("START | container=%s language=%s version=%s limit=%s", containerID, req.Language, req.Version, limit)
+
+	// 4 Cross the streams: each side's stdout feeds the other's stdin,
+	// and the end of one side's output is EOF on the other's input
+	var stdoutBuf, stderrBuf, interactorStderr sandbox.OutputBuffer
+
+	programDone := make(chan error, 1)
+	go func() {
+		toInteractor := io.MultiWriter(&stdoutBuf, peerWriter{interactor.attach.Conn})
+		_, err := stdcopy.StdCopy(toInteractor, &stderrBuf, program.attach.Reader)
+		_ = interactor.attach.CloseWrite()
+		programDone <- err
+	}()
+
+	interactorDone := make(chan error, 1)
+	go func() {
+		_, err := stdcopy.StdCopy(peerWriter{program.attach.Conn}, &interactorStderr, interactor.attach.Reader)
+		_ = program.attach.CloseWrite()
+		interactorDone <- err
+	}()
+
+	// 5 Wait for both sides or the Go-side timeout
+	execCtx, cancel := context.WithTimeout(ctx, limit+time.Second)
+	defer cancel()
+
+	for pending := 2; pending > 0; pending-- {
+		var err error
+		select {
+		case <-execCtx.Done():
+			log("GO TIMEOUT HIT | container=%s err=%v", containerID, execCtx.Err())
+			result.ErrType = models.ErrTLE
+			result.ErrMsg = models.MsgTLE
+			result.Dirty = true
+			return result
+		case err = <-programDone:
+		case err = <-interactorDone:
+		}
+		result.Elapsed = time.Since(started)
+		if err != nil {
+			result.ErrType = models.ErrSandboxError
+			result.ErrMsg = "Output read failed"
+			result.Dirty = true
+			return result
+		}
+	}
+
+	// 6 Judge from both exit codes
+	programInspect, err := d.client.Client.ContainerExecInspect(context.Background(), program.id)
+	if err == nil {
+		var interactorInspect container.ExecInspect
+		interactorInspect, err = d.client.Client.ContainerExecInspect(context.Background(), interactor.id)
+		if err == nil {
+			log("END | container=%s exit=%d interactor=%d", containerID, programInspect.ExitCode, interactorInspect.ExitCode)
+
+			r := sandbox.InteractorResult(
+				req.Language,
+				int64(programInspect.ExitCode), stdoutBuf.String(), stderrBuf.String(),
+				int64(interactorInspect.ExitCode), interactorStderr.String(),
+				containerJobPath,
+			)
+			result.ErrType = r.ErrorType
+			result.ErrMsg = r.ErrorMessage
+			result.Stdout = r.Stdout
+			result.Stderr = r.Stderr
+			result.ExitCode = r.ExitCode
+			result.Diagnostics = r.Diagnostics
+		}
+	}
+	if err != nil {
+		result.ErrType = models.ErrSandboxError
+		result.ErrMsg = "Exec inspect failed"
+		result.Dirty = true
+		return result
+	}
+
+	if result.ExitCode == 139 || result.ExitCode == 124 || result.ExitCode == 137 {
+		result.Dirty = true
+	}
+	return result
+}
+
+// compileWithInteractor compiles both sides of an interactive job,
+// the program first. A failure of the interactor's compiler fails the
+// run as InteractorFailed; its output is not shown to the submitter.
+func (d *Runner) compileWithInteractor(
+	ctx context.Context,
+	containerID string,
+	req sandbox.Request,
+	runCmd, interactorCmd string,
+	limit time.Duration,
+	jobPath, interactorPath string,
+) sandbox.RunResult {
+	var result sandbox.RunResult
+	if runCmd != "" {
+		result = sandbox.CompileResult(d.exec(ctx, containerID, d.owner, req.Env, jobPath, runCmd, limit, req.Language))
+		if result.ErrType != "" || result.Dirty {
+			return result
+		}
+	}
+	if interactorCmd == "" {
+		return result
+	}
+	r := d.exec(ctx, containerID, d.owner.interactor(), nil, interactorPath, interactorCmd, limit, req.Language)
+	if r.ErrType != "" {
+		return sandbox.RunResult{
+			ErrType: models.ErrInteractorFailed,
+			ErrMsg:  models.MsgInteractorFailed,
+			Dirty:   r.Dirty,
+			Elapsed: r.Elapsed,
+		}
+	}
+	return sandbox.RunResult{Elapsed: result.Elapsed + r.Elapsed}
+}
+
+// attachedExec is an exec started with stdin, stdout and stderr attached.
+type attachedExec struct {
+	id     string
+	attach types.HijackedResponse
+}
+
+// attachExec creates and starts an exec with all streams attached.
+func (d *Runner) attachExec(ctx context.Context, containerID string, opts container.ExecOptions) (attachedExec, error) {
+	opts.AttachStdin = true
+	opts.AttachStdout = true
+	opts.AttachStderr = true
+
+	execResp, err := d.client.Client.ContainerExecCreate(ctx, containerID, opts)
+	if err != nil {
+		return attachedExec{}, fmt.Errorf("exec create: %w", err)
+	}
+	attach, err := d.client.Client.ContainerExecAttach(ctx, execResp.ID, container.ExecStartOptions{})
+	if err != nil {
+		return attachedExec{}, fmt.Errorf("exec attach: %w", err)
+	}
+	return attachedExec{id: execResp.ID, attach: attach}, nil
+}
+
+// peerWriter writes to the other side's stdin. Once that side has
+// exited the writes are dropped, so the remaining output is still read.
+type peerWriter struct {
+	w io.Writer
+}
+
+func (p peerWriter) Write(b []byte) (int, error) {
+	_, _ = p.w.Write(b)
+	return len(b), nil
+}
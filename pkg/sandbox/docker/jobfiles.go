@@ -0,0 +1,126 @@
+package docker
+
+import (
+	"archive/tar"
+	"bytes"
+	"fmt"
+	"path"
+	"time"
+
+	"github.com/anurag-327/neuron/pkg/sandbox"
+)
+
+// sandboxRoot is the per-container volume job directories are copied into.
+const sandboxRoot = "/sandbox"
+
+// DefaultSandboxUID is the owner of job files inside sandbox containers
+// when SANDBOX_UID / SANDBOX_GID are not set.
+const DefaultSandboxUID = sandbox.DefaultSandboxUID
+
+// jobOwner is the unprivileged user and group that owns job files.
+type jobOwner struct {
+	uid int
+	gid int
+}
+
+// jobOwnerFromEnv reads SANDBOX_UID and SANDBOX_GID (see
+// sandbox.SandboxIDs).
+func jobOwnerFromEnv() (jobOwner, error) {
+	uid, gid, err := sandbox.SandboxIDs()
+	if err != nil {
+		return jobOwner{}, err
+	}
+	return jobOwner{uid: uid, gid: gid}, nil
+}
+
+// interactor returns the user that owns and runs interactors: the job
+// user's ids plus one, so the program cannot read the test input.
+func (o jobOwner) interactor() jobOwner {
+	return jobOwner{uid: o.uid + 1, gid: o.gid + 1}
+}
+
+// pool returns the user the main process of sandbox containers and
+// their warm-up run as: the job user's ids plus two. Jobs can neither
+// signal the container's own processes nor change or remove what the
+// warm-up left in /tmp.
+func (o jobOwner) pool() jobOwner {
+	return jobOwner{uid: o.uid + 2, gid: o.gid + 2}
+}
+
+// user returns the owner in the "uid:gid" form of docker exec --user.
+func (o jobOwner) user() string {
+	return fmt.Sprintf("%d:%d", o.uid, o.gid)
+}
+
+// jobFile is a file copied into a job directory. name may contain
+// subdirectories; a zero mode means 0600.
+type jobFile struct {
+	name    string
+	content []byte
+	mode    int64
+}
+
+// jobArchive packs a job directory and its files into a tar stream for
+// CopyToContainer. Everything is owned by owner; directories are 0700
+// and files 0600 unless they set a mode, so no other user in the
+// container can read them.
+func jobArchive(dir string, owner jobOwner, files ...jobFile) (*bytes.Buffer, error) {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	now := time.Now()
+
+	dirs := map[string]bool{}
+	writeDir := func(name string) error {
+		dirs[name] = true
+		return tw.WriteHeader(&tar.Header{
+			Typeflag: tar.TypeDir,
+			Name:     name + "/",
+			Mode:     0700,
+			Uid:      owner.uid,
+			Gid:      owner.gid,
+			ModTime:  now,
+		})
+	}
+	if err := writeDir(dir); err != nil {
+		return nil, err
+	}
+
+	for _, f := range files {
+		name := path.Join(dir, f.name)
+
+		// Parents first, so the daemon does not create them as root
+		var parents []string
+		for p := path.Dir(name); !dirs[p] && p != "." && p != "/"; p = path.Dir(p) {
+			parents = append([]string{p}, parents...)
+		}
+		for _, p := range parents {
+			if err := writeDir(p); err != nil {
+				return nil, err
+			}
+		}
+
+		mode := f.mode
+		if mode == 0 {
+			mode = 0600
+		}
+		if err := tw.WriteHeader(&tar.Header{
+			Typeflag: tar.TypeReg,
+			Name:     name,
+			Mode:     mode,
+			Size:     int64(len(f.content)),
+			Uid:      owner.uid,
+			Gid:      owner.gid,
+			ModTime:  now,
+		}); err != nil {
+			return nil, err
+		}
+		if _, err := tw.Write(f.content); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	return &buf, nil
+}
@@ -42,6 +42,14 @@ type PoolConfig struct {
 	// Image is the Docker image used to create containers
 	Image string
 
+	// Runtime is an optional OCI runtime for the containers,
+	// e.g. "runsc" for gVisor. Empty uses the daemon default.
+	Runtime string
+
+	// SecurityOpt holds extra security options, e.g.
+	// "seccomp=<profile json>" or "apparmor=<profile>".
+	SecurityOpt []string
+
 	// InitSize is the number of containers created eagerly
 	// when the pool is initialized.
 	InitSize int
@@ -50,6 +58,24 @@ type PoolConfig struct {
 	// of containers (idle + in-use) managed by the pool.
 	MaxSize int
 
+	// MemoryLimit is the memory limit in bytes applied to every
+	// container of the pool.
+	MemoryLimit int64
+
+	// TmpfsSize is the size in bytes of the writable /tmp mount and
+	// of the /sandbox volume job files are copied into.
+	TmpfsSize int64
+
+	// User is the "uid:gid" containers and warm-up commands run as.
+	// It is not the job user, so jobs cannot signal the container's
+	// processes, and caches filled in /tmp during warm-up are
+	// read-only for jobs.
+	User string
+
+	// IdleMemoryLimit is the memory usage in bytes above which an
+	// idle container is considered bloated and replaced.
+	IdleMemoryLimit int64
+
 	// HealthCmd is an optional command executed inside a container
 	// to verify it is healthy and ready to accept work.
 	// Example: []string{"python", "--version"}
@@ -61,6 +87,11 @@ type PoolConfig struct {
 	// If set to zero or a negative value, a sensible default
 	// (e.g., 2 minutes) will be used.
 	HealthInterval time.Duration
+
+	// WarmupCmd is an optional command executed once in every new
+	// container before it is handed out. A failing warm-up discards
+	// the container.
+	WarmupCmd []string
 }
 
 // ContainerPool manages a pool of reusable Docker containers
@@ -70,7 +101,8 @@ type PoolConfig struct {
 // borrowed and returned to reduce container startup latency.
 // It is safe for concurrent use.
 type ContainerPool struct {
-	// lang identifies the language/runtime this pool serves
+	// lang identifies the language version (and library set)
+	// this pool serves (see registry.PoolKey)
 	lang string
 
 	// cfg holds the pool configuration.
@@ -96,8 +96,10 @@ func (p *ContainerPool) isHealthy(id string) bool {
 	}
 
 	// 2. MEMORY CHECK: Check for 'bloat'
-	// If an idle container is using 50MB+ just sitting there, Python didn't GC properly.
-	if v.MemoryStats.Usage > (50 * 1024 * 1024) {
+	// If an idle container holds more than the pool allows, the last job
+	// leaked memory. Reclaimable page cache is not counted, matching
+	// `docker stats`.
+	if idleMemoryUsage(v.MemoryStats) > uint64(p.cfg.IdleMemoryLimit) {
 		return false
 	}
 
@@ -106,6 +108,20 @@ func (p *ContainerPool) isHealthy(id string) bool {
 	return err == nil && inspect.State.Running
 }
 
+// idleMemoryUsage returns memory usage without inactive page cache.
+//
+// cgroup v2 reports it as "inactive_file", cgroup v1 as "total_inactive_file".
+func idleMemoryUsage(m container.MemoryStats) uint64 {
+	inactive, ok := m.Stats["inactive_file"]
+	if !ok {
+		inactive = m.Stats["total_inactive_file"]
+	}
+	if inactive > m.Usage {
+		return 0
+	}
+	return m.Usage - inactive
+}
+
 func (p *ContainerPool) setHealth(h PoolHealth) {
 	p.healthMu.Lock()
 	defer p.healthMu.Unlock()
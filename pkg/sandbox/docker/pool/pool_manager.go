@@ -3,9 +3,8 @@ package pool
 import (
 	"context"
 	"fmt"
+	"io"
 	"log"
-	"os"
-	"path/filepath"
 	"sync"
 	"time"
 
@@ -15,6 +14,9 @@ import (
 	"github.com/docker/go-units"
 )
 
+// warmupTimeout bounds a single container warm-up command.
+const warmupTimeout = 2 * time.Minute
+
 // PoolManager manages multiple container pools keyed by language/runtime.
 //
 // It is responsible for:
@@ -27,27 +29,31 @@ import (
 type PoolManager struct {
 	mu sync.Mutex
 
-	// pools maps language → container pool
+	// pools maps pool key (language@version) → container pool
 	pools map[string]*ContainerPool
 }
 
-// Manager is the global singleton pool manager.
+// NewManager creates an empty pool manager.
 //
-// This is initialized at process startup and shared across the platform.
-var Manager = &PoolManager{
-	pools: map[string]*ContainerPool{},
+// Each Docker sandbox runner owns one manager, created at startup.
+func NewManager() *PoolManager {
+	return &PoolManager{
+		pools: map[string]*ContainerPool{},
+	}
 }
 
-// Register registers a new container pool for a given language.
+// Register registers a new container pool under a pool key.
 //
-// If a pool with the same language already exists, it will be replaced.
+// The key identifies a language version, optionally with a library
+// set (see registry.PoolKey).
+// If a pool with the same key already exists, it will be replaced.
 // Pool creation errors are ignored here and should surface during InitAll.
-func (pm *PoolManager) Register(language string, cfg PoolConfig) {
+func (pm *PoolManager) Register(key string, cfg PoolConfig) {
 	pm.mu.Lock()
 	defer pm.mu.Unlock()
 
-	pool, _ := NewPool(language, cfg)
-	pm.pools[language] = pool
+	pool, _ := NewPool(key, cfg)
+	pm.pools[key] = pool
 }
 
 // InitAll pre-warms all registered container pools.
@@ -70,11 +76,11 @@ func (pm *PoolManager) InitAll(ctx context.Context) error {
 	return nil
 }
 
-// GetPool returns the container pool for a given language.
+// GetPool returns the container pool registered under a pool key.
 //
 // The caller must handle the case where the pool does not exist.
-func (pm *PoolManager) GetPool(language string) *ContainerPool {
-	return pm.pools[language]
+func (pm *PoolManager) GetPool(key string) *ContainerPool {
+	return pm.pools[key]
 }
 
 // WarmUp eagerly creates InitSize containers and adds them to the idle pool.
@@ -197,38 +203,58 @@ func (p *ContainerPool) Put(id string) {
 		_ = p.client.ContainerRemove(
 			context.Background(),
 			id,
-			container.RemoveOptions{Force: true},
+			container.RemoveOptions{Force: true, RemoveVolumes: true},
 		)
 	}()
 }
 
+// runInit runs Docker's init as PID 1 of sandbox containers.
+var runInit = true
+
 // newContainer creates and starts a new sandbox container.
 //
-// The container is started in an isolated network namespace and
-// mounts a shared sandbox directory for code execution.
+// The container is started in an isolated network namespace and runs
+// as the unprivileged pool user rather than the image's default user;
+// job execs ask for the job user. /sandbox
+// is an anonymous tmpfs-backed volume private to the container; job
+// files are copied into it per run (CopyToContainer works on volumes
+// despite the read-only root filesystem) and it is removed together
+// with the container.
 func (p *ContainerPool) newContainer(ctx context.Context) (string, error) {
-	projectRoot, _ := os.Getwd()
-	basePath := filepath.Join(projectRoot, "/tmp/runner")
-	absPath, _ := filepath.Abs(basePath)
-
 	resp, err := p.client.ContainerCreate(
 		ctx,
 		&container.Config{
 			Image:      p.cfg.Image,
 			Cmd:        []string{"sleep", "infinity"},
 			WorkingDir: "/app",
+			User:       p.cfg.User, // Never the image's default (usually root)
 		},
 		&container.HostConfig{
 			Mounts: []mount.Mount{
-				{Type: mount.TypeBind, Source: absPath, Target: "/sandbox"},
+				{
+					Type:   mount.TypeVolume,
+					Target: "/sandbox",
+					VolumeOptions: &mount.VolumeOptions{
+						DriverConfig: &mount.Driver{
+							Name: "local",
+							Options: map[string]string{
+								"type":   "tmpfs",
+								"device": "tmpfs",
+								"o":      fmt.Sprintf("size=%d,mode=0755,nosuid,nodev", p.cfg.TmpfsSize),
+							},
+						},
+					},
+				},
 			},
-			Tmpfs:          map[string]string{"/tmp": "rw,noexec,nosuid,size=64m"},
+			Runtime:        p.cfg.Runtime,
+			Init:           &runInit, // Reaps processes orphaned by jobs
+			Tmpfs:          map[string]string{"/tmp": fmt.Sprintf("rw,noexec,nosuid,size=%d", p.cfg.TmpfsSize)},
 			ReadonlyRootfs: true,
 			NetworkMode:    "none",
 
 			// Resource limits to prevent abuse
 			Resources: container.Resources{
-				Memory:   256 * 1024 * 1024, // 256MB memory limit
+				Memory:   p.cfg.MemoryLimit, // Per-pool memory limit
 				NanoCPUs: 1000000000,        // 1 CPU core limit
 				PidsLimit: func() *int64 { // Prevent fork bombs
 					limit := int64(100)
@@ -240,9 +266,9 @@ func (p *ContainerPool) newContainer(ctx context.Context) (string, error) {
 			},
 
 			// Security options
-			SecurityOpt: []string{
+			SecurityOpt: append([]string{
 				"no-new-privileges:true", // Prevent privilege escalation
-			},
+			}, p.cfg.SecurityOpt...), // seccomp / AppArmor profiles
 
 			// Prevent container from gaining additional capabilities
 			CapDrop: []string{"ALL"},
@@ -269,9 +295,63 @@ func (p *ContainerPool) newContainer(ctx context.Context) (string, error) {
 		return "", err
 	}
 
+	if len(p.cfg.WarmupCmd) > 0 {
+		if err := p.warmUpContainer(ctx, resp.ID); err != nil {
+			appLogger := logger.GetGlobalLogger()
+			appLogger.Error(ctx, time.Now(), "Failed to warm up container", map[string]interface{}{
+				"language":     p.lang,
+				"container_id": resp.ID,
+				"error":        err.Error(),
+			})
+			_ = p.client.ContainerRemove(
+				context.Background(),
+				resp.ID,
+				container.RemoveOptions{Force: true, RemoveVolumes: true},
+			)
+			return "", err
+		}
+	}
+
 	return resp.ID, nil
 }
 
+// warmUpContainer runs the pool's WarmupCmd inside a freshly started
+// container and waits for it to finish successfully.
+func (p *ContainerPool) warmUpContainer(ctx context.Context, id string) error {
+	warmCtx, cancel := context.WithTimeout(ctx, warmupTimeout)
+	defer cancel()
+
+	execResp, err := p.client.ContainerExecCreate(warmCtx, id, container.ExecOptions{
+		Cmd:          p.cfg.WarmupCmd,
+		User:         p.cfg.User,
+		AttachStdout: true,
+		AttachStderr: true,
+	})
+	if err != nil {
+		return err
+	}
+
+	attach, err := p.client.ContainerExecAttach(warmCtx, execResp.ID, container.ExecStartOptions{})
+	if err != nil {
+		return err
+	}
+	defer attach.Close()
+
+	// Drain output until the command exits
+	if _, err := io.Copy(io.Discard, attach.Reader); err != nil {
+		return err
+	}
+
+	inspect, err := p.client.ContainerExecInspect(warmCtx, execResp.ID)
+	if err != nil {
+		return err
+	}
+	if inspect.ExitCode != 0 {
+		return fmt.Errorf("warm-up command exited with code %d", inspect.ExitCode)
+	}
+	return nil
+}
+
 // replaceContainer removes an unhealthy container and attempts to
 // replace it with a newly created one.
 //
@@ -290,7 +370,7 @@ func (p *ContainerPool) ReplaceContainer(id string) {
 	_ = p.client.ContainerRemove(
 		context.Background(),
 		id,
-		container.RemoveOptions{Force: true},
+		container.RemoveOptions{Force: true, RemoveVolumes: true},
 	)
 
 	// Attempt to spawn a replacement container to maintain pool capacity
@@ -337,7 +417,7 @@ func (p *ContainerPool) Destroy() {
 		p.client.ContainerRemove(
 			context.Background(),
 			id,
-			container.RemoveOptions{Force: true},
+			container.RemoveOptions{Force: true, RemoveVolumes: true},
 		)
 	}
 }
@@ -1,295 +1,535 @@
-package docker
-
-import (
-	"bytes"
-	"context"
-	"fmt"
-	"os"
-	"path/filepath"
-	"time"
-
-	"github.com/anurag-327/neuron/conn"
-	"github.com/anurag-327/neuron/internal/models"
-	"github.com/anurag-327/neuron/internal/registry"
-	fileUtils "github.com/anurag-327/neuron/internal/util/file"
-	"github.com/anurag-327/neuron/pkg/logger"
-	"github.com/docker/docker/api/types/container"
-	"github.com/docker/docker/pkg/stdcopy"
-)
-
-// Runner is responsible for executing user-submitted code
-// inside an already running (pooled) Docker container.
-type Runner struct {
-	client *conn.DockerClient
-}
-
-func NewRunner(client *conn.DockerClient) *Runner {
-	return &Runner{client: client}
-}
-
-// RunResult represents the final outcome of a sandbox execution.
-//
-// ContainerDirty:
-//   - true  → container must be destroyed & replaced
-//   - false → container can be safely reused
-type RunResult struct {
-	Stdout         string
-	Stderr         string
-	ErrType        models.SandboxError
-	ErrMsg         string
-	ExitCode       int64
-	ContainerDirty bool
-}
-
-// ------------------------------------------------------------
-// Run
-// ------------------------------------------------------------
-//
-// High-level execution flow:
-//
-// 1. Create a per-job directory on the HOST
-// 2. Write user code + input into that directory
-// 3. Execute code inside container using docker exec
-// 4. Enforce TIME LIMIT using BusyBox `timeout` (inside container)
-// 5. Use Go context timeout ONLY as a safety net
-// 6. Classify result (TLE / MLE / RE / OK)
-//
-// IMPORTANT TIMEOUT DESIGN:
-//
-//		inner timeout  <  Go exec timeout
-//	 Container is dirty only when go looses control over it and not when program times out correctly
-//
-// Example:
-//
-//	inner timeout = 2s   (authoritative TLE decision)
-//	Go timeout    = 3s   (safety / cleanup)
-//
-// Exit-code policy (documented by design):
-//
-//	124 → timeout exited normally (TLE)
-//	137 → SIGKILL (treated as TLE in this design)
-//	139 → SIGSEGV (MLE)
-func (d *Runner) Run(
-	ctx context.Context,
-	containerID,
-	basePathString, code, input, language string,
-) RunResult {
-
-	log := func(format string, args ...any) {
-		fmt.Printf("[RUN] "+format+"\n", args...)
-	}
-
-	result := RunResult{}
-	result.ExitCode = 1
-
-	log("START | container=%s language=%s", containerID, language)
-
-	// 1 Create job directory on HOST
-	projectRoot, _ := os.Getwd()
-	basePath := filepath.Join(projectRoot, basePathString)
-
-	log("Creating job directory: %s", basePath)
-
-	if err := os.MkdirAll(basePath, 0777); err != nil {
-		log("ERROR creating job dir: %v", err)
-		appLogger := logger.GetGlobalLogger()
-		appLogger.Error(ctx, time.Now(), "Failed to create job directory", map[string]interface{}{
-			"containerID": containerID,
-			"language":    language,
-			"error":       err.Error(),
-		})
-		result.ErrType = models.ErrInternalError
-		result.ErrMsg = "Failed to create job directory"
-		return result
-	}
-
-	// FORCE 0777 to bypass permissions error
-	if err := os.Chmod(basePath, 0777); err != nil {
-		log("ERROR chmod job dir: %v", err)
-		result.ErrType = models.ErrInternalError
-		result.ErrMsg = "Failed to set permissions"
-		return result
-	}
-
-	defer func() {
-		log("Deleting job directory: %s", basePath)
-		fileUtils.DeleteFolder(basePath)
-	}()
-
-	// 2 Load language configuration
-	log("Loading language config: %s", language)
-
-	langCfg, ok := registry.LanguageRegistry[language]
-	if !ok {
-		log("ERROR unsupported language")
-		result.ErrType = models.ErrInternalError
-		result.ErrMsg = "Unsupported language"
-		return result
-	}
-
-	names := BuildFileNames(basePath, langCfg)
-
-	// 3 Write user code and input
-	log("Writing code file: %s", names.PathFull)
-
-	if err := fileUtils.WriteContentToFile(names.PathFull, []byte(code), 0777); err != nil {
-		log("ERROR writing code: %v", err)
-		result.ErrType = models.ErrInternalError
-		result.ErrMsg = "Failed to write code"
-		return result
-	}
-
-	log("Writing input.txt")
-
-	if err := fileUtils.WriteContentToFile(
-		filepath.Join(basePath, "input.txt"),
-		[]byte(input),
-		0777,
-	); err != nil {
-		log("ERROR writing input: %v", err)
-		result.ErrType = models.ErrInternalError
-		result.ErrMsg = "Failed to write input"
-		return result
-	}
-
-	containerJobPath := filepath.Join("/sandbox", filepath.Base(basePath))
-	log("Container job path: %s", containerJobPath)
-
-	// 4 Build execution command
-	runCmd := langCfg.RunCmd(names)
-
-	runTimeout := 3 * time.Second
-	execTimeout := 4 * time.Second
-
-	log("Timeouts | run=%s exec=%s", runTimeout, execTimeout)
-	log("Run command: %s", runCmd)
-
-	execCmd := []string{
-		"sh", "-c",
-		fmt.Sprintf(
-			"cd %s && timeout -s KILL %ds sh -c '%s'",
-			containerJobPath,
-			int(runTimeout.Seconds()),
-			runCmd,
-		),
-	}
-
-	// 5 Create docker exec (NO timeout here)
-	log("Creating docker exec")
-
-	execResp, err := d.client.Client.ContainerExecCreate(
-		context.Background(),
-		containerID,
-		container.ExecOptions{
-			Cmd:          execCmd,
-			AttachStdout: true,
-			AttachStderr: true,
-		},
-	)
-	if err != nil {
-		log("ERROR exec create failed: %v", err)
-		appLogger := logger.GetGlobalLogger()
-		appLogger.Error(ctx, time.Now(), "Docker exec create failed", map[string]interface{}{
-			"container_id": containerID,
-			"language":     language,
-			"error":        err.Error(),
-		})
-		result.ErrType = models.ErrSandboxError
-		result.ErrMsg = "Exec create failed"
-		result.ContainerDirty = true
-		return result
-	}
-
-	log("Exec created: %s", execResp.ID)
-
-	// 6 Attach to exec & wait with Go timeout
-	execCtx, cancel := context.WithTimeout(ctx, execTimeout)
-	defer cancel()
-
-	deadline, _ := execCtx.Deadline()
-	log("Attaching to exec | deadline=%v", deadline)
-
-	attach, err := d.client.Client.ContainerExecAttach(
-		execCtx,
-		execResp.ID,
-		container.ExecStartOptions{},
-	)
-	if err != nil {
-		log("ERROR exec attach failed: %v", err)
-		appLogger := logger.GetGlobalLogger()
-		appLogger.Error(ctx, time.Now(), "Docker exec attach failed", map[string]interface{}{
-			"container_id": containerID,
-			"language":     language,
-			"error":        err.Error(),
-		})
-		result.ErrType = models.ErrSandboxError
-		result.ErrMsg = "Exec attach failed"
-		result.ContainerDirty = true
-		return result
-	}
-	defer attach.Close()
-
-	var stdoutBuf, stderrBuf bytes.Buffer
-	done := make(chan error, 1)
-
-	go func() {
-		log("Started stdout/stderr reader")
-		_, err := stdcopy.StdCopy(&stdoutBuf, &stderrBuf, attach.Reader)
-		done <- err
-	}()
-
-	// 7 Wait for completion OR Go-side timeout
-	select {
-
-	case <-execCtx.Done():
-		log("GO TIMEOUT HIT | err=%v", execCtx.Err())
-		appLogger := logger.GetGlobalLogger()
-		appLogger.Error(ctx, time.Now(), "Execution timeout (Go context)", map[string]interface{}{
-			"container_id": containerID,
-			"language":     language,
-			"timeout_sec":  execTimeout.Seconds(),
-		})
-		result.ErrType = models.ErrTLE
-		result.ErrMsg = models.MsgTLE
-		result.ContainerDirty = true
-		return result
-
-	case err := <-done:
-		log("Exec finished | reader err=%v", err)
-		if err != nil {
-			result.ErrType = models.ErrSandboxError
-			result.ErrMsg = "Output read failed"
-			result.ContainerDirty = true
-			return result
-		}
-	}
-
-	// 8 Inspect exit code for classification
-	inspect, _ := d.client.Client.ContainerExecInspect(
-		context.Background(),
-		execResp.ID,
-	)
-
-	log("Final inspect | pid=%d exit=%d",
-		inspect.Pid, inspect.ExitCode)
-
-	// Parse Error
-	r := ProcessResult(language, int64(inspect.ExitCode), stdoutBuf.String(), stderrBuf.String(), containerJobPath)
-	result.ErrType = r.ErrorType
-	result.ErrMsg = r.ErrorMessage
-	result.Stdout = r.Stdout
-	result.Stderr = r.Stderr
-	result.ExitCode = r.ExitCode
-
-	if r.ExitCode == 139 || r.ExitCode == 124 || r.ExitCode == 137 {
-		result.ContainerDirty = true
-	}
-
-	log("Execution completed successfully")
-	return result
-}
-
-func (d *Runner) Health() error {
-	_, err := d.client.Ping(context.Background())
-	return err
-}
+package docker
+
+import (
+	"archive/tar"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/anurag-327/neuron/conn"
+	"github.com/anurag-327/neuron/internal/models"
+	"github.com/anurag-327/neuron/internal/registry"
+	"github.com/anurag-327/neuron/pkg/logger"
+	"github.com/anurag-327/neuron/pkg/sandbox"
+	"github.com/anurag-327/neuron/pkg/sandbox/docker/pool"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/pkg/stdcopy"
+)
+
+// Runner is the Docker sandbox backend. It executes user-submitted
+// code inside already running (pooled) Docker containers.
+//
+// Pools whose runtime sets an OCI runtime (e.g. gVisor's runsc) are
+// served by the same runner; only the container runtime differs.
+type Runner struct {
+	client *conn.DockerClient
+	pools  *pool.PoolManager
+
+	// owner is the unprivileged user that owns job files and runs jobs
+	owner jobOwner
+}
+
+func NewRunner(client *conn.DockerClient) *Runner {
+	return &Runner{client: client, pools: pool.NewManager()}
+}
+
+// Name implements sandbox.Runner.
+func (d *Runner) Name() string {
+	return registry.BackendDocker
+}
+
+// Acquire borrows a warm container from the pool of the runtime.
+func (d *Runner) Acquire(ctx context.Context, req sandbox.Request) (sandbox.Slot, error) {
+	p := d.pools.GetPool(registry.PoolKey(req.Language, req.Version, req.Library))
+	if p == nil {
+		return nil, sandbox.ErrNoRuntime
+	}
+
+	containerID, err := p.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &slot{runner: d, pool: p, containerID: containerID}, nil
+}
+
+// Shutdown destroys all warm containers.
+func (d *Runner) Shutdown() {
+	d.pools.DestroyAll()
+}
+
+// slot is a container borrowed from a pool.
+type slot struct {
+	runner      *Runner
+	pool        *pool.ContainerPool
+	containerID string
+
+	// interactor is set once an interactor ran in the container
+	interactor bool
+}
+
+func (s *slot) Run(ctx context.Context, req sandbox.Request) sandbox.RunResult {
+	if req.Interactor != "" {
+		s.interactor = true
+	}
+	return s.runner.run(ctx, s.containerID, req)
+}
+
+// Release scrubs the container of what the jobs left (see scrub) and
+// returns it to the pool; dirty containers, and containers that cannot
+// be scrubbed, are destroyed and replaced.
+func (s *slot) Release(dirty bool) {
+	if !dirty {
+		owners := []jobOwner{s.runner.owner}
+		if s.interactor {
+			owners = append(owners, s.runner.owner.interactor())
+		}
+		if err := s.runner.scrub(s.containerID, owners...); err != nil {
+			log.Printf("[POOL] failed to scrub container %s: %v", s.containerID, err)
+			dirty = true
+		}
+	}
+	if dirty {
+		log.Println("[POOL] destroying dirty container:", s.containerID)
+		s.pool.ReplaceContainer(s.containerID)
+		return
+	}
+	log.Println("[POOL] returning clean container:", s.containerID)
+	s.pool.Put(s.containerID)
+}
+
+// ------------------------------------------------------------
+// run
+// ------------------------------------------------------------
+//
+// High-level execution flow:
+//
+// 1. Copy user code + input into a job directory owned by the job user
+// 2. Execute code inside container using docker exec as that user
+// 3. Remove the job directory (or discard the container)
+// 4. Enforce TIME LIMIT using BusyBox `timeout` (inside container)
+// 5. Use Go context timeout ONLY as a safety net
+// 6. Classify result (TLE / MLE / RE / OK)
+//
+// Staged requests (see sandbox.Stage) perform one part of this flow and
+// keep the job directory for the next stage.
+//
+// IMPORTANT TIMEOUT DESIGN:
+//
+//		inner timeout  <  Go exec timeout
+//	 Container is dirty only when go looses control over it and not when program times out correctly
+//
+// Example:
+//
+//	inner timeout = 2s   (authoritative TLE decision)
+//	Go timeout    = 3s   (safety / cleanup)
+//
+// Exit-code policy (documented by design):
+//
+//	124 → timeout exited normally (TLE)
+//	137 → SIGKILL (treated as TLE in this design)
+//	139 → SIGSEGV (MLE)
+func (d *Runner) run(
+	ctx context.Context,
+	containerID string,
+	req sandbox.Request,
+) (result sandbox.RunResult) {
+
+	log := func(format string, args ...any) {
+		fmt.Printf("[RUN] "+format+"\n", args...)
+	}
+
+	language, version := req.Language, req.Version
+
+	result.ExitCode = 1
+
+	log("START | container=%s language=%s version=%s stage=%d", containerID, language, version, req.Stage)
+
+	// 1 Load language configuration
+	log("Loading language config: %s", language)
+
+	langCfg, ok := registry.LanguageRegistry[language]
+	if !ok {
+		log("ERROR unsupported language")
+		result.ErrType = models.ErrInternalError
+		result.ErrMsg = "Unsupported language"
+		return result
+	}
+
+	versionCfg, err := langCfg.ResolveRuntime(version, req.Library)
+	if err != nil {
+		log("ERROR unsupported runtime: %v", err)
+		result.ErrType = models.ErrInternalError
+		result.ErrMsg = "Unsupported language version or library set"
+		return result
+	}
+
+	if req.Interactor != "" {
+		return d.runWithInteractor(ctx, containerID, req, langCfg, versionCfg)
+	}
+
+	jobDir := "job_" + req.JobID
+	containerJobPath := path.Join(sandboxRoot, jobDir)
+	names := sandbox.BuildFileNames(containerJobPath, langCfg, req.Args)
+
+	log("Container job path: %s", containerJobPath)
+
+	if req.Stage == sandbox.StageCleanup {
+		log("Deleting job directory: %s", containerJobPath)
+		return d.cleanup(containerID, jobCleanup{containerJobPath, d.owner})
+	}
+
+	// 2 Copy user code, input and data files into the container; a
+	// compiled job only gets its next input
+	var files []jobFile
+	if req.Stage != sandbox.StageRun {
+		files = append(files, jobFile{name: names.FullName, content: []byte(req.Code)})
+		for _, f := range req.Files {
+			files = append(files, jobFile{name: f.Name, content: f.Content, mode: 0400})
+		}
+	}
+	if req.Stage != sandbox.StageCompile {
+		files = append(files, jobFile{name: sandbox.InputFileName, content: []byte(req.Input)})
+	}
+	archive, err := jobArchive(jobDir, d.owner, files...)
+	if err != nil {
+		log("ERROR packing job files: %v", err)
+		result.ErrType = models.ErrInternalError
+		result.ErrMsg = "Failed to write code"
+		return result
+	}
+
+	log("Copying job files")
+
+	if err := d.client.Client.CopyToContainer(
+		ctx,
+		containerID,
+		sandboxRoot,
+		archive,
+		container.CopyToContainerOptions{},
+	); err != nil {
+		log("ERROR copying job files: %v", err)
+		appLogger := logger.GetGlobalLogger()
+		appLogger.Error(ctx, time.Now(), "Failed to copy job files", map[string]interface{}{
+			"container_id": containerID,
+			"language":     language,
+			"error":        err.Error(),
+		})
+		result.ErrType = models.ErrSandboxError
+		result.ErrMsg = "Failed to write code"
+		result.Dirty = true
+		return result
+	}
+
+	// 3 Remove the job directory after a full run; a container whose
+	// job files cannot be removed is not reused
+	defer func() {
+		if result.Dirty || req.Stage != sandbox.StageAll {
+			return
+		}
+		log("Deleting job directory: %s", containerJobPath)
+		if r := d.cleanup(containerID, jobCleanup{containerJobPath, d.owner}); r.Dirty {
+			result.Dirty = true
+		}
+	}()
+
+	// 4 Build execution command
+	runCmd, runTimeout, err := sandbox.StageCommand(versionCfg, names, req)
+	if err != nil {
+		log("ERROR building run command: %v", err)
+		result.ErrType = models.ErrInternalError
+		result.ErrMsg = "Failed to build run command"
+		return result
+	}
+	if runCmd == "" {
+		log("Nothing to compile")
+		result.ExitCode = 0
+		return result
+	}
+
+	log("Run command: %s", runCmd)
+
+	// 5 Execute and classify
+	result = d.exec(ctx, containerID, d.owner, req.Env, containerJobPath, runCmd, runTimeout, language)
+	if req.Stage == sandbox.StageCompile {
+		return sandbox.CompileResult(result)
+	}
+
+	// 6 Collect artifacts before the job directory is removed
+	if len(req.Artifacts) > 0 && !result.Dirty {
+		collector, err := d.collectArtifacts(ctx, containerID, containerJobPath, req.Artifacts)
+		if err != nil {
+			log("ERROR collecting artifacts: %v", err)
+		} else {
+			result.Artifacts = collector.Artifacts
+			result.ArtifactsSkipped = collector.Skipped
+		}
+	}
+
+	log("Execution completed successfully")
+	return result
+}
+
+// exec runs cmd in dir as owner under sandbox.ShellCommand with the
+// given time limit and classifies the result. The limit is enforced by
+// the in-container timeout; the Go timeout a second later is the safety
+// net that marks the container dirty.
+func (d *Runner) exec(
+	ctx context.Context,
+	containerID string,
+	owner jobOwner,
+	env map[string]string,
+	dir, cmd string,
+	runTimeout time.Duration,
+	language string,
+) (result sandbox.RunResult) {
+
+	log := func(format string, args ...any) {
+		fmt.Printf("[RUN] "+format+"\n", args...)
+	}
+
+	result.ExitCode = 1
+
+	execTimeout := runTimeout + time.Second
+	log("Timeouts | run=%s exec=%s", runTimeout, execTimeout)
+
+	execCmd := sandbox.ShellCommand(dir, cmd, runTimeout)
+
+	// 1 Create docker exec (NO timeout here)
+	log("Creating docker exec")
+
+	execResp, err := d.client.Client.ContainerExecCreate(
+		context.Background(),
+		containerID,
+		container.ExecOptions{
+			Cmd:          execCmd,
+			User:         owner.user(),
+			Env:          sandbox.EnvList(env),
+			AttachStdout: true,
+			AttachStderr: true,
+		},
+	)
+	if err != nil {
+		log("ERROR exec create failed: %v", err)
+		appLogger := logger.GetGlobalLogger()
+		appLogger.Error(ctx, time.Now(), "Docker exec create failed", map[string]interface{}{
+			"container_id": containerID,
+			"language":     language,
+			"error":        err.Error(),
+		})
+		result.ErrType = models.ErrSandboxError
+		result.ErrMsg = "Exec create failed"
+		result.Dirty = true
+		return result
+	}
+
+	log("Exec created: %s", execResp.ID)
+
+	// 2 Attach to exec & wait with Go timeout
+	execCtx, cancel := context.WithTimeout(ctx, execTimeout)
+	defer cancel()
+
+	deadline, _ := execCtx.Deadline()
+	log("Attaching to exec | deadline=%v", deadline)
+
+	started := time.Now()
+	attach, err := d.client.Client.ContainerExecAttach(
+		execCtx,
+		execResp.ID,
+		container.ExecStartOptions{},
+	)
+	if err != nil {
+		log("ERROR exec attach failed: %v", err)
+		appLogger := logger.GetGlobalLogger()
+		appLogger.Error(ctx, time.Now(), "Docker exec attach failed", map[string]interface{}{
+			"container_id": containerID,
+			"language":     language,
+			"error":        err.Error(),
+		})
+		result.ErrType = models.ErrSandboxError
+		result.ErrMsg = "Exec attach failed"
+		result.Dirty = true
+		return result
+	}
+	defer attach.Close()
+
+	var stdoutBuf, stderrBuf sandbox.OutputBuffer
+	done := make(chan error, 1)
+
+	go func() {
+		log("Started stdout/stderr reader")
+		_, err := stdcopy.StdCopy(&stdoutBuf, &stderrBuf, attach.Reader)
+		done <- err
+	}()
+
+	// 3 Wait for completion OR Go-side timeout
+	select {
+
+	case <-execCtx.Done():
+		log("GO TIMEOUT HIT | err=%v", execCtx.Err())
+		appLogger := logger.GetGlobalLogger()
+		appLogger.Error(ctx, time.Now(), "Execution timeout (Go context)", map[string]interface{}{
+			"container_id": containerID,
+			"language":     language,
+			"timeout_sec":  execTimeout.Seconds(),
+		})
+		result.ErrType = models.ErrTLE
+		result.ErrMsg = models.MsgTLE
+		result.Dirty = true
+		result.Elapsed = time.Since(started)
+		return result
+
+	case err := <-done:
+		result.Elapsed = time.Since(started)
+		log("Exec finished | reader err=%v", err)
+		if err != nil {
+			result.ErrType = models.ErrSandboxError
+			result.ErrMsg = "Output read failed"
+			result.Dirty = true
+			return result
+		}
+	}
+
+	// 4 Inspect exit code for classification
+	inspect, _ := d.client.Client.ContainerExecInspect(
+		context.Background(),
+		execResp.ID,
+	)
+
+	log("Final inspect | pid=%d exit=%d",
+		inspect.Pid, inspect.ExitCode)
+
+	// Parse Error
+	r := sandbox.ProcessResult(language, int64(inspect.ExitCode), stdoutBuf.String(), stderrBuf.String(), dir)
+	result.ErrType = r.ErrorType
+	result.ErrMsg = r.ErrorMessage
+	result.Stdout = r.Stdout
+	result.Stderr = r.Stderr
+	result.ExitCode = r.ExitCode
+	result.Diagnostics = r.Diagnostics
+
+	if r.ExitCode == 139 || r.ExitCode == 124 || r.ExitCode == 137 {
+		result.Dirty = true
+	}
+	return result
+}
+
+// collectArtifacts reads the job directory as a tar stream and keeps
+// the regular files matching globs. Links are skipped, so a job cannot
+// smuggle out files from outside its directory.
+func (d *Runner) collectArtifacts(ctx context.Context, containerID, dir string, globs []string) (*sandbox.ArtifactCollector, error) {
+	rc, _, err := d.client.Client.CopyFromContainer(ctx, containerID, dir)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	collector := sandbox.NewArtifactCollector(globs)
+	tr := tar.NewReader(rc)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return collector, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		// Entries are named after the job directory: job_<id>/out.png
+		_, name, ok := strings.Cut(hdr.Name, "/")
+		if !ok {
+			continue
+		}
+		if err := collector.Collect(name, hdr.Size, tr); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// jobCleanup is a job directory and the user owning it.
+type jobCleanup struct {
+	dir   string
+	owner jobOwner
+}
+
+// cleanup removes job directories after their last stage; a container
+// whose job files cannot be removed is marked dirty.
+func (d *Runner) cleanup(containerID string, jobs ...jobCleanup) (result sandbox.RunResult) {
+	for _, j := range jobs {
+		if err := d.removeJobDir(containerID, j.dir, j.owner); err != nil {
+			fmt.Printf("[RUN] ERROR deleting job dir %s: %v\n", j.dir, err)
+			result.Dirty = true
+			return result
+		}
+	}
+	return result
+}
+
+// removeJobDir deletes a job directory inside a container. It runs as
+// owner, who owns everything in it, and first restores write
+// permission in case the job removed it.
+func (d *Runner) removeJobDir(containerID, dir string, owner jobOwner) error {
+	return d.execAs(containerID, owner, fmt.Sprintf("chmod -R u+rwX %s; rm -rf %s", dir, dir))
+}
+
+// scrubScript kills every process of the user running it but itself
+// and removes the user's files from /tmp. The container's main process
+// and the warm-up files belong to the pool user, out of its reach.
+const scrubScript = "kill -9 -1 2>/dev/null; rm -rf /tmp/* /tmp/.[!.]* /tmp/..?* 2>/dev/null; exit 0"
+
+// scrub runs scrubScript as each of owners, so no process or /tmp file
+// of a job survives into the next job on the container.
+func (d *Runner) scrub(containerID string, owners ...jobOwner) error {
+	for _, o := range owners {
+		if err := d.execAs(containerID, o, scrubScript); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// execAs runs a shell script in a container as owner and waits for it
+// to exit successfully.
+func (d *Runner) execAs(containerID string, owner jobOwner, script string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	execResp, err := d.client.Client.ContainerExecCreate(ctx, containerID, container.ExecOptions{
+		Cmd:          []string{"sh", "-c", script},
+		User:         owner.user(),
+		AttachStdout: true,
+		AttachStderr: true,
+	})
+	if err != nil {
+		return err
+	}
+
+	attach, err := d.client.Client.ContainerExecAttach(ctx, execResp.ID, container.ExecStartOptions{})
+	if err != nil {
+		return err
+	}
+	defer attach.Close()
+
+	if _, err := io.Copy(io.Discard, attach.Reader); err != nil {
+		return err
+	}
+
+	inspect, err := d.client.Client.ContainerExecInspect(ctx, execResp.ID)
+	if err != nil {
+		return err
+	}
+	if inspect.ExitCode != 0 {
+		return fmt.Errorf("%q exited with code %d", script, inspect.ExitCode)
+	}
+	return nil
+}
+
+func (d *Runner) Health() error {
+	_, err := d.client.Ping(context.Background())
+	return err
+}
@@ -0,0 +1,124 @@
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/anurag-327/neuron/conn"
+)
+
+// DefaultSeccompDir is where seccomp profiles are read from when
+// SECCOMP_DIR is not set. It is relative to the process working directory.
+const DefaultSeccompDir = "security/seccomp"
+
+// seccompLogFlag makes the kernel audit-log every syscall the
+// profile does not allow.
+const seccompLogFlag = "SECCOMP_FILTER_FLAG_LOG"
+
+// profileLoader reads seccomp profiles once per name and turns
+// language sandbox settings into container security options.
+type profileLoader struct {
+	dir      string
+	audit    bool
+	apparmor bool
+	seccomp  map[string]string
+}
+
+func newProfileLoader() *profileLoader {
+	dir := os.Getenv("SECCOMP_DIR")
+	if dir == "" {
+		dir = DefaultSeccompDir
+	}
+
+	l := &profileLoader{
+		dir:      dir,
+		audit:    os.Getenv("SECCOMP_AUDIT") == "true",
+		apparmor: appArmorEnabled(),
+		seccomp:  map[string]string{},
+	}
+	if l.audit {
+		log.Println("Seccomp audit enabled: denied syscalls are logged to the kernel audit log")
+	}
+	return l
+}
+
+// securityOpt returns the SecurityOpt entries for a seccomp and an
+// AppArmor profile name. Empty names keep Docker's defaults.
+func (l *profileLoader) securityOpt(seccomp, apparmor string) ([]string, error) {
+	var opts []string
+
+	if seccomp != "" {
+		profile, err := l.loadSeccomp(seccomp)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, "seccomp="+profile)
+	}
+
+	if apparmor != "" {
+		if l.apparmor {
+			opts = append(opts, "apparmor="+apparmor)
+		} else {
+			log.Printf("AppArmor is not enabled on this host, skipping profile %s", apparmor)
+		}
+	}
+
+	return opts, nil
+}
+
+// loadSeccomp returns the profile as compact JSON, which is the form
+// the Engine API expects (the CLI reads the file itself).
+func (l *profileLoader) loadSeccomp(name string) (string, error) {
+	if profile, ok := l.seccomp[name]; ok {
+		return profile, nil
+	}
+
+	raw, err := os.ReadFile(filepath.Join(l.dir, name+".json"))
+	if err != nil {
+		return "", fmt.Errorf("failed to read seccomp profile %q: %w", name, err)
+	}
+
+	var profile map[string]any
+	if err := json.Unmarshal(raw, &profile); err != nil {
+		return "", fmt.Errorf("invalid seccomp profile %q: %w", name, err)
+	}
+
+	if l.audit {
+		profile["flags"] = []string{seccompLogFlag}
+	}
+
+	out, err := json.Marshal(profile)
+	if err != nil {
+		return "", err
+	}
+
+	l.seccomp[name] = string(out)
+	return l.seccomp[name], nil
+}
+
+// appArmorEnabled reports whether the host kernel enforces AppArmor.
+func appArmorEnabled() bool {
+	enabled, err := os.ReadFile("/sys/module/apparmor/parameters/enabled")
+	return err == nil && strings.TrimSpace(string(enabled)) == "Y"
+}
+
+// usernsRemapped reports whether the Docker daemon runs containers in a
+// remapped user namespace (dockerd --userns-remap), so that UIDs inside
+// sandbox containers, root included, are unprivileged on the host.
+func usernsRemapped(ctx context.Context, client *conn.DockerClient) (bool, error) {
+	info, err := client.Info(ctx)
+	if err != nil {
+		return false, err
+	}
+	for _, opt := range info.SecurityOptions {
+		if opt == "name=userns" || strings.HasPrefix(opt, "name=userns,") {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,149 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"time"
+
+	"github.com/anurag-327/neuron/internal/models"
+	"github.com/anurag-327/neuron/internal/registry"
+	"github.com/anurag-327/neuron/pkg/sandbox"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/pkg/stdcopy"
+)
+
+// Interact implements sandbox.InteractiveSlot.
+func (s *slot) Interact(ctx context.Context, req sandbox.Request, limit time.Duration, stdin io.Reader, stdout, stderr io.Writer) sandbox.RunResult {
+	return s.runner.interact(ctx, s.containerID, req, limit, stdin, stdout, stderr)
+}
+
+// interact runs an interactive session in a pooled container. The job
+// directory is set up as for run, without input.txt: InteractiveCommand
+// turns it into a FIFO fed from the attached stdin. The container is
+// always discarded afterwards, so the job directory is not removed.
+func (d *Runner) interact(
+	ctx context.Context,
+	containerID string,
+	req sandbox.Request,
+	limit time.Duration,
+	stdin io.Reader,
+	stdout, stderr io.Writer,
+) (result sandbox.RunResult) {
+	log := func(format string, args ...any) {
+		fmt.Printf("[SESSION] "+format+"\n", args...)
+	}
+
+	result.ExitCode = 1
+	result.Dirty = true
+
+	langCfg, ok := registry.LanguageRegistry[req.Language]
+	if !ok {
+		result.ErrType = models.ErrInternalError
+		result.ErrMsg = "Unsupported language"
+		return result
+	}
+	versionCfg, err := langCfg.ResolveRuntime(req.Version, req.Library)
+	if err != nil {
+		result.ErrType = models.ErrInternalError
+		result.ErrMsg = "Unsupported language version or library set"
+		return result
+	}
+
+	jobDir := "job_" + req.JobID
+	containerJobPath := path.Join(sandboxRoot, jobDir)
+	names := sandbox.BuildFileNames(containerJobPath, langCfg, req.Args)
+
+	// 1 Copy user code and data files into the container
+	files := []jobFile{{name: names.FullName, content: []byte(req.Code)}}
+	for _, f := range req.Files {
+		files = append(files, jobFile{name: f.Name, content: f.Content, mode: 0400})
+	}
+	archive, err := jobArchive(jobDir, d.owner, files...)
+	if err != nil {
+		result.ErrType = models.ErrInternalError
+		result.ErrMsg = "Failed to write code"
+		return result
+	}
+	if err := d.client.Client.CopyToContainer(ctx, containerID, sandboxRoot, archive, container.CopyToContainerOptions{}); err != nil {
+		log("ERROR copying job files: container=%s err=%v", containerID, err)
+		result.ErrType = models.ErrSandboxError
+		result.ErrMsg = "Failed to write code"
+		return result
+	}
+
+	// 2 Start the program with stdin attached
+	runCmd, err := versionCfg.Command(names)
+	if err != nil {
+		result.ErrType = models.ErrInternalError
+		result.ErrMsg = "Failed to build run command"
+		return result
+	}
+
+	exec, err := d.attachExec(ctx, containerID, container.ExecOptions{
+		Cmd:  sandbox.InteractiveCommand(containerJobPath, runCmd, limit),
+		User: d.owner.user(),
+		Env:  sandbox.EnvList(req.Env),
+	})
+	if err != nil {
+		log("ERROR starting exec: container=%s err=%v", containerID, err)
+		result.ErrType = models.ErrSandboxError
+		result.ErrMsg = "Exec create failed"
+		return result
+	}
+	attach := exec.attach
+	defer attach.Close()
+
+	log("START | container=%s language=%s version=%s limit=%s", containerID, req.Language, req.Version, limit)
+
+	// Closing stdin (EOF from the client) reaches the program as EOF
+	go func() {
+		_, _ = io.Copy(attach.Conn, stdin)
+		_ = attach.CloseWrite()
+	}()
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := stdcopy.StdCopy(stdout, stderr, attach.Reader)
+		done <- err
+	}()
+
+	// 3 Wait for the program, the Go-side wall clock or cancellation
+	wallCtx, cancel := context.WithTimeout(ctx, limit+time.Second)
+	defer cancel()
+
+	select {
+	case <-wallCtx.Done():
+		log("STOP | container=%s err=%v", containerID, context.Cause(wallCtx))
+		if ctx.Err() == nil {
+			result.ErrType = models.ErrTLE
+			result.ErrMsg = models.MsgTLE
+			result.ExitCode = 137
+		}
+		return result
+
+	case err := <-done:
+		if err != nil {
+			result.ErrType = models.ErrSandboxError
+			result.ErrMsg = "Output stream failed"
+			return result
+		}
+	}
+
+	inspect, err := d.client.Client.ContainerExecInspect(context.Background(), exec.id)
+	if err != nil {
+		result.ErrType = models.ErrSandboxError
+		result.ErrMsg = "Exec inspect failed"
+		return result
+	}
+
+	result.ExitCode = int64(inspect.ExitCode)
+	if result.ExitCode == 137 {
+		result.ErrType = models.ErrTLE
+		result.ErrMsg = models.MsgTLE
+	}
+
+	log("END | container=%s exit=%d", containerID, result.ExitCode)
+	return result
+}
@@ -0,0 +1,135 @@
+package sandbox
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/anurag-327/neuron/config"
+	"github.com/anurag-327/neuron/internal/models"
+	"github.com/anurag-327/neuron/internal/registry"
+)
+
+// Limits on the arguments, environment and data files of a submission.
+const (
+	MaxArgs          = 32
+	MaxArgsSize      = 4 * 1024 // total bytes over all arguments
+	MaxEnv           = 16
+	MaxEnvValueSize  = 1024
+	MaxDataFiles     = 10
+	MaxDataFilesSize = 1024 * 1024 // 1MB over all files
+)
+
+// InputFileName is the file stdin is redirected from.
+const InputFileName = "input.txt"
+
+var (
+	envNamePattern  = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	dataFilePattern = regexp.MustCompile(`^[A-Za-z0-9._-]+(/[A-Za-z0-9._-]+)*$`)
+)
+
+// ValidateArgs checks the program arguments of a submission.
+func ValidateArgs(args []string) error {
+	if len(args) > MaxArgs {
+		return fmt.Errorf("at most %d arguments are allowed", MaxArgs)
+	}
+	size := 0
+	for _, a := range args {
+		if strings.ContainsRune(a, 0) {
+			return fmt.Errorf("arguments must not contain NUL bytes")
+		}
+		size += len(a)
+	}
+	if size > MaxArgsSize {
+		return fmt.Errorf("arguments must not exceed %d bytes in total", MaxArgsSize)
+	}
+	return nil
+}
+
+// ValidateEnv checks the environment variables of a submission against
+// the whitelist in config.SubmissionEnv.
+func ValidateEnv(env map[string]string) error {
+	if len(env) > MaxEnv {
+		return fmt.Errorf("at most %d environment variables are allowed", MaxEnv)
+	}
+	for name, value := range env {
+		if !envNamePattern.MatchString(name) {
+			return fmt.Errorf("invalid environment variable name %q", name)
+		}
+		if !config.IsSubmissionEnvAllowed(name) {
+			return fmt.Errorf("environment variable %s is not allowed; use the %s prefix for application variables", name, config.SubmissionEnvPrefix)
+		}
+		if len(value) > MaxEnvValueSize || strings.ContainsRune(value, 0) {
+			return fmt.Errorf("environment variable %s must be at most %d bytes without NUL bytes", name, MaxEnvValueSize)
+		}
+	}
+	return nil
+}
+
+// ValidateDataFiles checks the data files of a submission in language
+// lang. Names are relative paths that must not clash with the input
+// file, the source file or anything the toolchain writes next to it
+// (the base name with or without an extension, e.g. "main", "Main.class").
+func ValidateDataFiles(files []models.DataFile, lang registry.LanguageConfig) error {
+	if len(files) > MaxDataFiles {
+		return fmt.Errorf("at most %d data files are allowed", MaxDataFiles)
+	}
+	size := 0
+	seen := make(map[string]bool, len(files))
+	for _, f := range files {
+		switch {
+		case len(f.Name) > 128 || !dataFilePattern.MatchString(f.Name):
+			return fmt.Errorf("invalid data file name %q: use a relative path of letters, digits, '.', '_' and '-'", f.Name)
+		case path.Clean(f.Name) != f.Name || f.Name == "." || f.Name == ".." || strings.HasPrefix(f.Name, "../"):
+			return fmt.Errorf("data file %q must stay inside the working directory", f.Name)
+		}
+
+		top := strings.SplitN(f.Name, "/", 2)[0]
+		if top == InputFileName || top == lang.BaseName || strings.HasPrefix(top, lang.BaseName+".") {
+			return fmt.Errorf("data file %q clashes with a file of the run", f.Name)
+		}
+		if seen[f.Name] {
+			return fmt.Errorf("duplicate data file %q", f.Name)
+		}
+		seen[f.Name] = true
+		size += len(f.Content)
+	}
+	for name := range seen {
+		// "a" and "a/b" cannot both exist
+		for dir := path.Dir(name); dir != "."; dir = path.Dir(dir) {
+			if seen[dir] {
+				return fmt.Errorf("data file %q is also used as a directory", dir)
+			}
+		}
+	}
+	if size > MaxDataFilesSize {
+		return fmt.Errorf("data files must not exceed %d bytes in total", MaxDataFilesSize)
+	}
+	return nil
+}
+
+// ShellQuote quotes s as a single POSIX shell word.
+func ShellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// QuoteArgs renders program arguments for the {{.Args}} template field.
+func QuoteArgs(args []string) string {
+	quoted := make([]string, len(args))
+	for i, a := range args {
+		quoted[i] = ShellQuote(a)
+	}
+	return strings.Join(quoted, " ")
+}
+
+// EnvList returns env as sorted NAME=value pairs.
+func EnvList(env map[string]string) []string {
+	list := make([]string, 0, len(env))
+	for name, value := range env {
+		list = append(list, name+"="+value)
+	}
+	sort.Strings(list)
+	return list
+}
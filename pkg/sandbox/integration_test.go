@@ -0,0 +1,111 @@
+//go:build integration
+
+// Integration suites run real programs through the sandbox backends the
+// worker uses, without MongoDB or Kafka. They need Docker with gVisor's
+// runsc registered, and bwrap or nsjail with the toolchains of every
+// language on the host for the process backend (see TestIsolation); a
+// backend that cannot start fails the run. From the repository root:
+//
+//	go test -tags integration ./pkg/sandbox/
+package sandbox_test
+
+import (
+	"context"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anurag-327/neuron/internal/factory"
+	"github.com/anurag-327/neuron/internal/registry"
+	"github.com/anurag-327/neuron/pkg/sandbox"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// testdata holds the programs of the suites, shared with scripts/.
+const testdata = "scripts/testdata"
+
+// loaded are the names of the languages in languages/, in order,
+// without the runtimes the suites register themselves.
+var loaded []string
+
+func TestMain(m *testing.M) {
+	// Language definitions, seccomp profiles and programs are found
+	// relative to the repository root, as in the worker
+	if err := os.Chdir("../.."); err != nil {
+		log.Fatal(err)
+	}
+	if err := registry.LoadLanguages(registry.LanguagesDir()); err != nil {
+		log.Fatalf("failed to load language definitions: %v", err)
+	}
+	for name := range registry.LanguageRegistry {
+		loaded = append(loaded, name)
+	}
+	sort.Strings(loaded)
+
+	if err := registerIsolationRuntimes(); err != nil {
+		log.Fatalf("failed to register isolation runtimes: %v", err)
+	}
+	if err := factory.InitRunners(context.Background()); err != nil {
+		log.Fatalf("sandbox backends not available: %v", err)
+	}
+
+	code := m.Run()
+	factory.ShutdownRunners()
+	os.Exit(code)
+}
+
+// programs returns the programs of a suite for language, keyed by file
+// name without extension. A language without programs fails t.
+func programs(t *testing.T, suite, language string) map[string]string {
+	t.Helper()
+	dir := filepath.Join(testdata, suite, language)
+	entries, err := os.ReadDir(dir)
+	if err != nil || len(entries) == 0 {
+		t.Fatalf("no %s programs for %s in %s", suite, language, dir)
+	}
+	out := map[string]string{}
+	for _, e := range entries {
+		code, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		out[strings.TrimSuffix(e.Name(), filepath.Ext(e.Name()))] = string(code)
+	}
+	return out
+}
+
+// run executes req on a slot of its runtime, as the worker does for a
+// plain submission.
+func run(t *testing.T, req sandbox.Request) sandbox.RunResult {
+	t.Helper()
+	langCfg, ok := registry.LanguageRegistry[req.Language]
+	if !ok {
+		t.Fatalf("language %s not loaded", req.Language)
+	}
+	versionCfg, err := langCfg.ResolveRuntime(req.Version, req.Library)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, ok := sandbox.GetRunner(versionCfg.Sandbox.Backend)
+	if !ok {
+		t.Fatalf("sandbox backend %s not available", versionCfg.Sandbox.Backend)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	if req.JobID == "" {
+		req.JobID = primitive.NewObjectID().Hex()
+	}
+	slot, err := r.Acquire(ctx, req)
+	if err != nil {
+		t.Fatalf("no slot for %s %s: %v", req.Language, req.Version, err)
+	}
+	result := slot.Run(ctx, req)
+	slot.Release(result.Dirty)
+	return result
+}
@@ -0,0 +1,72 @@
+package sandbox
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/anurag-327/neuron/internal/models"
+	"github.com/anurag-327/neuron/internal/registry"
+)
+
+// InteractorTestFile is the file holding the test input in the
+// interactor's directory; its name is the interactor's only argument.
+const InteractorTestFile = "test.txt"
+
+// Exit codes of testlib interactors and checkers.
+const (
+	TestlibAccepted          = 0
+	TestlibWrongAnswer       = 1
+	TestlibPresentationError = 2
+)
+
+// ValidateInteractor checks that runs with an interactor can be judged
+// on runtime: only the docker backend runs both sides together.
+func ValidateInteractor(runtime registry.VersionConfig) error {
+	if runtime.Sandbox.Backend != registry.BackendDocker {
+		return errors.New("interactors are not supported for this runtime")
+	}
+	return nil
+}
+
+// InteractorResult classifies a run judged by an interactor from the
+// exit codes and output of both sides. The program's own outcome wins
+// when it was not accepted for reasons of its own (compilation error,
+// time or memory limit); otherwise the interactor's verdict decides,
+// and a runtime error only counts when the interactor accepted, since a
+// program often crashes after the interactor rejected it and hung up.
+func InteractorResult(lang string, exitCode int64, stdout, stderr string, interactorExit int64, interactorStderr, jobID string) ResultResponse {
+	r := ProcessResult(lang, exitCode, stdout, stderr, jobID)
+	switch r.ErrorType {
+	case models.ErrCompilationError, models.ErrTLE, models.ErrMLE:
+		return r
+	}
+
+	verdict := firstLine(SanitizeOutput(interactorStderr, jobID))
+	switch interactorExit {
+	case TestlibAccepted:
+		return r
+	case TestlibWrongAnswer:
+		r.ErrorType = models.ErrWrongAnswer
+		r.ErrorMessage = withDetail(models.MsgWrongAnswer, verdict)
+	case TestlibPresentationError:
+		r.ErrorType = models.ErrPresentationError
+		r.ErrorMessage = withDetail(models.MsgPresentationError, verdict)
+	default:
+		r.ErrorType = models.ErrInteractorFailed
+		r.ErrorMessage = models.MsgInteractorFailed
+	}
+	r.Diagnostics = nil
+	return r
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(s), "\n")
+	return strings.TrimSpace(line)
+}
+
+func withDetail(msg, detail string) string {
+	if detail == "" {
+		return msg
+	}
+	return msg + " " + detail
+}
@@ -1,12 +1,134 @@
-package sandbox
-
-import (
-	"context"
-
-	"github.com/anurag-327/neuron/pkg/sandbox/docker"
-)
-
-type Runner interface {
-	Run(ctx context.Context, containerID, basePath, code, input, language string) docker.RunResult
-	Health() error
-}
+package sandbox
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/anurag-327/neuron/internal/models"
+)
+
+// ErrNoRuntime is returned by Runner.Acquire when the backend does not
+// serve the requested language / version / library set.
+var ErrNoRuntime = errors.New("runtime not served by this sandbox backend")
+
+// Request describes a single execution.
+type Request struct {
+	JobID    string
+	Code     string
+	Input    string
+	Language string
+	Version  string
+	Library  string
+
+	// Args are passed to the program through {{.Args}} in the run
+	// command, Env is added to its environment and Files are written
+	// read-only to the job directory (see ValidateDataFiles).
+	Args  []string
+	Env   map[string]string
+	Files []models.DataFile
+
+	// Artifacts are globs of files to collect from the job
+	// directory after the run (see ArtifactCollector).
+	Artifacts []string
+
+	// Interactor is judge code in the same runtime that runs next to
+	// the program with their stdin and stdout connected. Input is then
+	// written to InteractorTestFile for the interactor instead of being
+	// the program's stdin; see InteractorResult for the verdicts.
+	Interactor string
+
+	// TimeLimit lowers the runtime's time limit, e.g. to the limit of
+	// a problem or the one set on a rerun; zero keeps the runtime's limit.
+	TimeLimit time.Duration
+
+	// Stage selects the part of the run the slot performs; see Stage.
+	Stage Stage
+}
+
+// Stage is a part of a run. Jobs that run one program on several
+// inputs, e.g. the tests of a problem, compile it once with
+// StageCompile, run it on each input with StageRun and remove the job
+// files with StageCleanup, all on the same slot.
+type Stage int
+
+const (
+	// StageAll writes the job files, compiles, runs and removes the
+	// job files.
+	StageAll Stage = iota
+
+	// StageCompile writes the job files without the input and compiles
+	// under the runtime's own time limit. The job files are kept.
+	StageCompile
+
+	// StageRun writes Input and runs the compiled program under the
+	// time limit of the request. The job files are kept.
+	StageRun
+
+	// StageCleanup removes the job files.
+	StageCleanup
+)
+
+// RunLimit returns the time limit of the request on a runtime whose
+// own limit is runtimeLimit.
+func (r Request) RunLimit(runtimeLimit time.Duration) time.Duration {
+	if r.TimeLimit > 0 && r.TimeLimit < runtimeLimit {
+		return r.TimeLimit
+	}
+	return runtimeLimit
+}
+
+// RunResult represents the final outcome of a sandbox execution.
+//
+// Dirty:
+//   - true  → the slot must be destroyed & replaced
+//   - false → the slot can be safely reused
+type RunResult struct {
+	Stdout   string
+	Stderr   string
+	ErrType  models.SandboxError
+	ErrMsg   string
+	ExitCode int64
+	Dirty    bool
+
+	// Elapsed is the wall time of the executed command.
+	Elapsed time.Duration
+
+	// Diagnostics are compiler messages or the uncaught runtime
+	// error parsed from Stderr.
+	Diagnostics []models.Diagnostic
+
+	// Artifacts are the collected files matching Request.Artifacts;
+	// ArtifactsSkipped counts matches dropped by the limits.
+	Artifacts        []Artifact
+	ArtifactsSkipped int
+}
+
+// Runner is a sandbox backend (Docker, process jail, ...).
+//
+// Backends own their pools: Acquire hands out an isolated execution
+// slot for a runtime, which is returned with Slot.Release.
+type Runner interface {
+	// Name is the backend name used in language definitions.
+	Name() string
+
+	// Init prepares the runtimes served by the backend,
+	// e.g. by warming container pools.
+	Init(ctx context.Context) error
+
+	// Acquire blocks until a slot for the runtime of req is free.
+	Acquire(ctx context.Context, req Request) (Slot, error)
+
+	Health() error
+
+	// Shutdown releases every resource held by the backend.
+	Shutdown()
+}
+
+// Slot is an execution slot borrowed from a Runner.
+type Slot interface {
+	Run(ctx context.Context, req Request) RunResult
+
+	// Release returns the slot; dirty slots are destroyed and replaced.
+	Release(dirty bool)
+}
@@ -0,0 +1,92 @@
+//go:build integration
+
+package sandbox_test
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/anurag-327/neuron/internal/registry"
+	"github.com/anurag-327/neuron/pkg/sandbox"
+)
+
+// isolationBackends are the sandboxes the isolation programs run under:
+// pooled containers, the same containers under gVisor and process jails.
+var isolationBackends = []struct {
+	name    string
+	backend string
+	runtime string
+}{
+	{"docker", registry.BackendDocker, ""},
+	{"gvisor", registry.BackendDocker, "runsc"},
+	{"process", registry.BackendProcess, ""},
+}
+
+// isolationLanguage is the name the default version of language is
+// registered under for backend.
+func isolationLanguage(language, backend string) string {
+	return language + "-" + backend
+}
+
+// registerIsolationRuntimes registers a copy of the default version of
+// every loaded language for each isolation backend, with one warm slot,
+// so that a single run covers every language on every backend.
+func registerIsolationRuntimes() error {
+	for _, language := range loaded {
+		langCfg := registry.LanguageRegistry[language]
+		versionCfg, ok := langCfg.Versions[langCfg.DefaultVersion]
+		if !ok || versionCfg.Pool == nil {
+			return fmt.Errorf("%s: default version %s has no pool", language, langCfg.DefaultVersion)
+		}
+		for _, b := range isolationBackends {
+			v := versionCfg
+			v.Sandbox.Backend, v.Sandbox.Runtime = b.backend, b.runtime
+			pool := *versionCfg.Pool
+			pool.InitSize, pool.MaxSize = 1, 1
+			v.Pool = &pool
+
+			c := langCfg
+			c.Name = isolationLanguage(language, b.name)
+			c.Versions = map[string]registry.VersionConfig{v.Name: v}
+			c.Libraries = nil
+			registry.LanguageRegistry[c.Name] = c
+		}
+	}
+	return nil
+}
+
+// TestIsolation runs the programs of scripts/testdata/isolation/<name>/
+// for every language on every backend. Each tries to leave its job
+// directory or read a file only root may read and must print BLOCKED.
+func TestIsolation(t *testing.T) {
+	for _, language := range loaded {
+		t.Run(language, func(t *testing.T) {
+			t.Parallel()
+			code := programs(t, "isolation", language)
+			names := make([]string, 0, len(code))
+			for name := range code {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+
+			for _, b := range isolationBackends {
+				for _, name := range names {
+					t.Run(b.name+"/"+name, func(t *testing.T) {
+						result := run(t, sandbox.Request{
+							Code:     code[name],
+							Language: isolationLanguage(language, b.name),
+							Version:  registry.LanguageRegistry[language].DefaultVersion,
+						})
+						lines := strings.Split(strings.TrimSpace(result.Stdout), "\n")
+						if result.ErrType != "" || lines[len(lines)-1] != "BLOCKED" {
+							t.Errorf("got %q (%s %s)\nstdout: %s\nstderr: %s",
+								lines[len(lines)-1], result.ErrType, result.ErrMsg, result.Stdout, result.Stderr)
+						}
+					})
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+//go:build integration
+
+package sandbox_test
+
+import (
+	"testing"
+
+	"github.com/anurag-327/neuron/internal/models"
+	"github.com/anurag-327/neuron/internal/registry"
+	"github.com/anurag-327/neuron/pkg/sandbox"
+)
+
+// TestLanguages runs the programs of scripts/testdata/languages/<name>/
+// on every version of every language and checks the error type each
+// outcome is reported as.
+func TestLanguages(t *testing.T) {
+	outcomes := []struct {
+		program string
+		want    models.SandboxError
+	}{
+		{"ok", ""},
+		{"compile_error", models.ErrCompilationError},
+		{"runtime_error", models.ErrRuntimeError},
+		{"tle", models.ErrTLE},
+	}
+
+	for _, language := range loaded {
+		t.Run(language, func(t *testing.T) {
+			t.Parallel()
+			code := programs(t, "languages", language)
+			langCfg := registry.LanguageRegistry[language]
+
+			for _, version := range langCfg.VersionNames() {
+				for _, o := range outcomes {
+					t.Run(version+"/"+o.program, func(t *testing.T) {
+						program, ok := code[o.program]
+						if !ok {
+							t.Fatalf("no %s program for %s", o.program, language)
+						}
+						result := run(t, sandbox.Request{
+							Code:     program,
+							Language: language,
+							Version:  version,
+						})
+						if result.ErrType != o.want {
+							t.Errorf("error type = %q (%s), want %q\nstdout: %s\nstderr: %s",
+								result.ErrType, result.ErrMsg, o.want, result.Stdout, result.Stderr)
+						}
+						if o.program == "ok" && result.Stdout == "" {
+							t.Error("ok program printed nothing")
+						}
+					})
+				}
+			}
+		})
+	}
+}
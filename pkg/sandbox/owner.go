@@ -0,0 +1,38 @@
+package sandbox
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+)
+
+// DefaultSandboxUID is the owner of job files and the user jobs run as
+// when SANDBOX_UID / SANDBOX_GID are not set. It must not be 0.
+const DefaultSandboxUID = 10001
+
+// SandboxIDs reads the unprivileged user and group that own job files
+// and run jobs from SANDBOX_UID and SANDBOX_GID. The GID defaults to
+// the UID.
+func SandboxIDs() (uid, gid int, err error) {
+	uid, err = envID("SANDBOX_UID", DefaultSandboxUID)
+	if err != nil {
+		return 0, 0, err
+	}
+	gid, err = envID("SANDBOX_GID", uid)
+	if err != nil {
+		return 0, 0, err
+	}
+	return uid, gid, nil
+}
+
+func envID(name string, fallback int) (int, error) {
+	v := os.Getenv(name)
+	if v == "" {
+		return fallback, nil
+	}
+	id, err := strconv.Atoi(v)
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("%s must be a positive numeric id, got %q", name, v)
+	}
+	return id, nil
+}
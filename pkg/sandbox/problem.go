@@ -0,0 +1,220 @@
+package sandbox
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/anurag-327/neuron/config"
+	"github.com/anurag-327/neuron/internal/models"
+)
+
+// runTests runs a problem job against the samples and then the hidden
+// tests of problem, stopping at the first test that is not accepted
+// unless job.RunAllTests is set, and records the outcome of each in
+// job.TestResults. A compilation error always stops.
+//
+// The program is compiled once on slot (StageCompile) and then run on
+// each test (StageRun), so the time limit and the time of a test cover
+// its run only. A slot left dirty by a test is replaced and the program
+// compiled again. A custom checker runs in a slot of its own runtime, so
+// with one the outputs are kept until they reach config.MaxOutputSize or
+// the tests end, and judged in order once the program's slot is released.
+//
+// The returned result is the one of the first failed test, or of the
+// last test when all passed. Hidden tests keep only their verdict or
+// error type (see hideTest), so neither their input nor their expected
+// output can leak through the job.
+func runTests(ctx context.Context, r Runner, slot Slot, req Request, job *models.Job, problem *models.Problem) RunResult {
+	type test struct {
+		models.TestCase
+		n      int
+		sample bool
+	}
+	tests := make([]test, 0, len(problem.Samples)+len(problem.Tests))
+	for _, t := range problem.Samples {
+		tests = append(tests, test{t, len(tests) + 1, true})
+	}
+	for _, t := range problem.Tests {
+		tests = append(tests, test{t, len(tests) + 1, false})
+	}
+
+	checker := models.Checker{Type: models.CheckerExact}
+	if problem.Checker != nil {
+		checker = *problem.Checker
+	}
+
+	job.TestResults = nil
+	job.Verdict = models.VerdictAccepted
+	job.VerdictMessage = fmt.Sprintf("%d tests passed", len(tests))
+
+	var result, failed RunResult
+	failedAt := 0
+	compiled := false
+
+	// release removes the job files and returns the slot
+	release := func(dirty bool) {
+		if !dirty {
+			cleanup := req
+			cleanup.Stage = StageCleanup
+			dirty = slot.Run(ctx, cleanup).Dirty
+		}
+		slot.Release(dirty)
+		slot, compiled = nil, false
+	}
+
+	// record judges the run of t and records its outcome; it reports
+	// whether the remaining tests are skipped
+	record := func(t test, run RunResult) bool {
+		tr := models.TestResult{
+			Test:      t.n,
+			Sample:    t.sample,
+			ErrorType: run.ErrType,
+			ExitCode:  run.ExitCode,
+			TimeMs:    run.Elapsed.Milliseconds(),
+		}
+		if run.ErrType == "" && problem.Interactor != nil {
+			// The interactor accepted; its other verdicts are errors
+			tr.Verdict = models.VerdictAccepted
+		} else if run.ErrType == "" {
+			judged := *job
+			judged.Input = t.Input
+			judged.ExpectedOutput = t.ExpectedOutput
+			judged.Checker = &checker
+			tr.Verdict, tr.VerdictMessage = CheckOutput(ctx, &judged, run.Stdout)
+		}
+
+		if !t.sample {
+			tr.VerdictMessage, tr.ExitCode, tr.TimeMs = "", 0, 0
+			run = hideTest(run)
+		}
+		job.TestResults = append(job.TestResults, tr)
+		result = run
+
+		if failedAt > 0 || (run.ErrType == "" && tr.Verdict == models.VerdictAccepted) {
+			return false
+		}
+		failedAt, failed = t.n, run
+		if run.ErrType != "" {
+			job.Verdict, job.VerdictMessage = "", ""
+			failed.ErrMsg = fmt.Sprintf("Test %d: %s", t.n, run.ErrMsg)
+		} else {
+			job.Verdict = tr.Verdict
+			job.VerdictMessage = fmt.Sprintf("Test %d", t.n)
+			if tr.VerdictMessage != "" {
+				job.VerdictMessage += ": " + tr.VerdictMessage
+			}
+		}
+		return !job.RunAllTests
+	}
+
+	// Runs awaiting a custom checker
+	type pendingRun struct {
+		test
+		run RunResult
+	}
+	var pending []pendingRun
+	pendingSize := 0
+	judgePending := func() bool {
+		if slot != nil {
+			release(false)
+		}
+		runs := pending
+		pending, pendingSize = nil, 0
+		for _, p := range runs {
+			if record(p.test, p.run) {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, t := range tests {
+		if slot == nil {
+			var err error
+			if slot, err = r.Acquire(ctx, req); err != nil {
+				log.Printf("[RUN] no slot for test %d of job %s: %v", t.n, job.ID.Hex(), err)
+				if judgePending() || failedAt > 0 {
+					return failed
+				}
+				job.Verdict, job.VerdictMessage = "", ""
+				return RunResult{
+					ErrType: models.ErrInternalError,
+					ErrMsg:  fmt.Sprintf("Test %d: failed to acquire a container", t.n),
+				}
+			}
+		}
+
+		if !compiled {
+			compile := req
+			compile.Stage = StageCompile
+			res := slot.Run(ctx, compile)
+			if res.ErrType != "" {
+				release(res.Dirty)
+				if judgePending() || failedAt > 0 {
+					return failed
+				}
+				job.Verdict, job.VerdictMessage = "", ""
+				return res
+			}
+			compiled = true
+		}
+
+		run := req
+		run.Stage = StageRun
+		run.Input = t.Input
+		res := slot.Run(ctx, run)
+		if res.Dirty {
+			slot.Release(true)
+			slot, compiled = nil, false
+		}
+
+		if checker.Type != models.CheckerCustom {
+			if record(t, res) {
+				break
+			}
+			continue
+		}
+
+		pending = append(pending, pendingRun{t, res})
+		pendingSize += len(res.Stdout)
+		stop := res.ErrType != "" && !job.RunAllTests
+		if stop || pendingSize >= config.MaxOutputSize || t.n == len(tests) {
+			if judgePending() || stop {
+				break
+			}
+		}
+	}
+	if slot != nil {
+		release(false)
+	}
+
+	if failedAt > 0 {
+		return failed
+	}
+	return result
+}
+
+// hideTest drops what the run of a hidden test reveals beyond its
+// outcome: output, diagnostics, exit code, time and any detail in the
+// error message.
+func hideTest(run RunResult) RunResult {
+	hidden := RunResult{ErrType: run.ErrType}
+	if run.ErrType != "" {
+		hidden.ErrMsg = errorMessages[run.ErrType]
+	}
+	return hidden
+}
+
+// errorMessages are the messages of each error type without detail.
+var errorMessages = map[models.SandboxError]string{
+	models.ErrTLE:               models.MsgTLE,
+	models.ErrMLE:               models.MsgMLE,
+	models.ErrCompilationError:  models.MsgCompilationError,
+	models.ErrRuntimeError:      models.MsgRuntimeError,
+	models.ErrSandboxError:      models.MsgSandboxError,
+	models.ErrInternalError:     models.MsgInternalError,
+	models.ErrWrongAnswer:       models.MsgWrongAnswer,
+	models.ErrPresentationError: models.MsgPresentationError,
+	models.ErrInteractorFailed:  models.MsgInteractorFailed,
+}
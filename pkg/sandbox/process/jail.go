@@ -0,0 +1,128 @@
+package process
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/anurag-327/neuron/config"
+)
+
+// sandboxPath is the PATH inside the jail.
+const sandboxPath = "/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin:/sbin:/bin"
+
+// hostPaths are mounted read-only so host toolchains work in the jail.
+// Paths missing on the host are skipped.
+var hostPaths = []string{
+	"/usr", "/bin", "/sbin", "/lib", "/lib32", "/lib64", "/opt",
+	"/etc/alternatives", "/etc/ld.so.cache",
+}
+
+func existingHostPaths() []string {
+	var paths []string
+	for _, p := range hostPaths {
+		if _, err := os.Stat(p); err == nil {
+			paths = append(paths, p)
+		}
+	}
+	return paths
+}
+
+// Resource limits of a jailed run besides memory and time.
+const (
+	jailMaxProcs = 100
+	jailMaxFiles = 64
+)
+
+// cpuSeconds is the CPU time limit of a run that may last timeout: a
+// backstop for the wall-clock limits, rounded up to whole seconds.
+func cpuSeconds(timeout time.Duration) int {
+	return int((timeout+time.Second-1)/time.Second) + 1
+}
+
+// bwrapArgs confines shell with bubblewrap. bubblewrap has no resource
+// limits of its own, so it is started under prlimit, which caps memory,
+// CPU time, processes and open files for everything in the jail.
+// env holds NAME=value pairs added to the jail environment.
+func bwrapArgs(jobDir, jobPath string, cfg config.SandboxPoolConfig, timeout time.Duration, env, shell []string) []string {
+	args := []string{
+		ToolPrlimit,
+		"--as=" + strconv.FormatInt(cfg.MemoryLimit, 10),
+		"--cpu=" + strconv.Itoa(cpuSeconds(timeout)),
+		"--nproc=" + strconv.Itoa(jailMaxProcs),
+		"--nofile=" + strconv.Itoa(jailMaxFiles),
+		"--",
+		ToolBubblewrap,
+		"--die-with-parent",
+		"--new-session",
+		"--unshare-all", // includes the network namespace
+		"--cap-drop", "ALL",
+		"--clearenv",
+		"--setenv", "PATH", sandboxPath,
+		"--setenv", "HOME", "/tmp",
+	}
+	for _, kv := range env {
+		name, value, _ := strings.Cut(kv, "=")
+		args = append(args, "--setenv", name, value)
+	}
+	for _, p := range existingHostPaths() {
+		args = append(args, "--ro-bind", p, p)
+	}
+	args = append(args,
+		"--proc", "/proc",
+		"--dev", "/dev",
+		"--tmpfs", "/tmp",
+		"--bind", jobDir, jobPath,
+		"--",
+		"/bin/sh", "-c", shell[2],
+	)
+	return args
+}
+
+// nsjailArgs confines shell with nsjail in one-shot mode. owner, when
+// set, is the user the run is mapped to.
+func nsjailArgs(jobDir, jobPath string, cfg config.SandboxPoolConfig, timeout time.Duration, owner *jailOwner, env, shell []string) []string {
+	args := []string{
+		ToolNsjail,
+		"--mode", "o",
+		"--really_quiet",
+		"--time_limit", strconv.Itoa(cpuSeconds(timeout)),
+		"--max_cpus", "1",
+		"--rlimit_as", strconv.FormatInt(cfg.MemoryLimit/(1024*1024), 10),
+		"--rlimit_cpu", strconv.Itoa(cpuSeconds(timeout)),
+		"--rlimit_nofile", strconv.Itoa(jailMaxFiles),
+		"--rlimit_nproc", strconv.Itoa(jailMaxProcs),
+		"--env", "PATH=" + sandboxPath,
+		"--env", "HOME=/tmp",
+		"--cwd", jobPath,
+	}
+	if owner != nil {
+		args = append(args, "--user", strconv.Itoa(owner.uid), "--group", strconv.Itoa(owner.gid))
+	}
+	for _, kv := range env {
+		args = append(args, "--env", kv)
+	}
+	for _, p := range existingHostPaths() {
+		args = append(args, "-R", p)
+	}
+	args = append(args,
+		"-R", "/dev/urandom",
+		"-R", "/dev/zero",
+		"-B", "/dev/null",
+		"-T", "/tmp",
+		"-B", jobDir+":"+jobPath,
+		"--",
+		"/bin/sh", "-c", shell[2],
+	)
+	return args
+}
+
+func signalOf(state *os.ProcessState) (int, bool) {
+	ws, ok := state.Sys().(syscall.WaitStatus)
+	if !ok || !ws.Signaled() {
+		return 0, false
+	}
+	return int(ws.Signal()), true
+}
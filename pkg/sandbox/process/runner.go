@@ -0,0 +1,424 @@
+// Package process implements a process-level sandbox backend for hosts
+// without Docker.
+//
+// Every run is confined with bubblewrap or nsjail: fresh namespaces
+// (no network), read-only host toolchains, a private /tmp and the job
+// directory as the only writable mount. Compilers and interpreters are
+// taken from the host, so the image of a runtime is ignored.
+package process
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/anurag-327/neuron/config"
+	"github.com/anurag-327/neuron/internal/models"
+	"github.com/anurag-327/neuron/internal/registry"
+	fileUtils "github.com/anurag-327/neuron/internal/util/file"
+	"github.com/anurag-327/neuron/pkg/sandbox"
+)
+
+// Tools that can confine a run.
+const (
+	ToolBubblewrap = "bwrap"
+	ToolNsjail     = "nsjail"
+
+	// ToolPrlimit sets the resource limits of bubblewrap jails.
+	ToolPrlimit = "prlimit"
+)
+
+// Runner is the process sandbox backend.
+//
+// It keeps no warm state; a runtime's pool MaxSize only bounds how
+// many of its runs may execute concurrently.
+type Runner struct {
+	tool    string
+	workDir string
+
+	// owner is the user runs execute as when the worker runs as root;
+	// otherwise runs execute as the worker's user.
+	owner *jailOwner
+
+	mu       sync.Mutex
+	runtimes map[string]*runtime
+}
+
+// jailOwner is the unprivileged user that owns job files and runs jobs.
+type jailOwner struct {
+	uid int
+	gid int
+}
+
+// runtime tracks the concurrency slots of one pool.
+type runtime struct {
+	cfg   config.SandboxPoolConfig
+	slots chan struct{}
+}
+
+// NewRunner creates a process backend confining runs with tool
+// (bwrap when empty) and keeping job directories under workDir
+// (a directory in os.TempDir when empty).
+func NewRunner(tool, workDir string) (*Runner, error) {
+	if tool == "" {
+		tool = ToolBubblewrap
+	}
+	if tool != ToolBubblewrap && tool != ToolNsjail {
+		return nil, fmt.Errorf("unsupported process sandbox tool: %s", tool)
+	}
+	if workDir == "" {
+		workDir = filepath.Join(os.TempDir(), "neuron-runner")
+	}
+
+	var owner *jailOwner
+	if os.Geteuid() == 0 {
+		uid, gid, err := sandbox.SandboxIDs()
+		if err != nil {
+			return nil, err
+		}
+		owner = &jailOwner{uid: uid, gid: gid}
+	}
+
+	return &Runner{
+		tool:     tool,
+		workDir:  workDir,
+		owner:    owner,
+		runtimes: map[string]*runtime{},
+	}, nil
+}
+
+// Name implements sandbox.Runner.
+func (r *Runner) Name() string {
+	return registry.BackendProcess
+}
+
+// Init registers every runtime served by the process backend.
+func (r *Runner) Init(ctx context.Context) error {
+	if err := r.Health(); err != nil {
+		return err
+	}
+	// Traversable but not listable: job directories are only found by
+	// their owner
+	if err := os.MkdirAll(r.workDir, 0711); err != nil {
+		return fmt.Errorf("failed to create process sandbox dir: %w", err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, cfg := range config.SandboxPools(registry.BackendProcess) {
+		key := registry.PoolKey(cfg.Language, cfg.Version, cfg.Library)
+		r.runtimes[key] = &runtime{cfg: cfg, slots: make(chan struct{}, cfg.MaxSize)}
+		log.Printf("Registered process sandbox for %s (%s)", key, r.tool)
+	}
+	return nil
+}
+
+// Acquire waits for a free concurrency slot of the runtime.
+func (r *Runner) Acquire(ctx context.Context, req sandbox.Request) (sandbox.Slot, error) {
+	r.mu.Lock()
+	rt := r.runtimes[registry.PoolKey(req.Language, req.Version, req.Library)]
+	r.mu.Unlock()
+
+	if rt == nil {
+		return nil, sandbox.ErrNoRuntime
+	}
+
+	select {
+	case rt.slots <- struct{}{}:
+		return &slot{runner: r, rt: rt}, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Health reports whether the sandbox tool is installed.
+func (r *Runner) Health() error {
+	if _, err := exec.LookPath(r.tool); err != nil {
+		return fmt.Errorf("process sandbox tool %s not found: %w", r.tool, err)
+	}
+	if r.tool == ToolBubblewrap {
+		if _, err := exec.LookPath(ToolPrlimit); err != nil {
+			return fmt.Errorf("%s, which sets the limits of bubblewrap jails, not found: %w", ToolPrlimit, err)
+		}
+	}
+	return nil
+}
+
+// Shutdown is a no-op: runs die with the worker process.
+func (r *Runner) Shutdown() {}
+
+type slot struct {
+	runner *Runner
+	rt     *runtime
+}
+
+func (s *slot) Run(ctx context.Context, req sandbox.Request) sandbox.RunResult {
+	return s.runner.run(ctx, s.rt.cfg, req)
+}
+
+// Release frees the slot. Nothing survives a run, so dirty slots
+// need no cleanup.
+func (s *slot) Release(dirty bool) {
+	<-s.rt.slots
+}
+
+// run writes the job files, executes the run command inside the jail
+// and classifies the result. Limits follow the Docker backend: the
+// in-sandbox timeout decides TLE, the Go timeout is a safety net.
+// Staged requests (see sandbox.Stage) keep the job directory for the
+// next stage.
+func (r *Runner) run(ctx context.Context, cfg config.SandboxPoolConfig, req sandbox.Request) sandbox.RunResult {
+	result := sandbox.RunResult{ExitCode: 1}
+
+	if req.Interactor != "" {
+		result.ErrType = models.ErrInternalError
+		result.ErrMsg = "Interactors are not supported by the process sandbox"
+		return result
+	}
+
+	langCfg, ok := registry.LanguageRegistry[req.Language]
+	if !ok {
+		result.ErrType = models.ErrInternalError
+		result.ErrMsg = "Unsupported language"
+		return result
+	}
+
+	versionCfg, err := langCfg.ResolveRuntime(req.Version, req.Library)
+	if err != nil {
+		result.ErrType = models.ErrInternalError
+		result.ErrMsg = "Unsupported language version or library set"
+		return result
+	}
+
+	// 1 Job directory, mounted at the same path the Docker backend uses
+	jobDir := filepath.Join(r.workDir, "job_"+req.JobID)
+	jobPath := "/sandbox/job_" + req.JobID
+
+	if req.Stage == sandbox.StageCleanup {
+		if err := fileUtils.DeleteFolder(jobDir); err != nil {
+			log.Printf("[RUN] failed to delete job directory %s: %v", jobDir, err)
+		}
+		return sandbox.RunResult{}
+	}
+
+	if err := os.MkdirAll(jobDir, 0700); err != nil {
+		result.ErrType = models.ErrInternalError
+		result.ErrMsg = "Failed to create job directory"
+		return result
+	}
+	if req.Stage == sandbox.StageAll {
+		defer fileUtils.DeleteFolder(jobDir)
+	}
+
+	names := sandbox.BuildFileNames(jobDir, langCfg, req.Args)
+
+	// 2 Write user code, input and data files; a compiled job only gets
+	// its next input
+	if req.Stage != sandbox.StageRun {
+		if err := fileUtils.WriteContentToFile(names.PathFull, []byte(req.Code), 0600); err != nil {
+			result.ErrType = models.ErrInternalError
+			result.ErrMsg = "Failed to write code"
+			return result
+		}
+		if err := writeDataFiles(jobDir, req.Files); err != nil {
+			result.ErrType = models.ErrInternalError
+			result.ErrMsg = "Failed to write data files"
+			return result
+		}
+	}
+	if req.Stage != sandbox.StageCompile {
+		if err := writeInput(jobDir, req.Input); err != nil {
+			result.ErrType = models.ErrInternalError
+			result.ErrMsg = "Failed to write input"
+			return result
+		}
+	}
+	if err := r.chownJobDir(jobDir); err != nil {
+		result.ErrType = models.ErrInternalError
+		result.ErrMsg = "Failed to set job file owner"
+		return result
+	}
+
+	// 3 Build jailed command
+	runCmd, runTimeout, err := sandbox.StageCommand(versionCfg, names, req)
+	if err != nil {
+		result.ErrType = models.ErrInternalError
+		result.ErrMsg = "Failed to build run command"
+		return result
+	}
+	if runCmd == "" {
+		return sandbox.RunResult{}
+	}
+
+	execTimeout := runTimeout + time.Second
+	shell := sandbox.ShellCommand(jobPath, runCmd, runTimeout)
+	env := sandbox.EnvList(req.Env)
+
+	var args []string
+	switch r.tool {
+	case ToolNsjail:
+		args = nsjailArgs(jobDir, jobPath, cfg, execTimeout, r.owner, env, shell)
+	default:
+		args = bwrapArgs(jobDir, jobPath, cfg, execTimeout, env, shell)
+	}
+
+	// 4 Execute with Go timeout as safety net
+	execCtx, cancel := context.WithTimeout(ctx, execTimeout)
+	defer cancel()
+
+	var stdoutBuf, stderrBuf sandbox.OutputBuffer
+	cmd := exec.CommandContext(execCtx, args[0], args[1:]...)
+	cmd.Stdout = &stdoutBuf
+	cmd.Stderr = &stderrBuf
+
+	// nsjail needs root to set up the jail and drops to the owner
+	// itself; bubblewrap runs unprivileged from the start
+	if r.owner != nil && r.tool == ToolBubblewrap {
+		cmd.SysProcAttr = &syscall.SysProcAttr{
+			Credential: &syscall.Credential{Uid: uint32(r.owner.uid), Gid: uint32(r.owner.gid)},
+		}
+	}
+
+	started := time.Now()
+	err = cmd.Run()
+	result.Elapsed = time.Since(started)
+	if errors.Is(execCtx.Err(), context.DeadlineExceeded) {
+		result.ErrType = models.ErrTLE
+		result.ErrMsg = models.MsgTLE
+		return result
+	}
+
+	var exitErr *exec.ExitError
+	if err != nil && !errors.As(err, &exitErr) {
+		log.Printf("[RUN] process sandbox failed to start: %v", err)
+		result.ErrType = models.ErrSandboxError
+		result.ErrMsg = "Failed to start sandbox"
+		return result
+	}
+
+	// 5 Classify
+	res := sandbox.ProcessResult(req.Language, exitCode(cmd), stdoutBuf.String(), stderrBuf.String(), jobPath)
+	result.ErrType = res.ErrorType
+	result.ErrMsg = res.ErrorMessage
+	result.Stdout = res.Stdout
+	result.Stderr = res.Stderr
+	result.ExitCode = res.ExitCode
+	result.Diagnostics = res.Diagnostics
+	if req.Stage == sandbox.StageCompile {
+		return sandbox.CompileResult(result)
+	}
+
+	// 6 Collect artifacts before the job directory is removed
+	if len(req.Artifacts) > 0 {
+		collector, err := collectArtifacts(jobDir, req.Artifacts)
+		if err != nil {
+			log.Printf("[RUN] failed to collect artifacts: %v", err)
+		} else {
+			result.Artifacts = collector.Artifacts
+			result.ArtifactsSkipped = collector.Skipped
+		}
+	}
+	return result
+}
+
+// writeInput writes the input of a run to the job directory. A file
+// left at its place by an earlier run of the same job, e.g. a symlink,
+// is removed first rather than written through.
+func writeInput(jobDir, input string) error {
+	name := filepath.Join(jobDir, sandbox.InputFileName)
+	if err := os.Remove(name); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	f, err := os.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.WriteString(input); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// writeDataFiles writes the data files of a submission read-only into
+// the job directory. Names are validated by sandbox.ValidateDataFiles.
+func writeDataFiles(jobDir string, files []models.DataFile) error {
+	for _, f := range files {
+		dst := filepath.Join(jobDir, filepath.FromSlash(f.Name))
+		if err := os.MkdirAll(filepath.Dir(dst), 0700); err != nil {
+			return err
+		}
+		if err := fileUtils.WriteContentToFile(dst, f.Content, 0400); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// chownJobDir gives the job directory and everything in it to the
+// owner of runs, when runs have one.
+func (r *Runner) chownJobDir(jobDir string) error {
+	if r.owner == nil {
+		return nil
+	}
+	return filepath.WalkDir(jobDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		return os.Lchown(p, r.owner.uid, r.owner.gid)
+	})
+}
+
+// collectArtifacts walks the job directory and keeps the regular files
+// matching globs. Symlinks are neither followed nor collected.
+func collectArtifacts(jobDir string, globs []string) (*sandbox.ArtifactCollector, error) {
+	collector := sandbox.NewArtifactCollector(globs)
+	err := filepath.WalkDir(jobDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || !d.Type().IsRegular() {
+			return err
+		}
+		rel, err := filepath.Rel(jobDir, p)
+		if err != nil {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		f, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		return collector.Collect(filepath.ToSlash(rel), info.Size(), f)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return collector, nil
+}
+
+// exitCode returns the shell-style exit code of a finished command:
+// 128+N when it was killed by signal N.
+func exitCode(cmd *exec.Cmd) int64 {
+	state := cmd.ProcessState
+	if state == nil {
+		return 1
+	}
+	if code := state.ExitCode(); code >= 0 {
+		return int64(code)
+	}
+	if sig, ok := signalOf(state); ok {
+		return int64(128 + sig)
+	}
+	return 1
+}
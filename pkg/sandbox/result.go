@@ -1,13 +1,16 @@
-package docker
+package sandbox
 
 import (
+	"bytes"
 	"fmt"
 	"path/filepath"
 	"regexp"
 	"strings"
 
+	"github.com/anurag-327/neuron/config"
 	"github.com/anurag-327/neuron/internal/models"
 	"github.com/anurag-327/neuron/internal/registry"
+	"github.com/anurag-327/neuron/pkg/diagnostics"
 )
 
 func DetectLanguageError(language, stdout, stderr string) (models.SandboxError, string) {
@@ -15,73 +18,14 @@ func DetectLanguageError(language, stdout, stderr string) (models.SandboxError,
 	s := stderr
 	c := stdout + "\n" + stderr // Some runtime errors print to stdout
 
-	// C++
-	if language == "cpp" {
-		// Compiler errors (from g++)
-		if strings.Contains(s, "error:") ||
-			strings.Contains(s, "fatal error:") ||
-			strings.Contains(s, "undefined reference") {
+	// Language-specific patterns come from the language definition
+	if langCfg, ok := registry.LanguageRegistry[language]; ok {
+		// Compiler errors are only reported on stderr
+		if containsAny(s, langCfg.Errors.Compile) {
 			return models.ErrCompilationError, models.MsgCompilationError
 		}
 
-		// Runtime crash detection
-		if strings.Contains(s, "Segmentation fault") ||
-			strings.Contains(s, "core dumped") ||
-			strings.Contains(s, "abort") ||
-			strings.Contains(s, "floating point exception") {
-			return models.ErrRuntimeError, models.MsgRuntimeError
-		}
-
-	}
-
-	// Go
-	if language == "go" {
-		if strings.Contains(s, "undefined:") ||
-			strings.Contains(s, "cannot use") ||
-			strings.Contains(s, "no required module") {
-			return models.ErrCompilationError, models.MsgCompilationError
-		}
-
-		if strings.Contains(c, "panic:") ||
-			strings.Contains(c, "runtime error:") {
-			return models.ErrRuntimeError, models.MsgRuntimeError
-		}
-	}
-
-	// Python
-	if language == "python" {
-		if strings.Contains(s, "SyntaxError") ||
-			strings.Contains(s, "IndentationError") {
-			return models.ErrCompilationError, models.MsgCompilationError
-		}
-
-		if strings.Contains(c, "Traceback (most recent call last):") {
-			return models.ErrRuntimeError, models.MsgRuntimeError
-		}
-	}
-
-	// Java
-	if language == "java" {
-		if strings.Contains(s, "error:") ||
-			strings.Contains(s, "cannot find symbol") ||
-			strings.Contains(s, "symbol not found") {
-			return models.ErrCompilationError, models.MsgCompilationError
-		}
-
-		if strings.Contains(c, "Exception in thread") {
-			return models.ErrRuntimeError, models.MsgRuntimeError
-		}
-	}
-
-	// JavaScript (Node.js)
-	if language == "javascript" {
-		if strings.Contains(s, "SyntaxError:") {
-			return models.ErrCompilationError, models.MsgCompilationError
-		}
-
-		if strings.Contains(c, "TypeError:") ||
-			strings.Contains(c, "ReferenceError:") ||
-			strings.Contains(c, "UnhandledPromiseRejectionWarning") {
+		if containsAny(c, langCfg.Errors.Runtime) {
 			return models.ErrRuntimeError, models.MsgRuntimeError
 		}
 	}
@@ -97,6 +41,16 @@ func DetectLanguageError(language, stdout, stderr string) (models.SandboxError,
 	return models.ErrRuntimeError, models.MsgRuntimeError
 }
 
+// DetectDiagnostics parses stderr with the diagnostics parsers of the
+// language; see registry.ErrorPatterns.
+func DetectDiagnostics(language, stderr string) []models.Diagnostic {
+	langCfg, ok := registry.LanguageRegistry[language]
+	if !ok {
+		return nil
+	}
+	return diagnostics.Parse(stderr, langCfg.Errors.Diagnostics)
+}
+
 func isMeaningfulRuntimeErrorGeneric(stderr string) bool {
 	s := strings.ToLower(stderr)
 
@@ -144,13 +98,23 @@ func isMeaningfulRuntimeErrorGeneric(stderr string) bool {
 	return false
 }
 
-func BuildFileNames(basePath string, cfg registry.LanguageConfig) registry.FileNames {
+func containsAny(s string, patterns []string) bool {
+	for _, p := range patterns {
+		if strings.Contains(s, p) {
+			return true
+		}
+	}
+	return false
+}
+
+func BuildFileNames(basePath string, cfg registry.LanguageConfig, args []string) registry.FileNames {
 	full := cfg.BaseName + "." + cfg.Ext
 	return registry.FileNames{
 		BaseName: cfg.BaseName,
 		FullName: full,
 		PathBase: filepath.Join(basePath, cfg.BaseName),
 		PathFull: filepath.Join(basePath, full),
+		Args:     QuoteArgs(args),
 	}
 }
 
@@ -161,35 +125,39 @@ type ResultResponse struct {
 	ExitCode     int64               `json:"error_code"`
 	Stdout       string              `json:"stdout"`
 	Stderr       string              `json:"stderr"`
+	Diagnostics  []models.Diagnostic `json:"diagnostics,omitempty"`
 }
 
 func ProcessResult(lang string, status int64, stdout, stderr string, jobID string) ResultResponse {
-	// Truncate first to prevent massive strings from hitting regex or downstream DB
-	const MaxOutputSize = 256 * 1024 // 256KB
-	stdout = TruncateOutput(stdout, MaxOutputSize)
-	stderr = TruncateOutput(stderr, MaxOutputSize)
+	// Truncate first to prevent massive strings from hitting downstream
+	// storage; errors are detected on the beginning of the output only
+	const detectSize = 256 * 1024 // 256KB
+	stdout = TruncateOutput(stdout, config.MaxOutputSize)
+	stderr = TruncateOutput(stderr, config.MaxOutputSize)
 
 	cleanStderr := SanitizeOutput(stderr, jobID)
 	cleanStdout := SanitizeOutput(stdout, jobID)
+	detectStdout := TruncateOutput(cleanStdout, detectSize)
+	detectStderr := TruncateOutput(cleanStderr, detectSize)
 
 	switch status {
 	case 0: // Success
 		return ResultResponse{Status: "success", ErrorType: "", ErrorMessage: "", ExitCode: status, Stdout: cleanStdout, Stderr: cleanStderr}
 
 	case 1: // Runtime Error
-		errorType, message := DetectLanguageError(lang, cleanStdout, cleanStderr)
-		return ResultResponse{Status: "success", ErrorType: errorType, ErrorMessage: message, ExitCode: status, Stdout: cleanStdout, Stderr: cleanStderr}
+		errorType, message := DetectLanguageError(lang, detectStdout, detectStderr)
+		return ResultResponse{Status: "success", ErrorType: errorType, ErrorMessage: message, ExitCode: status, Stdout: cleanStdout, Stderr: cleanStderr, Diagnostics: DetectDiagnostics(lang, detectStderr)}
 
 	case 124, 143, 137: // SIGTERM / OOM / Timeout
 		// For TLE/OOM, output is often huge/infinite or irrelevant. Discard it.
 		return ResultResponse{Status: "success", ErrorType: models.ErrTLE, ErrorMessage: models.MsgTLE, ExitCode: status, Stdout: "", Stderr: ""}
 
 	case 139, 136, 134: // Segmentation Fault (SIGSEGV)
-		return ResultResponse{Status: "success", ErrorType: models.ErrRuntimeError, ErrorMessage: models.MsgRuntimeError, ExitCode: status, Stdout: cleanStdout, Stderr: cleanStderr}
+		return ResultResponse{Status: "success", ErrorType: models.ErrRuntimeError, ErrorMessage: models.MsgRuntimeError, ExitCode: status, Stdout: cleanStdout, Stderr: cleanStderr, Diagnostics: DetectDiagnostics(lang, detectStderr)}
 
 	default:
 		fmt.Println("Unknown exit code:", status)
-		errorType, message := DetectLanguageError(lang, cleanStdout, cleanStderr)
+		errorType, message := DetectLanguageError(lang, detectStdout, detectStderr)
 		return ResultResponse{
 			Status:       "error",
 			ErrorType:    errorType,
@@ -197,6 +165,7 @@ func ProcessResult(lang string, status int64, stdout, stderr string, jobID strin
 			ExitCode:     status,
 			Stdout:       cleanStdout,
 			Stderr:       cleanStderr,
+			Diagnostics:  DetectDiagnostics(lang, detectStderr),
 		}
 	}
 }
@@ -207,6 +176,26 @@ func SanitizeOutput(input string, jobID string) string {
 	return re.ReplaceAllString(input, "./")
 }
 
+// OutputBuffer captures an output of a run. It keeps the first
+// config.MaxOutputSize+1 bytes and discards the rest while reporting
+// them written, so the program is not blocked and cannot exhaust the
+// worker's memory; the extra byte makes ProcessResult mark the output
+// truncated.
+type OutputBuffer struct {
+	buf bytes.Buffer
+}
+
+func (b *OutputBuffer) Write(p []byte) (int, error) {
+	if room := config.MaxOutputSize + 1 - b.buf.Len(); room > 0 {
+		b.buf.Write(p[:min(len(p), room)])
+	}
+	return len(p), nil
+}
+
+func (b *OutputBuffer) String() string {
+	return b.buf.String()
+}
+
 func TruncateOutput(input string, limit int) string {
 	if len(input) > limit {
 		return input[:limit] + "\n... [Output Truncated]"
@@ -1,7 +1,7 @@
 // Package sandbox contains helper utilities for executing sandbox jobs.
 // It is responsible for:
 //   - Parsing job payloads
-//   - Acquiring containers from the pool
+//   - Acquiring execution slots from a sandbox backend
 //   - Executing user code inside a sandbox
 //   - Persisting job state transitions (running → success / failed)
 package sandbox
@@ -9,17 +9,17 @@ package sandbox
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"time"
 
 	"github.com/anurag-327/neuron/config"
-	"github.com/anurag-327/neuron/conn"
 	"github.com/anurag-327/neuron/internal/models"
+	"github.com/anurag-327/neuron/internal/registry"
 	"github.com/anurag-327/neuron/internal/repository"
 	"github.com/anurag-327/neuron/internal/services"
-	"github.com/anurag-327/neuron/pkg/sandbox/docker"
-	"github.com/anurag-327/neuron/pkg/sandbox/docker/pool"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 // failJob updates the job as FAILED and persists the failure state.
@@ -57,25 +57,38 @@ func failJob(
 	return nil
 }
 
+// saveOutput stores an output of a job, moving large output to the
+// object store. When that fails the output is cut to what fits inline.
+func saveOutput(ctx context.Context, jobID primitive.ObjectID, stream, output string) (string, *models.OutputBlob) {
+	inline, blob, err := services.SaveOutput(ctx, jobID, stream, output)
+	if err != nil {
+		log.Printf("failed to store %s of job %s: %v", stream, jobID.Hex(), err)
+		return TruncateOutput(output, len(services.OutputPreview(output))), nil
+	}
+	return inline, blob
+}
+
 // ExecuteCode is the main entry point for sandbox execution.
 
 // It is responsible for:
 //   - Parsing job payloads
-//   - Acquiring containers from the pool
+//   - Acquiring execution slots from a sandbox backend
 //   - Executing user code inside a sandbox
 //   - Persisting job state transitions (running → success / failed)
 
 // Lifecycle:
 //  1. Parse incoming job payload
-//  2. Acquire a warm container from pool
-//  3. Mark job as RUNNING
-//  4. Execute user code inside sandbox
-//  5. Persist stdout/stderr/results
-//  6. Return container back to pool
+//  2. Pick the sandbox backend of the job runtime
+//  3. Acquire an execution slot from the backend
+//  4. Mark job as RUNNING
+//  5. Execute user code inside sandbox
+//  6. Release the slot back to the backend
+//  7. Judge stdout with the job's checker, if any
+//  8. Persist stdout/stderr/results
 //
 // This function is intentionally synchronous:
 // - Caller controls concurrency
-// - Pool enforces execution limits
+// - Backends enforce execution limits
 func ExecuteCode(jobBytes []byte) error {
 
 	var job models.Job
@@ -90,27 +103,80 @@ func ExecuteCode(jobBytes []byte) error {
 	}
 
 	// -----------------------------
-	// 2) Initialize Docker runner
+	// 2) Pick sandbox backend
 	// -----------------------------
-	dC, dockerErr := conn.GetDockerClient()
-	if dockerErr != nil {
-		log.Println("[RUN] failed to get docker client:", dockerErr)
-		failJob(ctx, &job, models.ErrSandboxError, "failed to initiate sandboxed container")
-		return fmt.Errorf("failed to get docker client: %w", dockerErr)
+	langCfg, ok := registry.LanguageRegistry[job.Language]
+	if !ok {
+		log.Println("[RUN] unsupported language:", job.Language)
+		failJob(ctx, &job, models.ErrInternalError, "unsupported language")
+		return fmt.Errorf("unsupported language %q", job.Language)
+	}
+
+	// Jobs queued before versions existed carry no version;
+	// they run on the language default.
+	if job.Version == "" {
+		job.Version = langCfg.DefaultVersion
+	}
+
+	versionCfg, err := langCfg.ResolveRuntime(job.Version, job.Library)
+	if err != nil {
+		log.Println("[RUN] unsupported runtime:", err)
+		failJob(ctx, &job, models.ErrInternalError, "unsupported language")
+		return err
+	}
+
+	r, ok := GetRunner(versionCfg.Sandbox.Backend)
+	if !ok {
+		log.Println("[RUN] sandbox backend not available:", versionCfg.Sandbox.Backend)
+		failJob(ctx, &job, models.ErrSandboxError, "failed to initiate sandbox")
+		return fmt.Errorf("sandbox backend %q not available", versionCfg.Sandbox.Backend)
+	}
+
+	req := Request{
+		JobID:    job.ID.Hex(),
+		Code:     job.Code,
+		Input:    job.Input,
+		Language: job.Language,
+		Version:  job.Version,
+		Library:  job.Library,
+
+		Artifacts: job.ArtifactGlobs,
+
+		Args:  job.Args,
+		Env:   job.Env,
+		Files: job.Files,
+	}
+
+	// Problem jobs run against the problem's tests
+	var problem *models.Problem
+	if job.ProblemID != nil {
+		problem, err = repository.GetProblemByID(ctx, *job.ProblemID)
+		if err != nil {
+			log.Println("[RUN] problem not available:", err)
+			failJob(ctx, &job, models.ErrInternalError, "problem not available")
+			return err
+		}
+		req.TimeLimit = time.Duration(problem.TimeLimitMs) * time.Millisecond
+		if problem.Interactor != nil {
+			req.Interactor = problem.Interactor.Code
+		}
+	}
+	if job.TimeLimitMs > 0 {
+		limit := time.Duration(job.TimeLimitMs) * time.Millisecond
+		if req.TimeLimit == 0 || limit < req.TimeLimit {
+			req.TimeLimit = limit
+		}
 	}
-	r := docker.NewRunner(dC)
 
 	// -----------------------------
-	// 3) Acquire warm container
+	// 3) Acquire execution slot
 	// -----------------------------
-	p := pool.Manager.GetPool(job.Language)
-	if p == nil {
-		log.Println("[RUN] no pool for language:", job.Language)
+	slot, err := r.Acquire(ctx, req)
+	if errors.Is(err, ErrNoRuntime) {
+		log.Println("[RUN] no pool for language:", job.Language, job.Version, job.Library)
 		failJob(ctx, &job, models.ErrInternalError, "unsupported language")
 		return fmt.Errorf("no pool for language")
 	}
-
-	containerID, err := p.Get(ctx)
 	if err != nil {
 		log.Println("[RUN] pool exhausted:", err)
 		failJob(ctx, &job, models.ErrInternalError, "failed to acquire a container")
@@ -118,8 +184,8 @@ func ExecuteCode(jobBytes []byte) error {
 	}
 
 	// NOTE:
-	// DO NOT defer Put() here.
-	// Container lifecycle depends on execution result.
+	// DO NOT defer Release() here.
+	// Slot lifecycle depends on execution result.
 
 	// -----------------------------
 	// 4) Mark job as RUNNING
@@ -128,6 +194,7 @@ func ExecuteCode(jobBytes []byte) error {
 	job.StartedAt = time.Now()
 
 	if _, err := repository.SaveJob(ctx, &job); err != nil {
+		slot.Release(false)
 		failJob(ctx, &job, models.ErrInternalError, "Failed to update running state")
 		return fmt.Errorf("cannot update job state: %w", err)
 	}
@@ -135,34 +202,34 @@ func ExecuteCode(jobBytes []byte) error {
 	// -----------------------------
 	// 5) Execute user code
 	// -----------------------------
-	basePath := fmt.Sprintf("/tmp/runner/job_%s", job.ID.Hex())
-
-	runResult := r.Run(
-		ctx,
-		containerID,
-		basePath,
-		job.Code,
-		job.Input,
-		job.Language,
-	)
+	var runResult RunResult
+	job.Verdict, job.VerdictMessage = "", ""
 
-	// -----------------------------
-	// 6) Handle container lifecycle
-	// -----------------------------
-	if runResult.ContainerDirty {
-		log.Println("[POOL] destroying dirty container:", containerID)
-		p.ReplaceContainer(containerID)
+	if problem != nil {
+		// Runs, releases and judges each test
+		runResult = runTests(ctx, r, slot, req, &job, problem)
 	} else {
-		log.Println("[POOL] returning clean container:", containerID)
-		p.Put(containerID)
+		runResult = slot.Run(ctx, req)
+
+		// -----------------------------
+		// 6) Handle slot lifecycle
+		// -----------------------------
+		slot.Release(runResult.Dirty)
+
+		// -----------------------------
+		// 7) Judge the output
+		// -----------------------------
+		if job.Checker != nil && runResult.ErrType == "" {
+			job.Verdict, job.VerdictMessage = CheckOutput(ctx, &job, runResult.Stdout)
+		}
 	}
 
 	// -----------------------------
-	// 7) Persist execution result
+	// 8) Persist execution result
 	// -----------------------------
 	job.FinishedAt = time.Now()
-	job.Stdout = runResult.Stdout
-	job.Stderr = runResult.Stderr
+	job.Stdout, job.StdoutBlob = saveOutput(ctx, job.ID, "stdout", runResult.Stdout)
+	job.Stderr, job.StderrBlob = saveOutput(ctx, job.ID, "stderr", runResult.Stderr)
 
 	job.SandboxErrorType = nil
 	if runResult.ErrType != "" {
@@ -170,6 +237,19 @@ func ExecuteCode(jobBytes []byte) error {
 	}
 	job.SandboxErrorMessage = runResult.ErrMsg
 	job.ExitCode = runResult.ExitCode
+	job.Diagnostics = runResult.Diagnostics
+
+	job.Artifacts = nil
+	job.ArtifactsSkipped = runResult.ArtifactsSkipped
+	for _, a := range runResult.Artifacts {
+		artifact, err := services.SaveArtifact(ctx, job.ID, a.Name, a.Content)
+		if err != nil {
+			log.Printf("failed to store artifact %s of job %s: %v", a.Name, job.ID.Hex(), err)
+			job.ArtifactsSkipped++
+			continue
+		}
+		job.Artifacts = append(job.Artifacts, artifact)
+	}
 
 	switch runResult.ErrType {
 	case models.ErrSandboxError, models.ErrInternalError:
@@ -183,20 +263,31 @@ func ExecuteCode(jobBytes []byte) error {
 		return err
 	}
 
+	// Results with stored outputs are not cached: the cache keeps
+	// outputs inline
+	if job.CacheKey != "" && runResult.ErrType == "" && job.StdoutBlob == nil && job.StderrBlob == nil {
+		if err := services.StoreCachedResult(ctx, &job, versionCfg); err != nil {
+			log.Printf("failed to cache the result of job %s: %v", job.ID.Hex(), err)
+		}
+	}
+
 	if runResult.ErrType == "" {
 		executionTime := job.FinishedAt.Sub(job.StartedAt)
 		queueTime := job.StartedAt.Sub(job.QueuedAt)
 		totalTime := job.FinishedAt.Sub(job.QueuedAt)
-		amount := config.GetCreditsForReason(models.CreditReasonSubmission)
+		reason := job.CreditReason()
+		amount := config.GetJobCredits(reason, langCfg)
 
 		err = services.DeductCreditsAndLog(
 			ctx,
 			job.UserID,
 			amount,
-			models.CreditReasonSubmission,
+			reason,
 			&job.ID,
 			map[string]interface{}{
 				"language":      job.Language,
+				"version":       job.Version,
+				"library":       job.Library,
 				"executionTime": executionTime,
 				"queueTime":     queueTime,
 				"totalTime":     totalTime,
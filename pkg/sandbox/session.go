@@ -0,0 +1,186 @@
+package sandbox
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"sync/atomic"
+	"time"
+
+	"github.com/anurag-327/neuron/config"
+	"github.com/anurag-327/neuron/internal/models"
+	"github.com/anurag-327/neuron/internal/registry"
+	"github.com/anurag-327/neuron/internal/repository"
+	"github.com/anurag-327/neuron/internal/services"
+)
+
+// InteractiveSlot is implemented by slots that can run interactive
+// sessions.
+type InteractiveSlot interface {
+	Slot
+
+	// Interact runs req with stdin attached and streams its output
+	// until the program exits, limit passes or ctx is cancelled.
+	// Only ExitCode, ErrType and ErrMsg of the result are set; the
+	// slot must be released dirty afterwards.
+	Interact(ctx context.Context, req Request, limit time.Duration, stdin io.Reader, stdout, stderr io.Writer) RunResult
+}
+
+var errSessionIdle = errors.New("session idle")
+
+// RunSession serves an active session: it reserves the credits of the
+// session's whole run, acquires a slot of the session runtime, runs the
+// program interactively, then persists the outcome, bills the session
+// duration and refunds the rest of the reservation.
+//
+// The session ends when the program exits, at the wall-clock limit
+// (config.SessionWallLimit, capped by the account balance), after
+// config.SessionIdleLimit without input or output, or when ctx is
+// cancelled because the client went away.
+func RunSession(ctx context.Context, session *models.Session, stdin io.Reader, stdout, stderr io.Writer) *models.Session {
+	started := time.Now()
+	if !session.StartedAt.IsZero() {
+		started = session.StartedAt
+	}
+
+	// Only sessions whose program ran are billed
+	ran := false
+
+	finish := func(reason models.SessionCloseReason, message string) *models.Session {
+		// Persist and bill even when the client context is gone
+		persistCtx := context.Background()
+
+		session.Status = models.SessionClosed
+		session.EndedAt = time.Now()
+		session.DurationMs = session.EndedAt.Sub(started).Milliseconds()
+		session.CloseReason = reason
+		session.ErrorMessage = message
+
+		services.SettleSession(persistCtx, session, ran)
+		if _, err := repository.SaveSession(persistCtx, session); err != nil {
+			log.Printf("[SESSION] failed to save session %s: %v", session.ID.Hex(), err)
+		}
+		return session
+	}
+
+	limit, err := services.ReserveSession(ctx, session)
+	if errors.Is(err, repository.ErrInsufficientCredits) {
+		return finish(models.SessionFailed, "insufficient credits")
+	}
+	if err != nil {
+		return finish(models.SessionFailed, "failed to reserve credits")
+	}
+
+	langCfg, ok := registry.LanguageRegistry[session.Language]
+	if !ok {
+		return finish(models.SessionFailed, "unsupported language")
+	}
+	versionCfg, err := langCfg.ResolveRuntime(session.Version, session.Library)
+	if err != nil {
+		return finish(models.SessionFailed, "unsupported language")
+	}
+	r, ok := GetRunner(versionCfg.Sandbox.Backend)
+	if !ok {
+		return finish(models.SessionFailed, "failed to initiate sandbox")
+	}
+
+	req := Request{
+		JobID:    session.ID.Hex(),
+		Code:     session.Code,
+		Language: session.Language,
+		Version:  session.Version,
+		Library:  session.Library,
+		Args:     session.Args,
+		Env:      session.Env,
+		Files:    session.Files,
+	}
+
+	acquireCtx, cancelAcquire := context.WithTimeout(ctx, config.SessionAcquireTimeout)
+	slot, err := r.Acquire(acquireCtx, req)
+	cancelAcquire()
+	if err != nil {
+		log.Printf("[SESSION] no sandbox for session %s: %v", session.ID.Hex(), err)
+		return finish(models.SessionFailed, "no sandbox available, try again later")
+	}
+
+	interactive, ok := slot.(InteractiveSlot)
+	if !ok {
+		slot.Release(false)
+		return finish(models.SessionFailed, "interactive sessions are not supported for this runtime")
+	}
+	// Sessions may leave background processes behind; never reuse the slot
+	defer slot.Release(true)
+
+	// Idle watchdog: any input or output counts as activity
+	runCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	var lastActive atomic.Int64
+	lastActive.Store(time.Now().UnixNano())
+	touch := func() { lastActive.Store(time.Now().UnixNano()) }
+
+	go func() {
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-runCtx.Done():
+				return
+			case now := <-ticker.C:
+				if now.Sub(time.Unix(0, lastActive.Load())) > config.SessionIdleLimit {
+					cancel(errSessionIdle)
+					return
+				}
+			}
+		}
+	}()
+
+	ran = true
+	started = time.Now()
+	result := interactive.Interact(
+		runCtx,
+		req,
+		limit,
+		activityReader{stdin, touch},
+		activityWriter{stdout, touch},
+		activityWriter{stderr, touch},
+	)
+	session.ExitCode = result.ExitCode
+
+	switch {
+	case errors.Is(context.Cause(runCtx), errSessionIdle):
+		return finish(models.SessionIdleTimeout, "")
+	case ctx.Err() != nil:
+		return finish(models.SessionClientClosed, "")
+	case result.ErrType == models.ErrTLE:
+		return finish(models.SessionWallTimeout, "")
+	case result.ErrType == models.ErrSandboxError || result.ErrType == models.ErrInternalError:
+		return finish(models.SessionFailed, result.ErrMsg)
+	default:
+		return finish(models.SessionExited, "")
+	}
+}
+
+type activityReader struct {
+	r     io.Reader
+	touch func()
+}
+
+func (a activityReader) Read(p []byte) (int, error) {
+	n, err := a.r.Read(p)
+	if n > 0 {
+		a.touch()
+	}
+	return n, err
+}
+
+type activityWriter struct {
+	w     io.Writer
+	touch func()
+}
+
+func (a activityWriter) Write(p []byte) (int, error) {
+	a.touch()
+	return a.w.Write(p)
+}
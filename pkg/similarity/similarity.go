@@ -0,0 +1,226 @@
+// Package similarity detects copied code with winnowing, the
+// fingerprinting algorithm of MOSS (Schleimer, Wilkerson and Aiken,
+// "Winnowing: Local Algorithms for Document Fingerprinting", 2003).
+//
+// Code is lexed per language and normalized so that renaming variables,
+// changing literals, reformatting or editing comments does not change
+// it. Every run of K tokens is hashed, and in every window of Window
+// consecutive hashes the smallest is kept as a fingerprint. Two pieces
+// of code sharing a run of at least K+Window-1 tokens are guaranteed to
+// share a fingerprint, while runs shorter than K are ignored as noise.
+package similarity
+
+import (
+	"hash/fnv"
+
+	"github.com/anurag-327/neuron/internal/models"
+	"github.com/anurag-327/neuron/pkg/analysis"
+)
+
+const (
+	// K is the number of normalized tokens hashed together.
+	K = 8
+
+	// Window is the number of consecutive hashes a fingerprint is
+	// chosen from.
+	Window = 6
+
+	// matchGap is how many lines apart fingerprints may be and still
+	// belong to the same matching region.
+	matchGap = 2
+)
+
+// keywords keep their text when code is normalized; every other
+// identifier becomes the same placeholder. The list is shared by all
+// languages: a keyword of one language is a rare name in another.
+var keywords = map[string]bool{}
+
+func init() {
+	for _, k := range []string{
+		"abstract", "and", "as", "async", "await", "bool", "boolean", "break",
+		"byte", "case", "catch", "char", "class", "const", "continue", "def",
+		"default", "defer", "del", "do", "double", "elif", "else", "elsif",
+		"end", "enum", "except", "extends", "false", "final", "finally",
+		"float", "fn", "for", "foreach", "fun", "func", "function", "go",
+		"goto", "if", "impl", "implements", "import", "in", "int", "interface",
+		"is", "lambda", "let", "long", "loop", "map", "match", "mut", "new",
+		"nil", "none", "not", "null", "object", "or", "package", "pass",
+		"private", "protected", "pub", "public", "raise", "range", "return",
+		"select", "self", "short", "signed", "sizeof", "static", "string",
+		"struct", "super", "switch", "this", "throw", "throws", "trait",
+		"true", "try", "type", "typedef", "union", "unless", "unsigned",
+		"until", "use", "val", "var", "void", "when", "where", "while",
+		"with", "yield",
+	} {
+		keywords[k] = true
+	}
+}
+
+// Region is a range of lines of code.
+type Region struct {
+	StartLine int `json:"startLine"`
+	EndLine   int `json:"endLine"`
+}
+
+// Match is a region of code found in both sides of a comparison.
+type Match struct {
+	A Region `json:"a"`
+	B Region `json:"b"`
+}
+
+// Fingerprints returns the winnowed fingerprints of code, lexed with
+// the named syntax (see analysis.Tokenize), in the order they appear.
+// Code of fewer than K tokens has none.
+func Fingerprints(syntax, code string) []models.Fingerprint {
+	toks := analysis.Tokenize(syntax, code)
+	if len(toks) < K {
+		return nil
+	}
+
+	hashes := make([]int64, len(toks)-K+1)
+	for i := range hashes {
+		h := fnv.New64a()
+		for _, t := range toks[i : i+K] {
+			h.Write([]byte(normalize(t)))
+			h.Write([]byte{0})
+		}
+		hashes[i] = int64(h.Sum64())
+	}
+
+	var fps []models.Fingerprint
+	last := -1
+	for start := 0; start == 0 || start+Window <= len(hashes); start++ {
+		end := min(start+Window, len(hashes))
+		// The rightmost minimum, so a window shifting over a run of
+		// equal hashes keeps its fingerprint
+		m := start
+		for i := start + 1; i < end; i++ {
+			if hashes[i] <= hashes[m] {
+				m = i
+			}
+		}
+		if m == last {
+			continue
+		}
+		last = m
+		fps = append(fps, models.Fingerprint{
+			Hash:      hashes[m],
+			StartLine: toks[m].Line,
+			EndLine:   toks[m+K-1].Line,
+		})
+	}
+	return fps
+}
+
+// normalize maps a token to the text that is hashed.
+func normalize(t analysis.Token) string {
+	switch t.Kind {
+	case analysis.TokenIdent:
+		if keywords[t.Text] {
+			return t.Text
+		}
+		return "$id"
+	case analysis.TokenString:
+		return "$str"
+	case analysis.TokenNumber:
+		return "$num"
+	}
+	return t.Text
+}
+
+// Common returns the hashes found in more than maxShare of sets. Such
+// code is usually boilerplate, e.g. a template handed out with an
+// assignment, and is ignored by Compare. With fewer than three sets no
+// hash is common.
+func Common(sets [][]models.Fingerprint, maxShare float64) map[int64]bool {
+	common := map[int64]bool{}
+	if len(sets) < 3 {
+		return common
+	}
+
+	counts := map[int64]int{}
+	for _, fps := range sets {
+		seen := map[int64]bool{}
+		for _, fp := range fps {
+			if !seen[fp.Hash] {
+				seen[fp.Hash] = true
+				counts[fp.Hash]++
+			}
+		}
+	}
+	for h, n := range counts {
+		if float64(n) > maxShare*float64(len(sets)) {
+			common[h] = true
+		}
+	}
+	return common
+}
+
+// Compare returns the share of the fingerprints of a found in b and of
+// b found in a, ignoring the hashes in ignore, with the regions of code
+// they match in order of a.
+func Compare(a, b []models.Fingerprint, ignore map[int64]bool) (shareA, shareB float64, matches []Match) {
+	inA := map[int64]bool{}
+	for _, fp := range a {
+		if !ignore[fp.Hash] {
+			inA[fp.Hash] = true
+		}
+	}
+	inB := map[int64][]int{}
+	totalB, sharedB := 0, 0
+	for i, fp := range b {
+		if ignore[fp.Hash] {
+			continue
+		}
+		inB[fp.Hash] = append(inB[fp.Hash], i)
+		totalB++
+		if inA[fp.Hash] {
+			sharedB++
+		}
+	}
+
+	totalA, sharedA := 0, 0
+	var cur *Match
+	for _, fp := range a {
+		if ignore[fp.Hash] {
+			continue
+		}
+		totalA++
+		candidates := inB[fp.Hash]
+		if len(candidates) == 0 {
+			continue
+		}
+		sharedA++
+
+		// Extend the current region when the fingerprint follows it on
+		// both sides, otherwise start a new one
+		other := b[candidates[0]]
+		extends := false
+		if cur != nil && fp.StartLine <= cur.A.EndLine+matchGap {
+			for _, i := range candidates {
+				if b[i].StartLine >= cur.B.StartLine && b[i].StartLine <= cur.B.EndLine+matchGap {
+					other, extends = b[i], true
+					break
+				}
+			}
+		}
+		if extends {
+			cur.A.EndLine = max(cur.A.EndLine, fp.EndLine)
+			cur.B.EndLine = max(cur.B.EndLine, other.EndLine)
+			continue
+		}
+		matches = append(matches, Match{
+			A: Region{fp.StartLine, fp.EndLine},
+			B: Region{other.StartLine, other.EndLine},
+		})
+		cur = &matches[len(matches)-1]
+	}
+
+	if totalA > 0 {
+		shareA = float64(sharedA) / float64(totalA)
+	}
+	if totalB > 0 {
+		shareB = float64(sharedB) / float64(totalB)
+	}
+	return shareA, shareB, matches
+}
@@ -0,0 +1,214 @@
+package similarity
+
+import (
+	"testing"
+
+	"github.com/anurag-327/neuron/internal/models"
+)
+
+const original = `def solve(numbers, target):
+    seen = {}
+    for index, value in enumerate(numbers):
+        rest = target - value
+        if rest in seen:
+            return seen[rest], index
+        seen[value] = index
+    return None
+
+print(solve([2, 7, 11, 15], 9))
+`
+
+// renamed is original with other names, literals, comments and layout.
+const renamed = `# two sum
+def find_pair(arr, goal):
+    lookup = {}
+    for i, x in enumerate(arr):
+        need = goal - x
+        if need in lookup:
+            return lookup[need], i   # found
+        lookup[x] = i
+    return None
+
+
+print(find_pair([1, 2, 3, 4], 5))
+`
+
+const unrelated = `import sys
+
+total = 0
+while True:
+    line = sys.stdin.readline()
+    if not line:
+        break
+    total += int(line.strip()) * 2
+print("total:", total)
+`
+
+// extended is original with unrelated code appended.
+const extended = original + `
+def fib(n):
+    a, b = 0, 1
+    while n > 0:
+        a, b = b, a + b
+        n -= 1
+    return a
+
+for k in range(30):
+    print(k, fib(k) % 1000, sum(range(k)))
+`
+
+func hashes(fps []models.Fingerprint) map[int64]bool {
+	set := map[int64]bool{}
+	for _, fp := range fps {
+		set[fp.Hash] = true
+	}
+	return set
+}
+
+func TestFingerprints(t *testing.T) {
+	if fps := Fingerprints("python", "print(1)"); fps != nil {
+		t.Errorf("code shorter than K has %d fingerprints, want none", len(fps))
+	}
+
+	a := hashes(Fingerprints("python", original))
+	b := hashes(Fingerprints("python", renamed))
+	if len(a) == 0 {
+		t.Fatal("no fingerprints")
+	}
+	if len(a) != len(b) {
+		t.Fatalf("renamed code has %d fingerprints, want %d", len(b), len(a))
+	}
+	for h := range a {
+		if !b[h] {
+			t.Errorf("fingerprint %d lost by renaming", h)
+		}
+	}
+
+	for _, fp := range Fingerprints("python", original) {
+		if fp.StartLine < 1 || fp.EndLine < fp.StartLine {
+			t.Errorf("fingerprint lines %d-%d", fp.StartLine, fp.EndLine)
+		}
+	}
+}
+
+func TestCompare(t *testing.T) {
+	fp := func(code string) []models.Fingerprint { return Fingerprints("python", code) }
+	all := hashes(fp(original))
+
+	tests := []struct {
+		name           string
+		a, b           []models.Fingerprint
+		ignore         map[int64]bool
+		minA, maxA     float64
+		minB, maxB     float64
+		wantMatches    bool
+		wantFirstMatch *Match
+	}{
+		{
+			name: "identical",
+			a:    fp(original), b: fp(original),
+			minA: 1, maxA: 1, minB: 1, maxB: 1,
+			wantMatches:    true,
+			wantFirstMatch: &Match{A: Region{1, 10}, B: Region{1, 10}},
+		},
+		{
+			name: "renamed",
+			a:    fp(original), b: fp(renamed),
+			minA: 1, maxA: 1, minB: 1, maxB: 1,
+			wantMatches:    true,
+			wantFirstMatch: &Match{A: Region{1, 10}, B: Region{2, 12}},
+		},
+		{
+			name: "unrelated",
+			a:    fp(original), b: fp(unrelated),
+			maxA: 0.2, maxB: 0.2,
+		},
+		{
+			name: "contained",
+			a:    fp(original), b: fp(extended),
+			minA: 1, maxA: 1, minB: 0.2, maxB: 0.8,
+			wantMatches: true,
+		},
+		{
+			name: "all ignored",
+			a:    fp(original), b: fp(original),
+			ignore: all,
+		},
+		{
+			name: "empty",
+			a:    nil, b: fp(original),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shareA, shareB, matches := Compare(tt.a, tt.b, tt.ignore)
+			if shareA < tt.minA || shareA > tt.maxA {
+				t.Errorf("shareA = %.2f, want %.2f to %.2f", shareA, tt.minA, tt.maxA)
+			}
+			if shareB < tt.minB || shareB > tt.maxB {
+				t.Errorf("shareB = %.2f, want %.2f to %.2f", shareB, tt.minB, tt.maxB)
+			}
+			if (len(matches) > 0) != tt.wantMatches {
+				t.Errorf("got %d matches, want matches: %v", len(matches), tt.wantMatches)
+			}
+			if tt.wantFirstMatch != nil && len(matches) > 0 && matches[0] != *tt.wantFirstMatch {
+				t.Errorf("first match = %+v, want %+v", matches[0], *tt.wantFirstMatch)
+			}
+		})
+	}
+}
+
+func TestCommon(t *testing.T) {
+	fp := func(hashes ...int64) []models.Fingerprint {
+		fps := make([]models.Fingerprint, len(hashes))
+		for i, h := range hashes {
+			fps[i] = models.Fingerprint{Hash: h}
+		}
+		return fps
+	}
+
+	tests := []struct {
+		name     string
+		sets     [][]models.Fingerprint
+		maxShare float64
+		want     []int64
+	}{
+		{
+			name:     "fewer than three sets",
+			sets:     [][]models.Fingerprint{fp(1, 2), fp(1, 2)},
+			maxShare: 0.5,
+		},
+		{
+			name:     "hash in every set",
+			sets:     [][]models.Fingerprint{fp(1, 2), fp(1, 3), fp(1, 4)},
+			maxShare: 0.5,
+			want:     []int64{1},
+		},
+		{
+			name:     "repeats in one set count once",
+			sets:     [][]models.Fingerprint{fp(5, 5, 5), fp(6), fp(7), fp(5)},
+			maxShare: 0.5,
+		},
+		{
+			name:     "above the share",
+			sets:     [][]models.Fingerprint{fp(1, 2), fp(1, 2), fp(1), fp(3)},
+			maxShare: 0.5,
+			want:     []int64{1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Common(tt.sets, tt.maxShare)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Common = %v, want %v", got, tt.want)
+			}
+			for _, h := range tt.want {
+				if !got[h] {
+					t.Errorf("Common = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
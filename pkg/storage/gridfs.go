@@ -0,0 +1,87 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/gridfs"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// GridFS stores objects in a MongoDB GridFS bucket, using the key as the
+// file name. It needs no storage beyond the database both the API and
+// the worker already use.
+type GridFS struct {
+	bucket *gridfs.Bucket
+}
+
+// NewGridFS creates a store on the named bucket of db.
+func NewGridFS(db *mongo.Database, bucket string) (*GridFS, error) {
+	b, err := gridfs.NewBucket(db, options.GridFSBucket().SetName(bucket))
+	if err != nil {
+		return nil, err
+	}
+	return &GridFS{bucket: b}, nil
+}
+
+// Put uploads a new revision and then deletes older ones, so Open
+// always reads the latest.
+func (g *GridFS) Put(ctx context.Context, key string, r io.Reader) error {
+	key = Join(key)
+	id, err := g.bucket.UploadFromStream(key, r)
+	if err != nil {
+		return err
+	}
+	_, err = g.deleteWhere(ctx, bson.M{"filename": key, "_id": bson.M{"$ne": id}})
+	return err
+}
+
+func (g *GridFS) Open(_ context.Context, key string) (io.ReadCloser, error) {
+	stream, err := g.bucket.OpenDownloadStreamByName(Join(key))
+	if errors.Is(err, gridfs.ErrFileNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return stream, nil
+}
+
+func (g *GridFS) Delete(ctx context.Context, key string) error {
+	n, err := g.deleteWhere(ctx, bson.M{"filename": Join(key)})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// deleteWhere deletes every file (all revisions) matching filter and
+// returns how many were deleted.
+func (g *GridFS) deleteWhere(ctx context.Context, filter bson.M) (int, error) {
+	cursor, err := g.bucket.Find(filter)
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	deleted := 0
+	for cursor.Next(ctx) {
+		var file struct {
+			ID interface{} `bson:"_id"`
+		}
+		if err := cursor.Decode(&file); err != nil {
+			return deleted, err
+		}
+		if err := g.bucket.Delete(file.ID); err != nil && !errors.Is(err, gridfs.ErrFileNotFound) {
+			return deleted, err
+		}
+		deleted++
+	}
+	return deleted, cursor.Err()
+}
@@ -0,0 +1,60 @@
+package storage
+
+import (
+	"errors"
+	"io"
+)
+
+// ReadSeeker returns r, holding size bytes, as an io.ReadSeeker for
+// http.ServeContent. Readers that cannot seek, such as GridFS streams,
+// are read forward through skipped bytes, so seeking back to data
+// already read fails; a single range request never does.
+func ReadSeeker(r io.Reader, size int64) io.ReadSeeker {
+	if rs, ok := r.(io.ReadSeeker); ok {
+		return rs
+	}
+	return &forwardSeeker{r: r, size: size}
+}
+
+type forwardSeeker struct {
+	r    io.Reader
+	size int64
+
+	// pos is the offset the next Read starts at and read how much of r
+	// was consumed
+	pos, read int64
+}
+
+func (f *forwardSeeker) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += f.pos
+	case io.SeekEnd:
+		offset += f.size
+	default:
+		return 0, errors.New("storage: invalid whence")
+	}
+	if offset < 0 {
+		return 0, errors.New("storage: negative position")
+	}
+	f.pos = offset
+	return offset, nil
+}
+
+func (f *forwardSeeker) Read(p []byte) (int, error) {
+	if f.pos < f.read {
+		return 0, errors.New("storage: cannot seek backwards")
+	}
+	if f.pos > f.read {
+		n, err := io.CopyN(io.Discard, f.r, f.pos-f.read)
+		f.read += n
+		if err != nil {
+			return 0, err
+		}
+	}
+	n, err := f.r.Read(p)
+	f.read += int64(n)
+	f.pos = f.read
+	return n, err
+}
@@ -0,0 +1,117 @@
+package storage
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+const content = "0123456789abcdefghij"
+
+// stream hides the Seek method of a strings.Reader, like a GridFS stream.
+func stream() io.Reader {
+	return struct{ io.Reader }{strings.NewReader(content)}
+}
+
+func TestReadSeeker(t *testing.T) {
+	type seek struct {
+		offset int64
+		whence int
+	}
+	tests := []struct {
+		name    string
+		seeks   []seek
+		n       int // bytes read after the seeks
+		want    string
+		wantErr bool
+	}{
+		{name: "from the start", n: 5, want: "01234"},
+		{name: "seek start", seeks: []seek{{10, io.SeekStart}}, n: 4, want: "abcd"},
+		{name: "seek end", seeks: []seek{{-3, io.SeekEnd}}, n: 10, want: "hij"},
+		{name: "seek current", seeks: []seek{{4, io.SeekStart}, {2, io.SeekCurrent}}, n: 2, want: "67"},
+		{name: "size probe then rewind", seeks: []seek{{0, io.SeekEnd}, {0, io.SeekStart}}, n: 3, want: "012"},
+		{name: "past the end", seeks: []seek{{30, io.SeekStart}}, n: 1, wantErr: true},
+		{name: "negative position", seeks: []seek{{-1, io.SeekStart}}, wantErr: true},
+		{name: "invalid whence", seeks: []seek{{0, 7}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rs := ReadSeeker(stream(), int64(len(content)))
+			var err error
+			for _, s := range tt.seeks {
+				if _, err = rs.Seek(s.offset, s.whence); err != nil {
+					break
+				}
+			}
+			var got []byte
+			if err == nil {
+				got = make([]byte, tt.n)
+				var n int
+				n, err = io.ReadFull(rs, got)
+				got = got[:n]
+				if err == io.ErrUnexpectedEOF {
+					err = nil
+				}
+			}
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, want error: %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && string(got) != tt.want {
+				t.Errorf("read %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadSeekerBackwards(t *testing.T) {
+	rs := ReadSeeker(stream(), int64(len(content)))
+	if _, err := io.ReadFull(rs, make([]byte, 8)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rs.Seek(2, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rs.Read(make([]byte, 1)); err == nil {
+		t.Error("read after seeking back to consumed data succeeded")
+	}
+}
+
+func TestReadSeekerKeepsSeekers(t *testing.T) {
+	r := strings.NewReader(content)
+	if rs := ReadSeeker(r, int64(len(content))); rs != io.ReadSeeker(r) {
+		t.Error("a seekable reader was wrapped")
+	}
+}
+
+func TestReadSeekerServesRanges(t *testing.T) {
+	tests := []struct {
+		rangeHeader string
+		wantStatus  int
+		want        string
+	}{
+		{"", http.StatusOK, content},
+		{"bytes=5-9", http.StatusPartialContent, "56789"},
+		{"bytes=-4", http.StatusPartialContent, "ghij"},
+		{"bytes=18-", http.StatusPartialContent, "ij"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.rangeHeader, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/file.txt", nil)
+			if tt.rangeHeader != "" {
+				req.Header.Set("Range", tt.rangeHeader)
+			}
+			rec := httptest.NewRecorder()
+			http.ServeContent(rec, req, "file.txt", time.Time{}, ReadSeeker(stream(), int64(len(content))))
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if got := rec.Body.String(); got != tt.want {
+				t.Errorf("body = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,35 @@
+// Package storage stores opaque objects (artifacts, archives) by key.
+//
+// Keys are slash-separated paths such as "artifacts/<jobId>/plot.png".
+// Backends must be safe for concurrent use.
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"path"
+	"strings"
+)
+
+// ErrNotFound is returned by Open and Delete for unknown keys.
+var ErrNotFound = errors.New("object not found")
+
+// Store is an object store.
+type Store interface {
+	// Put stores the content of r under key, replacing any object
+	// already stored there.
+	Put(ctx context.Context, key string, r io.Reader) error
+
+	// Open returns the content stored under key.
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+
+	// Delete removes the object stored under key.
+	Delete(ctx context.Context, key string) error
+}
+
+// Join builds a key from path elements. Elements are cleaned, so the
+// result never starts with "/" or climbs out with "..".
+func Join(elem ...string) string {
+	return strings.TrimPrefix(path.Clean("/"+path.Join(elem...)), "/")
+}
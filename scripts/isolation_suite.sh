@@ -0,0 +1,119 @@
+#!/bin/bash
+#
+# Checks job file isolation in containers set up like the pooled ones:
+# a private tmpfs-backed /sandbox volume, the job directory copied in as
+# a tar owned by the job user, and every exec running as that user.
+#
+# Each program under scripts/testdata/isolation/<language>/ attempts to
+# write to /sandbox outside its job directory or to read a file only root
+# may read, and must print BLOCKED. Every program runs under each OCI
+# runtime of OCI_RUNTIMES (runc and gVisor's runsc by default); a runtime
+# the Docker daemon does not have, or a language in languages/ without
+# programs or a runtime entry, fails the suite.
+#
+# The process backend and the pooled runners themselves are covered by
+# the integration tests: go test -tags integration -run TestIsolation ./pkg/sandbox/
+#
+# Usage: SANDBOX_UID=10001 SANDBOX_GID=10001 OCI_RUNTIMES="runc runsc" scripts/isolation_suite.sh [language...]
+
+set -u
+
+ROOT="$(cd "$(dirname "$0")/.." && pwd)"
+SUITE="$ROOT/scripts/testdata/isolation"
+JOB_UID="${SANDBOX_UID:-10001}"
+JOB_GID="${SANDBOX_GID:-$JOB_UID}"
+OCI_RUNTIMES="${OCI_RUNTIMES:-runc runsc}"
+
+# language → image | seccomp profile | command (SRC is the program file)
+declare -A RUNTIMES=(
+  [c]="gcc:latest|native|gcc -O2 -o prog \$SRC && ./prog"
+  [cpp]="gcc:latest|native|g++ -O2 -o prog \$SRC && ./prog"
+  [go]="golang:1.23-alpine|native|GOCACHE=/tmp/.gocache CGO_ENABLED=0 go build -o prog \$SRC && ./prog"
+  [rust]="rust:1.83-alpine|native|rustc -O --edition 2021 -o prog \$SRC && ./prog"
+  [python]="python:3.12-alpine|interpreter|python3 \$SRC"
+  [ruby]="ruby:3.3-alpine|interpreter|ruby \$SRC"
+  [javascript]="node:22-alpine|interpreter|node \$SRC"
+  [typescript]="neuron-typescript:5.6|interpreter|tsc --strict --target es2022 --module commonjs --typeRoots /usr/local/lib/node_modules/@types --types node --outDir . \$SRC && node \${SRC%.ts}.js"
+  [java]="eclipse-temurin:21-jdk-alpine|jvm|java \$SRC"
+  [kotlin]="neuron-kotlin:2.0|jvm|kotlinc -nowarn \$SRC -include-runtime -d prog.jar && java -jar prog.jar"
+)
+
+languages=("$@")
+if [ ${#languages[@]} -eq 0 ]; then
+  languages=($(sed -n 's/^name: *//p' "$ROOT"/languages/*.y*ml | sort))
+fi
+
+stage="$(mktemp -d)"
+trap 'rm -rf "$stage"' EXIT
+
+failures=0
+
+for lang in "${languages[@]}"; do
+  if [ -z "${RUNTIMES[$lang]:-}" ]; then
+    echo "FAIL $lang: no runtime entry in $(basename "$0")"
+    failures=$((failures + 1))
+    continue
+  fi
+  if ! ls "$SUITE/$lang"/* >/dev/null 2>&1; then
+    echo "FAIL $lang: no programs in $SUITE/$lang"
+    failures=$((failures + 1))
+    continue
+  fi
+  IFS='|' read -r image profile cmd <<< "${RUNTIMES[$lang]}"
+
+  for oci in $OCI_RUNTIMES; do
+    for file in "$SUITE/$lang"/*; do
+      name="$(basename "$file")"
+
+      cid="$(docker run -d --rm \
+        --runtime "$oci" \
+        --network none \
+        --read-only \
+        --tmpfs /tmp:rw,noexec,nosuid,size=64m \
+        --mount 'type=volume,dst=/sandbox,volume-driver=local,volume-opt=type=tmpfs,volume-opt=device=tmpfs,"volume-opt=o=size=64m,mode=0755,nosuid,nodev"' \
+        --cap-drop ALL \
+        --security-opt no-new-privileges:true \
+        --security-opt "seccomp=$ROOT/security/seccomp/$profile.json" \
+        --user "$JOB_UID:$JOB_GID" \
+        -e HOME=/tmp \
+        "$image" sleep infinity)" || {
+        echo "FAIL $lang/$name ($oci): container did not start"
+        failures=$((failures + 1))
+        continue
+      }
+
+      # Same layout and modes as the runner's job archive
+      rm -rf "$stage/job_suite"
+      mkdir "$stage/job_suite"
+      cp "$file" "$stage/job_suite/$name"
+      chmod 0700 "$stage/job_suite"
+      chmod 0600 "$stage/job_suite/$name"
+      tar -C "$stage" --owner="$JOB_UID" --group="$JOB_GID" --numeric-owner -cf - job_suite |
+        docker cp - "$cid:/sandbox"
+
+      if ! docker exec "$cid" sh -c 'touch /sandbox/job_suite/own' >/dev/null 2>&1; then
+        echo "FAIL $lang/$name ($oci): job directory is not writable by the job user"
+        failures=$((failures + 1))
+        docker rm -f "$cid" >/dev/null
+        continue
+      fi
+
+      output="$(docker exec -e SRC="$name" "$cid" \
+        sh -c "cd /sandbox/job_suite && $cmd" 2>&1 | tail -1)"
+      docker rm -f "$cid" >/dev/null
+
+      if [ "$output" = "BLOCKED" ]; then
+        echo "PASS $lang/$name ($oci)"
+      else
+        echo "FAIL $lang/$name ($oci): $output"
+        failures=$((failures + 1))
+      fi
+    done
+  done
+done
+
+if [ "$failures" -gt 0 ]; then
+  echo "$failures isolation check(s) failed"
+  exit 1
+fi
+echo "All isolation checks passed"
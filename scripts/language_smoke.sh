@@ -0,0 +1,80 @@
+#!/bin/bash
+#
+# End-to-end smoke test for language runtimes.
+#
+# Submits every program under scripts/testdata/languages/<language>/ to a running
+# API + worker and checks the reported error type:
+#
+#   ok.*             → no error
+#   compile_error.*  → CompilationError
+#   runtime_error.*  → RuntimeError
+#   tle.*            → TLE
+#
+# Usage: API_URL=http://localhost:8080 API_KEY=nr_live_... scripts/language_smoke.sh [language...]
+# Requires curl and jq.
+
+set -u
+
+API_URL="${API_URL:-http://localhost:8080}"
+API_KEY="${API_KEY:?API_KEY is required}"
+FIXTURES="$(dirname "$0")/testdata/languages"
+
+languages=("$@")
+if [ ${#languages[@]} -eq 0 ]; then
+  languages=($(ls "$FIXTURES"))
+fi
+
+expected_for() {
+  case "$1" in
+    ok) echo "null" ;;
+    compile_error) echo "CompilationError" ;;
+    runtime_error) echo "RuntimeError" ;;
+    tle) echo "TLE" ;;
+  esac
+}
+
+failures=0
+
+for lang in "${languages[@]}"; do
+  for file in "$FIXTURES/$lang"/*; do
+    name="$(basename "${file%.*}")"
+    expected="$(expected_for "$name")"
+
+    body="$(jq -n --arg language "$lang" --rawfile code "$file" '{language: $language, code: $code, input: ""}')"
+    job_id="$(curl -s -X POST "$API_URL/api/v1/runner/submit" \
+      -H "Content-Type: application/json" \
+      -H "X-API-Key: $API_KEY" \
+      -d "$body" | jq -r '.data.jobId')"
+
+    if [ -z "$job_id" ] || [ "$job_id" = "null" ]; then
+      echo "FAIL $lang/$name: submission rejected"
+      failures=$((failures + 1))
+      continue
+    fi
+
+    # Poll until the job leaves queued/running
+    result=""
+    for _ in $(seq 1 60); do
+      result="$(curl -s "$API_URL/api/v1/runner/$job_id/result" -H "X-API-Key: $API_KEY")"
+      status="$(echo "$result" | jq -r '.data.status')"
+      if [ "$status" != "queued" ] && [ "$status" != "running" ]; then
+        break
+      fi
+      sleep 1
+    done
+
+    actual="$(echo "$result" | jq -r '.data.sandboxErrorType')"
+    if [ "$actual" = "$expected" ]; then
+      echo "PASS $lang/$name"
+    else
+      echo "FAIL $lang/$name: expected $expected, got $actual"
+      failures=$((failures + 1))
+    fi
+  done
+done
+
+if [ "$failures" -gt 0 ]; then
+  echo "$failures check(s) failed"
+  exit 1
+fi
+echo "All checks passed"
@@ -0,0 +1,121 @@
+#!/bin/bash
+#
+# Runs every program under scripts/testdata/malicious/<language>/ in a
+# container with the language's image, seccomp / AppArmor profiles and
+# the process, memory and file limits of the pools. Each program attempts
+# a forbidden operation and must print BLOCKED, or be killed by the memory
+# limit (exit 137).
+#
+# Every language in languages/ needs a runtime entry below and at least
+# a fork bomb, a memory bomb, a file system escape and a socket program;
+# a language without them fails the suite.
+#
+# The API validator is bypassed on purpose: this checks the kernel-level
+# layer on its own.
+#
+# Usage: scripts/malicious_suite.sh [language...]
+
+set -u
+
+ROOT="$(cd "$(dirname "$0")/.." && pwd)"
+SUITE="$ROOT/scripts/testdata/malicious"
+
+# Programs every language must have, by file name prefix (case and
+# underscores ignored)
+ATTACKS=(fork_bomb memory_bomb fs_escape socket)
+
+# language → image | seccomp profile | memory MB | command (SRC is the program path)
+declare -A RUNTIMES=(
+  [c]="gcc:latest|native|256|gcc -O2 -o /tmp/prog \$SRC && /tmp/prog"
+  [cpp]="gcc:latest|native|256|g++ -O2 -o /tmp/prog \$SRC && /tmp/prog"
+  [go]="golang:1.23-alpine|native|512|GOCACHE=/tmp/.gocache go run \$SRC"
+  [rust]="rust:1.83-alpine|native|512|rustc -O --edition 2021 -o /tmp/prog \$SRC && /tmp/prog"
+  [python]="python:3.12-alpine|interpreter|256|python3 \$SRC"
+  [ruby]="ruby:3.3-alpine|interpreter|256|ruby \$SRC"
+  [javascript]="node:22-alpine|interpreter|256|node \$SRC"
+  [typescript]="neuron-typescript:5.6|interpreter|384|cp \$SRC /tmp/prog.ts && tsc --strict --target es2022 --module commonjs --typeRoots /usr/local/lib/node_modules/@types --types node --outDir /tmp /tmp/prog.ts && node /tmp/prog.js"
+  [java]="eclipse-temurin:21-jdk-alpine|jvm|256|java \$SRC"
+  [kotlin]="neuron-kotlin:2.0|jvm|768|kotlinc -nowarn \$SRC -include-runtime -d /tmp/prog.jar && java -jar /tmp/prog.jar"
+)
+
+# has_program DIR ATTACK reports whether DIR holds a program for ATTACK.
+has_program() {
+  local want="${2//_/}" file name
+  for file in "$1"/*; do
+    [ -f "$file" ] || continue
+    name="$(basename "${file%.*}")"
+    name="${name//_/}"
+    if [[ "${name,,}" == "$want"* ]]; then
+      return 0
+    fi
+  done
+  return 1
+}
+
+apparmor_opt=()
+if [ "$(cat /sys/module/apparmor/parameters/enabled 2>/dev/null)" = "Y" ]; then
+  apparmor_opt=(--security-opt apparmor=neuron-sandbox)
+fi
+
+languages=("$@")
+if [ ${#languages[@]} -eq 0 ]; then
+  languages=($(sed -n 's/^name: *//p' "$ROOT"/languages/*.y*ml | sort))
+fi
+
+failures=0
+
+for lang in "${languages[@]}"; do
+  if [ -z "${RUNTIMES[$lang]:-}" ]; then
+    echo "FAIL $lang: no runtime entry in $(basename "$0")"
+    failures=$((failures + 1))
+    continue
+  fi
+  missing=0
+  for attack in "${ATTACKS[@]}"; do
+    if ! has_program "$SUITE/$lang" "$attack"; then
+      echo "FAIL $lang: no $attack program in $SUITE/$lang"
+      missing=1
+    fi
+  done
+  if [ "$missing" -ne 0 ]; then
+    failures=$((failures + 1))
+    continue
+  fi
+
+  IFS='|' read -r image profile memory cmd <<< "${RUNTIMES[$lang]}"
+
+  for file in "$SUITE/$lang"/*; do
+    name="$(basename "$file")"
+    output="$(docker run --rm \
+      --network none \
+      --read-only \
+      --tmpfs /tmp:rw,exec,size=256m \
+      --memory "${memory}m" \
+      --memory-swap "${memory}m" \
+      --pids-limit 100 \
+      --ulimit nofile=64:64 \
+      --cap-drop ALL \
+      --security-opt no-new-privileges:true \
+      --security-opt "seccomp=$ROOT/security/seccomp/$profile.json" \
+      "${apparmor_opt[@]}" \
+      -e HOME=/tmp \
+      -e SRC="/src/$name" \
+      -v "$file:/src/$name:ro" \
+      "$image" sh -c "$cmd" 2>&1)"
+    status=$?
+    output="$(tail -1 <<< "$output")"
+
+    if [ "$output" = "BLOCKED" ] || [ "$status" -eq 137 ]; then
+      echo "PASS $lang/$name"
+    else
+      echo "FAIL $lang/$name: $output"
+      failures=$((failures + 1))
+    fi
+  done
+done
+
+if [ "$failures" -gt 0 ]; then
+  echo "$failures check(s) failed"
+  exit 1
+fi
+echo "All malicious programs blocked"
@@ -0,0 +1,24 @@
+#!/bin/bash
+#
+# Lists syscalls denied by sandbox seccomp profiles.
+#
+# Requires the worker to run with SECCOMP_AUDIT=true, which makes the
+# kernel log every denial as an audit record of type 1326.
+#
+# Usage: scripts/seccomp_audit.sh [since]   (default: "1 hour ago")
+
+SINCE="${1:-1 hour ago}"
+
+journalctl -k --since "$SINCE" --no-pager 2>/dev/null \
+  | grep -E 'type=1326|audit: type=1326' \
+  | grep -oE 'comm="[^"]*"|syscall=[0-9]+' \
+  | paste -d' ' - - \
+  | while read -r comm syscall; do
+      nr="${syscall#syscall=}"
+      name="$nr"
+      if command -v ausyscall >/dev/null 2>&1; then
+        name="$(ausyscall "$nr" 2>/dev/null || echo "$nr")"
+      fi
+      echo "$comm $name"
+    done \
+  | sort | uniq -c | sort -rn
@@ -0,0 +1,9 @@
+// Reads a file only root may read.
+#include <fcntl.h>
+#include <stdio.h>
+
+int main(void) {
+    int fd = open("/etc/shadow", O_RDONLY);
+    puts(fd < 0 ? "BLOCKED" : "ALLOWED");
+    return 0;
+}
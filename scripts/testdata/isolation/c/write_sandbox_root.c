@@ -0,0 +1,9 @@
+// Creates a directory next to the job directory, where another job's files would live.
+#include <stdio.h>
+#include <sys/stat.h>
+
+int main(void) {
+    int r = mkdir("/sandbox/job_other", 0777);
+    puts(r < 0 ? "BLOCKED" : "ALLOWED");
+    return 0;
+}
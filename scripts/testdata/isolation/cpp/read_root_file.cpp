@@ -0,0 +1,9 @@
+// Reads a file only root may read.
+#include <fstream>
+#include <iostream>
+
+int main() {
+    std::ifstream f("/etc/shadow");
+    std::cout << (f ? "ALLOWED" : "BLOCKED") << std::endl;
+    return 0;
+}
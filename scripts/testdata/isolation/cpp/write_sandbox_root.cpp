@@ -0,0 +1,11 @@
+// Creates a file next to the job directory, where another job's files would live.
+#include <fstream>
+#include <iostream>
+
+int main() {
+    std::ofstream f("/sandbox/job_other");
+    f << "x";
+    f.close();
+    std::cout << (f.fail() ? "BLOCKED" : "ALLOWED") << std::endl;
+    return 0;
+}
@@ -0,0 +1,15 @@
+// Reads a file only root may read.
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+func main() {
+	if _, err := os.ReadFile("/etc/shadow"); err != nil {
+		fmt.Println("BLOCKED")
+		return
+	}
+	fmt.Println("ALLOWED")
+}
@@ -0,0 +1,15 @@
+// Creates a file next to the job directory, where another job's files would live.
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+func main() {
+	if err := os.WriteFile("/sandbox/job_other", []byte("x"), 0644); err != nil {
+		fmt.Println("BLOCKED")
+		return
+	}
+	fmt.Println("ALLOWED")
+}
@@ -0,0 +1,14 @@
+// Reads a file only root may read.
+import java.nio.file.Files;
+import java.nio.file.Path;
+
+public class Main {
+    public static void main(String[] args) {
+        try {
+            Files.readAllBytes(Path.of("/etc/shadow"));
+            System.out.println("ALLOWED");
+        } catch (Exception e) {
+            System.out.println("BLOCKED");
+        }
+    }
+}
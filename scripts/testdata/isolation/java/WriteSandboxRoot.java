@@ -0,0 +1,14 @@
+// Creates a file next to the job directory, where another job's files would live.
+import java.nio.file.Files;
+import java.nio.file.Path;
+
+public class Main {
+    public static void main(String[] args) {
+        try {
+            Files.writeString(Path.of("/sandbox/job_other"), "x");
+            System.out.println("ALLOWED");
+        } catch (Exception e) {
+            System.out.println("BLOCKED");
+        }
+    }
+}
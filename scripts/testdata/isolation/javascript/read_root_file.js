@@ -0,0 +1,9 @@
+// Reads a file only root may read.
+const fs = require("fs");
+
+try {
+  fs.readFileSync("/etc/shadow");
+  console.log("ALLOWED");
+} catch (e) {
+  console.log("BLOCKED");
+}
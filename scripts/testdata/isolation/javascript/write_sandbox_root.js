@@ -0,0 +1,9 @@
+// Creates a file next to the job directory, where another job's files would live.
+const fs = require("fs");
+
+try {
+  fs.writeFileSync("/sandbox/job_other", "x");
+  console.log("ALLOWED");
+} catch (e) {
+  console.log("BLOCKED");
+}
@@ -0,0 +1,11 @@
+// Reads a file only root may read.
+import java.io.File
+
+fun main() {
+    try {
+        File("/etc/shadow").readBytes()
+        println("ALLOWED")
+    } catch (e: Exception) {
+        println("BLOCKED")
+    }
+}
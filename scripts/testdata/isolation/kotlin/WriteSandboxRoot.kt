@@ -0,0 +1,11 @@
+// Creates a file next to the job directory, where another job's files would live.
+import java.io.File
+
+fun main() {
+    try {
+        File("/sandbox/job_other").writeText("x")
+        println("ALLOWED")
+    } catch (e: Exception) {
+        println("BLOCKED")
+    }
+}
@@ -0,0 +1,7 @@
+# Reads a file only root may read.
+try:
+    with open("/etc/shadow") as f:
+        f.read()
+    print("ALLOWED")
+except OSError:
+    print("BLOCKED")
@@ -0,0 +1,7 @@
+# Creates a file next to the job directory, where another job's files would live.
+try:
+    with open("/sandbox/job_other", "w") as f:
+        f.write("x")
+    print("ALLOWED")
+except OSError:
+    print("BLOCKED")
@@ -0,0 +1,7 @@
+# Reads a file only root may read.
+begin
+  File.read("/etc/shadow")
+  puts "ALLOWED"
+rescue SystemCallError
+  puts "BLOCKED"
+end
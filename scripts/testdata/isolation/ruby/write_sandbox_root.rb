@@ -0,0 +1,7 @@
+# Creates a file next to the job directory, where another job's files would live.
+begin
+  File.write("/sandbox/job_other", "x")
+  puts "ALLOWED"
+rescue SystemCallError
+  puts "BLOCKED"
+end
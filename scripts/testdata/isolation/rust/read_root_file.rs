@@ -0,0 +1,9 @@
+// Reads a file only root may read.
+use std::fs;
+
+fn main() {
+    match fs::read("/etc/shadow") {
+        Ok(_) => println!("ALLOWED"),
+        Err(_) => println!("BLOCKED"),
+    }
+}
@@ -0,0 +1,9 @@
+// Creates a file next to the job directory, where another job's files would live.
+use std::fs;
+
+fn main() {
+    match fs::write("/sandbox/job_other", "x") {
+        Ok(_) => println!("ALLOWED"),
+        Err(_) => println!("BLOCKED"),
+    }
+}
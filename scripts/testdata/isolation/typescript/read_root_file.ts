@@ -0,0 +1,9 @@
+// Reads a file only root may read.
+import { readFileSync } from "fs";
+
+try {
+  readFileSync("/etc/shadow");
+  console.log("ALLOWED");
+} catch {
+  console.log("BLOCKED");
+}
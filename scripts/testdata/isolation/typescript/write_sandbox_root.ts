@@ -0,0 +1,9 @@
+// Creates a file next to the job directory, where another job's files would live.
+import { writeFileSync } from "fs";
+
+try {
+  writeFileSync("/sandbox/job_other", "x");
+  console.log("ALLOWED");
+} catch {
+  console.log("BLOCKED");
+}
@@ -0,0 +1,9 @@
+package main
+
+import "fmt"
+
+func main() {
+	var values []int
+	i := len(values) + 4
+	fmt.Println(values[i])
+}
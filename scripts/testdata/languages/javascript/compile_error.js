@@ -0,0 +1,2 @@
+function greet( {
+  console.log("never closed");
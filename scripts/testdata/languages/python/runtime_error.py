@@ -0,0 +1 @@
+raise RuntimeError("boom")
@@ -0,0 +1 @@
+puts "Hello from Ruby"
@@ -0,0 +1 @@
+raise "boom"
@@ -0,0 +1,4 @@
+fn main() {
+    let values: Vec<i32> = Vec::new();
+    println!("{}", values[values.len() + 4]);
+}
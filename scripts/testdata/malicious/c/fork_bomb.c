@@ -0,0 +1,19 @@
+// Forks until the process limit stops it.
+#include <stdio.h>
+#include <unistd.h>
+
+int main(void) {
+    for (int i = 0; i < 1000; i++) {
+        pid_t pid = fork();
+        if (pid < 0) {
+            puts("BLOCKED");
+            return 0;
+        }
+        if (pid == 0) {
+            sleep(60);
+            _exit(0);
+        }
+    }
+    puts("ALLOWED");
+    return 0;
+}
@@ -0,0 +1,14 @@
+// Creates a file in /etc, outside the writable job directory and /tmp.
+#include <stdio.h>
+
+int main(void) {
+    FILE *f = fopen("/etc/neuron_escape", "w");
+    if (f == NULL) {
+        puts("BLOCKED");
+        return 0;
+    }
+    fclose(f);
+    remove("/etc/neuron_escape");
+    puts("ALLOWED");
+    return 0;
+}
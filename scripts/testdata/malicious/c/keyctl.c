@@ -0,0 +1,10 @@
+// Adds a key to the kernel keyring, which is not namespaced.
+#include <stdio.h>
+#include <sys/syscall.h>
+#include <unistd.h>
+
+int main(void) {
+    long r = syscall(SYS_add_key, "user", "neuron", "secret", 6, -2 /* KEY_SPEC_PROCESS_KEYRING */);
+    puts(r < 0 ? "BLOCKED" : "ALLOWED");
+    return 0;
+}
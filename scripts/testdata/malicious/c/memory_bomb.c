@@ -0,0 +1,17 @@
+// Allocates and touches 8 GiB; the memory limit must fail or kill it.
+#include <stdio.h>
+#include <stdlib.h>
+#include <string.h>
+
+int main(void) {
+    for (int i = 0; i < 128; i++) {
+        char *p = malloc(64 << 20);
+        if (p == NULL) {
+            puts("BLOCKED");
+            return 0;
+        }
+        memset(p, 1, 64 << 20);
+    }
+    puts("ALLOWED");
+    return 0;
+}
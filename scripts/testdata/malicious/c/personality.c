@@ -0,0 +1,9 @@
+// Disables ASLR through personality().
+#include <stdio.h>
+#include <sys/personality.h>
+
+int main(void) {
+    int r = personality(ADDR_NO_RANDOMIZE);
+    puts(r < 0 ? "BLOCKED" : "ALLOWED");
+    return 0;
+}
@@ -0,0 +1,9 @@
+// Asks to be traced, the first step of most ptrace-based escapes.
+#include <stdio.h>
+#include <sys/ptrace.h>
+
+int main(void) {
+    long r = ptrace(PTRACE_TRACEME, 0, NULL, NULL);
+    puts(r < 0 ? "BLOCKED" : "ALLOWED");
+    return 0;
+}
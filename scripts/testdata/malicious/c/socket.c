@@ -0,0 +1,9 @@
+// Opens a TCP socket; containers have no network, but socket() itself must be denied.
+#include <stdio.h>
+#include <sys/socket.h>
+
+int main(void) {
+    int fd = socket(AF_INET, SOCK_STREAM, 0);
+    puts(fd < 0 ? "BLOCKED" : "ALLOWED");
+    return 0;
+}
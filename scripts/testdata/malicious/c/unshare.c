@@ -0,0 +1,10 @@
+// Creates a user namespace to regain capabilities inside it.
+#define _GNU_SOURCE
+#include <sched.h>
+#include <stdio.h>
+
+int main(void) {
+    int r = unshare(CLONE_NEWUSER | CLONE_NEWNS);
+    puts(r < 0 ? "BLOCKED" : "ALLOWED");
+    return 0;
+}
@@ -0,0 +1,19 @@
+// Forks until the process limit stops it.
+#include <iostream>
+#include <unistd.h>
+
+int main() {
+    for (int i = 0; i < 1000; i++) {
+        pid_t pid = fork();
+        if (pid < 0) {
+            std::cout << "BLOCKED" << std::endl;
+            return 0;
+        }
+        if (pid == 0) {
+            sleep(60);
+            _exit(0);
+        }
+    }
+    std::cout << "ALLOWED" << std::endl;
+    return 0;
+}
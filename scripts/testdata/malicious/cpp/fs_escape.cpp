@@ -0,0 +1,16 @@
+// Creates a file in /etc, outside the writable job directory and /tmp.
+#include <cstdio>
+#include <fstream>
+#include <iostream>
+
+int main() {
+    std::ofstream f("/etc/neuron_escape");
+    if (!f) {
+        std::cout << "BLOCKED" << std::endl;
+        return 0;
+    }
+    f.close();
+    std::remove("/etc/neuron_escape");
+    std::cout << "ALLOWED" << std::endl;
+    return 0;
+}
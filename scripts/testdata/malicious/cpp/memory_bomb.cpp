@@ -0,0 +1,19 @@
+// Allocates and touches 8 GiB; the memory limit must fail or kill it.
+#include <iostream>
+#include <new>
+#include <vector>
+
+int main() {
+    std::vector<std::vector<char>> chunks;
+    try {
+        for (int i = 0; i < 128; i++) {
+            chunks.emplace_back(64 << 20, 1);
+        }
+    } catch (const std::bad_alloc &) {
+        chunks.clear();
+        std::cout << "BLOCKED" << std::endl;
+        return 0;
+    }
+    std::cout << "ALLOWED" << std::endl;
+    return 0;
+}
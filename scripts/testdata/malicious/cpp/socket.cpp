@@ -0,0 +1,9 @@
+// Opens a TCP socket; containers have no network, but socket() itself must be denied.
+#include <iostream>
+#include <sys/socket.h>
+
+int main() {
+    int fd = socket(AF_INET, SOCK_STREAM, 0);
+    std::cout << (fd < 0 ? "BLOCKED" : "ALLOWED") << std::endl;
+    return 0;
+}
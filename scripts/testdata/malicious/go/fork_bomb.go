@@ -0,0 +1,17 @@
+// Starts processes until the process limit stops it.
+package main
+
+import (
+	"fmt"
+	"os/exec"
+)
+
+func main() {
+	for i := 0; i < 1000; i++ {
+		if err := exec.Command("sleep", "60").Start(); err != nil {
+			fmt.Println("BLOCKED")
+			return
+		}
+	}
+	fmt.Println("ALLOWED")
+}
@@ -0,0 +1,18 @@
+// Creates a file in /etc, outside the writable job directory and /tmp.
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+func main() {
+	f, err := os.Create("/etc/neuron_escape")
+	if err != nil {
+		fmt.Println("BLOCKED")
+		return
+	}
+	f.Close()
+	os.Remove("/etc/neuron_escape")
+	fmt.Println("ALLOWED")
+}
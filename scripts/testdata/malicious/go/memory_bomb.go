@@ -0,0 +1,16 @@
+// Allocates and touches 8 GiB; the memory limit must fail or kill it.
+package main
+
+import "fmt"
+
+func main() {
+	var chunks [][]byte
+	for i := 0; i < 128; i++ {
+		chunk := make([]byte, 64<<20)
+		for j := range chunk {
+			chunk[j] = 1
+		}
+		chunks = append(chunks, chunk)
+	}
+	fmt.Println("ALLOWED", len(chunks))
+}
@@ -0,0 +1,15 @@
+// Calls ptrace(PTRACE_TRACEME) with a raw syscall.
+package main
+
+import (
+	"fmt"
+	"syscall"
+)
+
+func main() {
+	if _, _, errno := syscall.RawSyscall(syscall.SYS_PTRACE, syscall.PTRACE_TRACEME, 0, 0); errno != 0 {
+		fmt.Println("BLOCKED")
+		return
+	}
+	fmt.Println("ALLOWED")
+}
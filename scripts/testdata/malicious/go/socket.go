@@ -0,0 +1,15 @@
+// Opens a TCP socket with a raw syscall.
+package main
+
+import (
+	"fmt"
+	"syscall"
+)
+
+func main() {
+	if _, err := syscall.Socket(syscall.AF_INET, syscall.SOCK_STREAM, 0); err != nil {
+		fmt.Println("BLOCKED")
+		return
+	}
+	fmt.Println("ALLOWED")
+}
@@ -0,0 +1,13 @@
+// Starts processes until the process limit stops it.
+public class ForkBomb {
+    public static void main(String[] args) {
+        try {
+            for (int i = 0; i < 1000; i++) {
+                new ProcessBuilder("sleep", "60").start();
+            }
+            System.out.println("ALLOWED");
+        } catch (Exception e) {
+            System.out.println("BLOCKED");
+        }
+    }
+}
@@ -0,0 +1,15 @@
+// Creates a file in /etc, outside the writable job directory and /tmp.
+import java.io.File;
+
+public class FsEscape {
+    public static void main(String[] args) {
+        File f = new File("/etc/neuron_escape");
+        try {
+            f.createNewFile();
+            f.delete();
+            System.out.println("ALLOWED");
+        } catch (Exception e) {
+            System.out.println("BLOCKED");
+        }
+    }
+}
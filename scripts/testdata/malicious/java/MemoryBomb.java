@@ -0,0 +1,19 @@
+// Allocates 8 GiB; the memory limit must fail or kill it.
+import java.util.ArrayList;
+import java.util.List;
+
+public class MemoryBomb {
+    public static void main(String[] args) {
+        List<byte[]> chunks = new ArrayList<>();
+        try {
+            for (int i = 0; i < 128; i++) {
+                chunks.add(new byte[64 << 20]);
+            }
+        } catch (OutOfMemoryError e) {
+            chunks.clear();
+            System.out.println("BLOCKED");
+            return;
+        }
+        System.out.println("ALLOWED");
+    }
+}
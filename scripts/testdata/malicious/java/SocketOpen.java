@@ -0,0 +1,10 @@
+// Starts a TCP server.
+public class SocketOpen {
+    public static void main(String[] args) {
+        try (java.net.ServerSocket s = new java.net.ServerSocket(0)) {
+            System.out.println("ALLOWED");
+        } catch (Exception e) {
+            System.out.println("BLOCKED");
+        }
+    }
+}
@@ -0,0 +1,10 @@
+// Creates a file in /etc, outside the writable job directory and /tmp.
+const fs = require("fs");
+
+try {
+  fs.writeFileSync("/etc/neuron_escape", "");
+  fs.unlinkSync("/etc/neuron_escape");
+  console.log("ALLOWED");
+} catch {
+  console.log("BLOCKED");
+}
@@ -0,0 +1,12 @@
+// Allocates and fills 8 GiB; the memory limit must fail or kill it.
+const chunks = [];
+try {
+  for (let i = 0; i < 128; i++) {
+    chunks.push(Buffer.alloc(64 << 20, 1));
+  }
+} catch {
+  chunks.length = 0;
+  console.log("BLOCKED");
+  process.exit(0);
+}
+console.log("ALLOWED");
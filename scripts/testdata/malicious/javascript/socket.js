@@ -0,0 +1,9 @@
+// Starts a TCP server.
+const net = require("net");
+
+const server = net.createServer();
+server.on("error", () => console.log("BLOCKED"));
+server.listen(0, () => {
+  console.log("ALLOWED");
+  server.close();
+});
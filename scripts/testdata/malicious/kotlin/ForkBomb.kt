@@ -0,0 +1,11 @@
+// Starts processes until the process limit stops it.
+fun main() {
+    try {
+        repeat(1000) {
+            ProcessBuilder("sleep", "60").start()
+        }
+        println("ALLOWED")
+    } catch (e: Exception) {
+        println("BLOCKED")
+    }
+}
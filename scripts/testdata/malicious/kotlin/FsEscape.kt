@@ -0,0 +1,13 @@
+// Creates a file in /etc, outside the writable job directory and /tmp.
+import java.io.File
+
+fun main() {
+    val f = File("/etc/neuron_escape")
+    try {
+        f.createNewFile()
+        f.delete()
+        println("ALLOWED")
+    } catch (e: Exception) {
+        println("BLOCKED")
+    }
+}
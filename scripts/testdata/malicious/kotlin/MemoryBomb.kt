@@ -0,0 +1,14 @@
+// Allocates 8 GiB; the memory limit must fail or kill it.
+fun main() {
+    val chunks = mutableListOf<ByteArray>()
+    try {
+        repeat(128) {
+            chunks.add(ByteArray(64 shl 20))
+        }
+    } catch (e: OutOfMemoryError) {
+        chunks.clear()
+        println("BLOCKED")
+        return
+    }
+    println("ALLOWED")
+}
@@ -0,0 +1,10 @@
+// Starts a TCP server.
+import java.net.ServerSocket
+
+fun main() {
+    try {
+        ServerSocket(0).use { println("ALLOWED") }
+    } catch (e: Exception) {
+        println("BLOCKED")
+    }
+}
@@ -0,0 +1,15 @@
+# Forks until the process limit stops it.
+import os
+import time
+
+for _ in range(1000):
+    try:
+        pid = os.fork()
+    except OSError:
+        print("BLOCKED")
+        break
+    if pid == 0:
+        time.sleep(60)
+        os._exit(0)
+else:
+    print("ALLOWED")
@@ -0,0 +1,9 @@
+# Creates a file in /etc, outside the writable job directory and /tmp.
+import os
+
+try:
+    open("/etc/neuron_escape", "w").close()
+    os.remove("/etc/neuron_escape")
+    print("ALLOWED")
+except OSError:
+    print("BLOCKED")
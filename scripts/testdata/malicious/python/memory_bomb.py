@@ -0,0 +1,9 @@
+# Allocates and fills 8 GiB; the memory limit must fail or kill it.
+chunks = []
+try:
+    for _ in range(128):
+        chunks.append(b"x" * (64 << 20))
+    print("ALLOWED")
+except MemoryError:
+    chunks.clear()
+    print("BLOCKED")
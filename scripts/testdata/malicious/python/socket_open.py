@@ -0,0 +1,8 @@
+# Opens a TCP socket.
+import socket
+
+try:
+    socket.socket(socket.AF_INET, socket.SOCK_STREAM)
+    print("ALLOWED")
+except OSError:
+    print("BLOCKED")
@@ -0,0 +1,12 @@
+# Forks until the process limit stops it.
+begin
+  1000.times do
+    fork do
+      sleep 60
+      exit!(0)
+    end
+  end
+  puts "ALLOWED"
+rescue SystemCallError, NotImplementedError
+  puts "BLOCKED"
+end
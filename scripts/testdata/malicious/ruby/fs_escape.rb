@@ -0,0 +1,8 @@
+# Creates a file in /etc, outside the writable job directory and /tmp.
+begin
+  File.write("/etc/neuron_escape", "")
+  File.delete("/etc/neuron_escape")
+  puts "ALLOWED"
+rescue SystemCallError
+  puts "BLOCKED"
+end
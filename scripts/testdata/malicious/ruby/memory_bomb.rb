@@ -0,0 +1,9 @@
+# Allocates and fills 8 GiB; the memory limit must fail or kill it.
+chunks = []
+begin
+  128.times { chunks << "x" * (64 << 20) }
+  puts "ALLOWED"
+rescue NoMemoryError
+  chunks.clear
+  puts "BLOCKED"
+end
@@ -0,0 +1,9 @@
+# Starts a TCP server.
+require "socket"
+
+begin
+  TCPServer.new("127.0.0.1", 0)
+  puts "ALLOWED"
+rescue SystemCallError
+  puts "BLOCKED"
+end
@@ -0,0 +1,12 @@
+// Starts processes until the process limit stops it.
+use std::process::Command;
+
+fn main() {
+    for _ in 0..1000 {
+        if Command::new("sleep").arg("60").spawn().is_err() {
+            println!("BLOCKED");
+            return;
+        }
+    }
+    println!("ALLOWED");
+}
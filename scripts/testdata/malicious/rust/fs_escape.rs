@@ -0,0 +1,12 @@
+// Creates a file in /etc, outside the writable job directory and /tmp.
+use std::fs;
+
+fn main() {
+    match fs::File::create("/etc/neuron_escape") {
+        Ok(_) => {
+            let _ = fs::remove_file("/etc/neuron_escape");
+            println!("ALLOWED");
+        }
+        Err(_) => println!("BLOCKED"),
+    }
+}
@@ -0,0 +1,15 @@
+// Allocates and touches 8 GiB; the memory limit must fail or kill it.
+fn main() {
+    let mut chunks: Vec<Vec<u8>> = Vec::new();
+    for _ in 0..128 {
+        let mut chunk = Vec::new();
+        if chunk.try_reserve_exact(64 << 20).is_err() {
+            chunks.clear();
+            println!("BLOCKED");
+            return;
+        }
+        chunk.resize(64 << 20, 1);
+        chunks.push(chunk);
+    }
+    println!("ALLOWED");
+}
@@ -0,0 +1,9 @@
+// Starts a TCP server.
+use std::net::TcpListener;
+
+fn main() {
+    match TcpListener::bind("127.0.0.1:0") {
+        Ok(_) => println!("ALLOWED"),
+        Err(_) => println!("BLOCKED"),
+    }
+}
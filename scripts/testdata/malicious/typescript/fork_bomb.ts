@@ -0,0 +1,19 @@
+// Starts processes until the process limit stops it.
+import { ChildProcess, spawn } from "child_process";
+
+for (let i = 0; i < 1000; i++) {
+  let child: ChildProcess;
+  try {
+    child = spawn("sleep", ["60"]);
+  } catch {
+    console.log("BLOCKED");
+    process.exit(0);
+  }
+  child.on("error", () => {});
+  if (child.pid === undefined) {
+    console.log("BLOCKED");
+    process.exit(0);
+  }
+}
+console.log("ALLOWED");
+process.exit(0);
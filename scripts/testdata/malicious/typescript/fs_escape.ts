@@ -0,0 +1,10 @@
+// Creates a file in /etc, outside the writable job directory and /tmp.
+import { unlinkSync, writeFileSync } from "fs";
+
+try {
+  writeFileSync("/etc/neuron_escape", "");
+  unlinkSync("/etc/neuron_escape");
+  console.log("ALLOWED");
+} catch {
+  console.log("BLOCKED");
+}
@@ -0,0 +1,9 @@
+// Starts a TCP server.
+import { createServer } from "net";
+
+const server = createServer();
+server.on("error", () => console.log("BLOCKED"));
+server.listen(0, () => {
+  console.log("ALLOWED");
+  server.close();
+});